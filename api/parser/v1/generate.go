@@ -0,0 +1,4 @@
+// Package parserv1 contains gRPC api of the parser generated from parser.proto
+package parserv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative parser.proto
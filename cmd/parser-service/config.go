@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/cmd/parser-service/internal/server"
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/confirmation"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/ratelimit"
+	subscriberpkg "github.com/veljkomatic/be-homework/pkg/subscriber"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
+)
+
+const (
+	// defaultBackfillDepth is the default number of the most recent blocks backfilled for newly subscribed address
+	defaultBackfillDepth = 1000
+	// defaultKeyRateLimit and defaultKeyBurst are the default rate limit of api key
+	defaultKeyRateLimit = 10
+	defaultKeyBurst     = 20
+	// defaultIPBurst is the number of requests of client ip address allowed at once, when ip rate limit is enabled
+	defaultIPBurst = 100
+)
+
+// Config is the configuration of the application, parsed from command line flags
+type Config struct {
+	Provider provider.Config
+	// WebSocketEndpoint is the websocket rpc endpoint used to subscribe to new heads, polling is used if empty
+	WebSocketEndpoint string
+	// TraceEndpoint is the rpc endpoint used to trace blocks for internal transfers, tracing is disabled if empty
+	TraceEndpoint string
+	// TraceMethod is the rpc method family of the trace endpoint: debug or trace
+	TraceMethod  provider.TraceMethod
+	Confirmation confirmation.Config
+	// StorageBackend is the backend of blocks, transactions and subscriptions storages: memory, bolt, sqlite or postgres
+	StorageBackend string
+	// DatabasePath is the path of the database file of bolt and sqlite storage backends
+	DatabasePath string
+	// DatabaseDSN is the connection string of postgres storage backend
+	DatabaseDSN string
+	// BackfillDepth is the number of the most recent blocks backfilled for newly subscribed address
+	BackfillDepth int64
+	// APIKeys are api keys of tenants configured at startup, more keys are created via admin endpoint.
+	// Without any key every caller is the default tenant.
+	APIKeys []*tenant.Key
+	// AdminKey is the key of admin endpoints, they are disabled if it is empty
+	AdminKey string
+	// KeyRate is the default rate limit of api keys, IPRate is the rate limit of every client ip address
+	KeyRate ratelimit.Rate
+	IPRate  ratelimit.Rate
+	// ClientIPHeader is the header with client ip address set by TrustedProxies, client ip is remote address if empty
+	ClientIPHeader string
+	TrustedProxies []*net.IPNet
+	// MaxSubscriptions is the default quota of addresses subscribed by tenant of api key, 0 is unlimited
+	MaxSubscriptions int
+	// FilterFalsePositiveRate is the false positive rate of bloom filter of subscribed addresses
+	FilterFalsePositiveRate float64
+	// AllowedOrigins are origins of browsers allowed to open websocket connection, * allows every origin.
+	// Without them only the same origin is allowed.
+	AllowedOrigins []string
+	// AllowPrivateWebhooks allows webhooks of loopback and private network hosts, e.g. for local development
+	AllowPrivateWebhooks bool
+}
+
+// parseConfig parses configuration from command line flags
+func parseConfig(args []string) Config {
+	flags := flag.NewFlagSet("parser-service", flag.ExitOnError)
+	defaultProviderConfig := provider.DefaultConfig()
+
+	rpcEndpoints := flags.String("rpc-endpoints", strings.Join(defaultProviderConfig.Endpoints, ","), "comma separated list of rpc endpoints")
+	rpcQuorum := flags.Int("rpc-quorum", defaultProviderConfig.Quorum, "number of rpc endpoints that have to agree on block number and hash")
+	wsEndpoint := flags.String("rpc-ws-endpoint", "", "websocket rpc endpoint to subscribe to new heads, polling is used if empty")
+	traceEndpoint := flags.String("rpc-trace-endpoint", "", "rpc endpoint with debug or trace api to find internal transfers, tracing is disabled if empty")
+	traceMethod := flags.String("rpc-trace-method", string(provider.DebugTraceMethod), "trace api of the trace endpoint: debug (debug_traceBlockByHash) or trace (trace_block)")
+	depth := flags.Int64("confirmation-depth", confirmationDepth, "number of blocks after which transaction is confirmed")
+	tag := flags.String("finality-tag", string(finalityTag), "block tag used to finalize transactions: finalized, safe or empty to disable")
+	storageBackend := flags.String("storage", MemoryStorageBackend, "storage backend: memory, bolt, sqlite or postgres")
+	databasePath := flags.String("db-path", "parser.db", "path of the database file of bolt and sqlite storage backends")
+	databaseDSN := flags.String("db-dsn", "", "connection string of postgres storage backend")
+	backfillDepth := flags.Int64("backfill-depth", defaultBackfillDepth, "number of the most recent blocks backfilled for newly subscribed address, 0 disables it")
+	apiKeys := flags.String("api-keys", "", "comma separated list of tenant:key pairs, api is open to everyone if there are no keys")
+	adminKey := flags.String("admin-key", "", "key of admin endpoints managing api keys, admin endpoints are disabled if empty")
+	keyRateLimit := flags.Float64("rate-limit", defaultKeyRateLimit, "default number of requests per second of api key, 0 disables it")
+	keyBurst := flags.Int("rate-limit-burst", defaultKeyBurst, "default number of requests of api key allowed at once")
+	ipRateLimit := flags.Float64("ip-rate-limit", 0, "number of requests per second of client ip address, 0 disables it")
+	ipBurst := flags.Int("ip-rate-limit-burst", defaultIPBurst, "number of requests of client ip address allowed at once")
+	clientIPHeader := flags.String("client-ip-header", "", "header with client ip address set by trusted proxies, e.g. X-Forwarded-For or X-Real-IP")
+	trustedProxies := flags.String("trusted-proxies", "", "comma separated list of ip addresses and CIDR networks of proxies trusted to set client ip header")
+	falsePositiveRate := flags.Float64("filter-false-positive-rate", subscriberpkg.DefaultFalsePositiveRate, "false positive rate of bloom filter of subscribed addresses, lower rate uses more memory")
+	maxSubscriptions := flags.Int("max-subscriptions", 0, "default quota of addresses subscribed by tenant of api key, 0 is unlimited")
+	allowedOrigins := flags.String("ws-allowed-origins", "", "comma separated list of origins allowed to open websocket connection, * allows every origin, only the same origin is allowed if empty")
+	allowPrivateWebhooks := flags.Bool("webhook-allow-private-hosts", false, "allow webhooks of loopback, private and link local hosts, e.g. for local development")
+	flags.Parse(args)
+
+	keys, err := tenant.ParseKeys(*apiKeys)
+	if err != nil {
+		log.Fatalln("Error parsing api keys:", err)
+	}
+	proxies, err := server.ParseTrustedProxies(splitList(*trustedProxies))
+	if err != nil {
+		log.Fatalln("Error parsing trusted proxies:", err)
+	}
+
+	return Config{
+		Provider:                provider.NewConfig(splitList(*rpcEndpoints), *rpcQuorum),
+		WebSocketEndpoint:       *wsEndpoint,
+		TraceEndpoint:           *traceEndpoint,
+		TraceMethod:             provider.TraceMethod(*traceMethod),
+		Confirmation:            confirmation.NewConfig(*depth, blockchain.BlockTag(*tag)),
+		StorageBackend:          *storageBackend,
+		DatabasePath:            *databasePath,
+		DatabaseDSN:             *databaseDSN,
+		BackfillDepth:           *backfillDepth,
+		APIKeys:                 keys,
+		AdminKey:                *adminKey,
+		KeyRate:                 ratelimit.Rate{PerSecond: *keyRateLimit, Burst: *keyBurst},
+		IPRate:                  ratelimit.Rate{PerSecond: *ipRateLimit, Burst: *ipBurst},
+		ClientIPHeader:          *clientIPHeader,
+		TrustedProxies:          proxies,
+		MaxSubscriptions:        *maxSubscriptions,
+		FilterFalsePositiveRate: *falsePositiveRate,
+		AllowedOrigins:          splitList(*allowedOrigins),
+		AllowPrivateWebhooks:    *allowPrivateWebhooks,
+	}
+}
+
+// splitList splits comma separated list and drops empty values
+func splitList(list string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
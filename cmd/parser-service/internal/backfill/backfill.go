@@ -0,0 +1,418 @@
+package backfill
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	filter "github.com/veljkomatic/be-homework/cmd/parser-service/internal/transaction_filter"
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/storage/block"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
+)
+
+const (
+	jobsBufferSize          = 100
+	maxConcurrentJobs       = 2
+	maxConcurrentJobBatches = 4
+	blocksBatchSize         = 20
+	maxRetries              = 3
+	retryDelay              = 2 * time.Second
+	// finishedJobRetention is how long a finished job can be queried before it is removed
+	finishedJobRetention = time.Hour
+	jobsPruneInterval    = time.Minute
+)
+
+var (
+	ErrJobNotFound = errors.New("backfill job not found")
+	ErrQueueFull   = errors.New("backfill queue is full")
+	// ErrNothingToBackfill is returned when no block is processed yet
+	ErrNothingToBackfill = errors.New("no processed blocks to backfill")
+)
+
+// Backfiller scans historical blocks for transactions of newly subscribed addresses
+type Backfiller interface {
+	// Start starts processing of queued backfill jobs
+	Start(ctx context.Context)
+	// Backfill queues backfill job of the address started by tenant of the caller, from given block up to the last processed block.
+	// If from block is not set, configured number of the most recent blocks is scanned,
+	// if it is zero, no job is queued and nil job is returned.
+	Backfill(ctx context.Context, address string, fromBlock *blockchain.BlockNumber) (*Job, error)
+	// GetJob returns the current state of backfill job
+	GetJob(ctx context.Context, id string) (*Job, error)
+	// Close closes the backfiller
+	Close(ctx context.Context)
+}
+
+var _ Backfiller = (*backfiller)(nil)
+
+type backfiller struct {
+	rpcProvider    provider.Provider
+	receiptFetcher *filter.ReceiptFetcher
+	// tracer is nil when tracing is disabled, internal transfers are not backfilled then
+	tracer                provider.Tracer
+	blockRepository       block.ReadOnlyBlockRepository
+	transactionRepository transaction.WriteRepository
+	transferRepository    transfer.WriteRepository
+	traceRepository       trace.WriteRepository
+	// filter matches transactions of subscriptions, backfilled transactions have to meet criteria of the subscription
+	filter subscriber.Filter
+	// depth is the number of the most recent blocks scanned when from block is not set
+	depth int64
+
+	queue chan *job
+	jobs  map[string]*job
+	mutex sync.RWMutex
+}
+
+func NewBackfiller(
+	rpcProvider provider.Provider,
+	tracer provider.Tracer,
+	blockRepository block.ReadOnlyBlockRepository,
+	transactionRepository transaction.WriteRepository,
+	transferRepository transfer.WriteRepository,
+	traceRepository trace.WriteRepository,
+	subscriptionFilter subscriber.Filter,
+	depth int64,
+) Backfiller {
+	return &backfiller{
+		rpcProvider:           rpcProvider,
+		receiptFetcher:        filter.NewReceiptFetcher(rpcProvider),
+		tracer:                tracer,
+		blockRepository:       blockRepository,
+		transactionRepository: transactionRepository,
+		transferRepository:    transferRepository,
+		traceRepository:       traceRepository,
+		filter:                subscriptionFilter,
+		depth:                 depth,
+		queue:                 make(chan *job, jobsBufferSize),
+		jobs:                  make(map[string]*job),
+	}
+}
+
+func (b *backfiller) Start(ctx context.Context) {
+	// semaphore to limit the number of concurrent jobs
+	jobSemaphore := make(chan struct{}, maxConcurrentJobs)
+	pruneTicker := time.NewTicker(jobsPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(jobSemaphore)
+			return
+		case <-pruneTicker.C:
+			b.pruneJobs(time.Now().Add(-finishedJobRetention))
+		case j := <-b.queue:
+			if j == nil {
+				continue
+			}
+			jobSemaphore <- struct{}{} // acquire a semaphore slot
+			go func(j *job) {
+				defer func() { <-jobSemaphore }() // release the semaphore slot
+				b.run(ctx, j)
+			}(j)
+		}
+	}
+}
+
+func (b *backfiller) Backfill(ctx context.Context, address string, fromBlock *blockchain.BlockNumber) (*Job, error) {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	toBlock, err := b.blockRepository.GetCurrentBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if fromBlock == nil && b.depth <= 0 {
+		// backfill of the most recent blocks is disabled
+		return nil, nil
+	}
+	if toBlock == blockchain.EarliestBlockNumber {
+		return nil, ErrNothingToBackfill
+	}
+	if fromBlock == nil {
+		from := toBlock - blockchain.BlockNumber(b.depth) + 1
+		fromBlock = &from
+	}
+	if *fromBlock < blockchain.EarliestBlockNumber {
+		from := blockchain.EarliestBlockNumber
+		fromBlock = &from
+	}
+	if *fromBlock > toBlock {
+		return nil, fmt.Errorf("from block %d is after the last processed block %d", *fromBlock, toBlock)
+	}
+
+	j := newJob(newJobID(), tenant.IDFromContext(ctx), parsedAddress, *fromBlock, toBlock)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	select {
+	case b.queue <- j:
+		b.jobs[j.id] = j
+	default:
+		return nil, ErrQueueFull
+	}
+	return j.snapshot(), nil
+}
+
+func (b *backfiller) GetJob(ctx context.Context, id string) (*Job, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	j, ok := b.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return j.snapshot(), nil
+}
+
+// pruneJobs removes jobs finished before the given time, so jobs do not accumulate in memory
+func (b *backfiller) pruneJobs(finishedBefore time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for id, j := range b.jobs {
+		if j.finishedBefore(finishedBefore) {
+			delete(b.jobs, id)
+		}
+	}
+}
+
+// run scans blocks of the job in batches with bounded concurrency
+func (b *backfiller) run(ctx context.Context, j *job) {
+	log.Printf("Backfilling address %s, blocks %d-%d.", j.address, j.fromBlock, j.toBlock)
+	j.start()
+
+	addressFilter := &addressFilter{address: j.address, filter: b.filter}
+	// The semaphore channel
+	batchSemaphore := make(chan struct{}, maxConcurrentJobBatches)
+
+	wg := sync.WaitGroup{}
+	for from := j.fromBlock; from <= j.toBlock; from += blocksBatchSize {
+		if ctx.Err() != nil {
+			break
+		}
+		to := from + blocksBatchSize - 1
+		if to > j.toBlock {
+			to = j.toBlock
+		}
+
+		wg.Add(1)
+		batchSemaphore <- struct{}{} // Acquire a semaphore slot
+
+		go func(from blockchain.BlockNumber, to blockchain.BlockNumber) {
+			defer wg.Done()
+			defer func() { <-batchSemaphore }() // Release a semaphore slot when we're done
+
+			matched, failed, err := b.backfillRange(ctx, addressFilter, from, to)
+			j.progress(int64(to-from+1), failed, int64(matched), err)
+		}(from, to)
+	}
+
+	wg.Wait()
+	close(batchSemaphore)
+	j.finish(ctx.Err())
+	log.Printf("Backfill of address %s finished.", j.address)
+}
+
+// backfillRange fetches blocks of the range and stores transactions, token transfers and internal transfers matching the filter,
+// it returns number of matched transactions and number of blocks of the range which could not be scanned completely
+func (b *backfiller) backfillRange(ctx context.Context, addressFilter *addressFilter, from blockchain.BlockNumber, to blockchain.BlockNumber) (int, int64, error) {
+	var blocks []*blockchain.Block
+	var err error
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		blocks, err = b.rpcProvider.GetBlocksByRange(ctx, from, to)
+		if err == nil {
+			break
+		}
+		log.Printf("Error fetching blocks %d-%d: %s. Retry %d/%d.", from, to, err, currentRetry+1, maxRetries)
+		time.Sleep(retryDelay)
+	}
+
+	// blocks fetched before the range failed are still stored, blocks which were not fetched are failed
+	failedBlocks := make(map[blockchain.Hash]struct{})
+	matched := make([]*transaction.AddressTransaction, 0)
+	for _, block := range blocks {
+		filtered := filter.FilterBlock(ctx, addressFilter, block)
+		creations := filter.ContractCreations(block)
+		b.attachReceipts(ctx, block, filtered, creations)
+		matched = append(matched, filtered...)
+		filteredCreations, creationsErr := filter.FilterContractCreations(ctx, addressFilter, block, creations)
+		if creationsErr != nil {
+			failedBlocks[block.Hash] = struct{}{}
+			if err == nil {
+				err = creationsErr
+			}
+		}
+		matched = append(matched, filteredCreations...)
+	}
+	notFetched := int64(to-from+1) - int64(len(blocks))
+	if len(matched) > 0 {
+		if err := b.transactionRepository.InsertTransactions(ctx, matched); err != nil {
+			return 0, int64(to - from + 1), err
+		}
+	}
+	if len(blocks) == 0 {
+		return 0, notFetched, err
+	}
+	if transfersErr := b.backfillTransfers(ctx, addressFilter, blocks); transfersErr != nil {
+		// token transfers of all blocks are fetched in a single call
+		for _, block := range blocks {
+			failedBlocks[block.Hash] = struct{}{}
+		}
+		if err == nil {
+			err = transfersErr
+		}
+	}
+	traced, tracesErr := b.backfillInternalTransfers(ctx, addressFilter, blocks)
+	if tracesErr != nil {
+		for _, block := range blocks[traced:] {
+			failedBlocks[block.Hash] = struct{}{}
+		}
+		if err == nil {
+			err = tracesErr
+		}
+	}
+	return len(matched), notFetched + int64(len(failedBlocks)), err
+}
+
+// backfillTransfers fetches token transfer logs of the backfilled address in the fetched blocks and stores token transfers
+// matching the filter, logs of other blocks at the same height, e.g. after reorg, are skipped
+func (b *backfiller) backfillTransfers(ctx context.Context, addressFilter *addressFilter, blocks []*blockchain.Block) error {
+	from := blockchain.NewBlockNumberBuilder().FromQuantity(blocks[0].Number).Value()
+	to := blockchain.NewBlockNumberBuilder().FromQuantity(blocks[len(blocks)-1].Number).Value()
+	// address is matched in indexed topics by the endpoint, logs of every sender and recipient position are fetched
+	// separately and merged, log returned by more filters, e.g. of transfer to itself, is kept once
+	logs := make([]*blockchain.Log, 0)
+	fetched := make(map[string]struct{})
+	for _, logFilter := range filter.AddressTransferLogFilters(addressFilter.address) {
+		logFilter.FromBlock = &from
+		logFilter.ToBlock = &to
+		var filterLogs []*blockchain.Log
+		var err error
+		for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+			if filterLogs, err = b.rpcProvider.GetLogs(ctx, logFilter); err == nil {
+				break
+			}
+			log.Printf("Error fetching token transfer logs of blocks %d-%d: %s. Retry %d/%d.", from, to, err, currentRetry+1, maxRetries)
+			time.Sleep(retryDelay)
+		}
+		if err != nil {
+			return err
+		}
+		for _, transferLog := range filterLogs {
+			key := transferLog.BlockHash.String() + ":" + transferLog.LogIndex.String()
+			if _, ok := fetched[key]; ok {
+				continue
+			}
+			fetched[key] = struct{}{}
+			logs = append(logs, transferLog)
+		}
+	}
+	// merged logs are ordered as they are emitted
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber.Cmp(logs[j].BlockNumber) != 0 {
+			return logs[i].BlockNumber.Cmp(logs[j].BlockNumber) < 0
+		}
+		return logs[i].LogIndex.Cmp(logs[j].LogIndex) < 0
+	})
+
+	logsByBlock := make(map[blockchain.Hash][]*blockchain.Log)
+	for _, transferLog := range logs {
+		logsByBlock[transferLog.BlockHash] = append(logsByBlock[transferLog.BlockHash], transferLog)
+	}
+	matched := make([]*transfer.AddressTransfer, 0)
+	for _, block := range blocks {
+		matched = append(matched, filter.FilterTransfers(ctx, addressFilter, block, logsByBlock[block.Hash])...)
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	return b.transferRepository.InsertTransfers(ctx, matched)
+}
+
+// backfillInternalTransfers traces the fetched blocks one by one and stores internal transfers matching the filter,
+// it returns number of blocks whose internal transfers were stored, all blocks are stored when tracing is disabled
+func (b *backfiller) backfillInternalTransfers(ctx context.Context, addressFilter subscriber.Filter, blocks []*blockchain.Block) (int, error) {
+	if b.tracer == nil {
+		return len(blocks), nil
+	}
+	matched := make([]*trace.AddressTransfer, 0)
+	traced := 0
+	var err error
+	for _, block := range blocks {
+		var traces []*blockchain.TransactionTrace
+		for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+			if traces, err = b.tracer.TraceBlock(ctx, block); err == nil {
+				break
+			}
+			log.Printf("Error tracing block %s: %s. Retry %d/%d.", block.Number, err, currentRetry+1, maxRetries)
+			time.Sleep(retryDelay)
+		}
+		if err != nil {
+			// internal transfers of traced blocks are still stored
+			break
+		}
+		matched = append(matched, filter.FilterInternalTransfers(ctx, addressFilter, block, traces)...)
+		traced++
+	}
+	if len(matched) == 0 {
+		return traced, err
+	}
+	if err := b.traceRepository.InsertInternalTransfers(ctx, matched); err != nil {
+		return 0, err
+	}
+	return traced, err
+}
+
+// attachReceipts fetches receipts of filtered transactions and contract creations of the block with retries,
+// receipts of the block are fetched once for both. Filtered transactions whose receipts could not be fetched are stored without them.
+func (b *backfiller) attachReceipts(ctx context.Context, block *blockchain.Block, filtered, creations []*transaction.AddressTransaction) {
+	transactions := make([]*transaction.AddressTransaction, 0, len(filtered)+len(creations))
+	transactions = append(append(transactions, filtered...), creations...)
+	var err error
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if err = b.receiptFetcher.AttachReceipts(ctx, block, transactions); err == nil {
+			return
+		}
+		log.Printf("Error fetching receipts: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+		time.Sleep(retryDelay)
+	}
+	log.Printf("Storing transactions of block %s without receipts: %s.", block.Number, err)
+}
+
+func (b *backfiller) Close(ctx context.Context) {}
+
+var _ subscriber.Filter = (*addressFilter)(nil)
+
+// addressFilter matches only transactions of the backfilled address meeting criteria of its subscription
+type addressFilter struct {
+	address blockchain.Address
+	filter  subscriber.Filter
+}
+
+func (f *addressFilter) Test(ctx context.Context, address blockchain.Address, tx *blockchain.Transaction) bool {
+	return f.address == address && f.filter.Test(ctx, address, tx)
+}
+
+func (f *addressFilter) TestAddress(ctx context.Context, address blockchain.Address) bool {
+	return f.address == address && f.filter.TestAddress(ctx, address)
+}
+
+func newJobID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
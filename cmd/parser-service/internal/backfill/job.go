@@ -0,0 +1,138 @@
+package backfill
+
+import (
+	"sync"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// JobStatus is a status of backfill job
+type JobStatus string
+
+const (
+	JobQueued    = JobStatus("queued")
+	JobRunning   = JobStatus("running")
+	JobCompleted = JobStatus("completed")
+	// JobFailed job finished, but some blocks could not be scanned
+	JobFailed = JobStatus("failed")
+)
+
+// Job is a snapshot of backfill job progress
+type Job struct {
+	ID                  string             `json:"id"`
+	TenantID            string             `json:"tenant_id,omitempty"`
+	Address             blockchain.Address `json:"address"`
+	Status              JobStatus          `json:"status"`
+	FromBlock           int64              `json:"from_block"`
+	ToBlock             int64              `json:"to_block"`
+	ScannedBlocks       int64              `json:"scanned_blocks"`
+	FailedBlocks        int64              `json:"failed_blocks"`
+	MatchedTransactions int64              `json:"matched_transactions"`
+	Error               string             `json:"error,omitempty"`
+	CreatedAt           time.Time          `json:"created_at"`
+	// StartedAt and FinishedAt are not set until the job is started and finished
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// job is a backfill job, progress is updated concurrently by batches of the job
+type job struct {
+	id        string
+	tenantID  string
+	address   blockchain.Address
+	fromBlock blockchain.BlockNumber
+	toBlock   blockchain.BlockNumber
+
+	status              JobStatus
+	scannedBlocks       int64
+	failedBlocks        int64
+	matchedTransactions int64
+	lastError           error
+	createdAt           time.Time
+	startedAt           time.Time
+	finishedAt          time.Time
+	mutex               sync.RWMutex
+}
+
+func newJob(id string, tenantID string, address blockchain.Address, fromBlock blockchain.BlockNumber, toBlock blockchain.BlockNumber) *job {
+	return &job{
+		id:        id,
+		tenantID:  tenantID,
+		address:   address,
+		fromBlock: fromBlock,
+		toBlock:   toBlock,
+		status:    JobQueued,
+		createdAt: time.Now(),
+	}
+}
+
+func (j *job) start() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.status = JobRunning
+	j.startedAt = time.Now()
+}
+
+// progress records result of scanned blocks, failed blocks are the part of scanned blocks which could not be scanned
+func (j *job) progress(blocks int64, failedBlocks int64, matchedTransactions int64, err error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.scannedBlocks += blocks
+	j.failedBlocks += failedBlocks
+	j.matchedTransactions += matchedTransactions
+	if err != nil {
+		j.lastError = err
+	}
+}
+
+// finish marks job as finished, job is failed if it was interrupted or some blocks failed
+func (j *job) finish(err error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if err != nil {
+		j.lastError = err
+	}
+	j.status = JobCompleted
+	if j.lastError != nil {
+		j.status = JobFailed
+	}
+	j.finishedAt = time.Now()
+}
+
+// finishedBefore returns true if the job finished before the given time
+func (j *job) finishedBefore(t time.Time) bool {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return !j.finishedAt.IsZero() && j.finishedAt.Before(t)
+}
+
+// snapshot returns current progress of the job
+func (j *job) snapshot() *Job {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	snapshot := &Job{
+		ID:                  j.id,
+		TenantID:            j.tenantID,
+		Address:             j.address,
+		Status:              j.status,
+		FromBlock:           j.fromBlock.ToInt64(),
+		ToBlock:             j.toBlock.ToInt64(),
+		ScannedBlocks:       j.scannedBlocks,
+		FailedBlocks:        j.failedBlocks,
+		MatchedTransactions: j.matchedTransactions,
+		CreatedAt:           j.createdAt,
+	}
+	if !j.startedAt.IsZero() {
+		startedAt := j.startedAt
+		snapshot.StartedAt = &startedAt
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		snapshot.FinishedAt = &finishedAt
+	}
+	if j.lastError != nil {
+		snapshot.Error = j.lastError.Error()
+	}
+	return snapshot
+}
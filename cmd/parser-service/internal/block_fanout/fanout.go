@@ -0,0 +1,54 @@
+package block_fanout
+
+import (
+	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// BlockFanout forwards every processed block to all consumers, e.g. transaction filter and new head notifications.
+type BlockFanout interface {
+	// Start starts forwarding processed blocks
+	Start(ctx context.Context)
+	// Close closes the block fanout
+	Close(ctx context.Context)
+}
+
+type blockFanout struct {
+	processedBlockChannel <-chan *blockchain.Block
+	consumers             []chan<- *blockchain.Block
+}
+
+// NewBlockFanout creates fanout of processed blocks to consumers,
+// block is sent to consumers one by one and every send blocks until the consumer receives it,
+// so the slowest consumer applies backpressure to block processor as before.
+func NewBlockFanout(processedBlockChannel <-chan *blockchain.Block, consumers ...chan<- *blockchain.Block) BlockFanout {
+	return &blockFanout{
+		processedBlockChannel: processedBlockChannel,
+		consumers:             consumers,
+	}
+}
+
+func (f *blockFanout) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case block := <-f.processedBlockChannel:
+			// defensive programming
+			// we should never receive a nil block
+			if block == nil {
+				continue
+			}
+			for _, consumer := range f.consumers {
+				select {
+				case <-ctx.Done():
+					return
+				case consumer <- block:
+				}
+			}
+		}
+	}
+}
+
+func (f *blockFanout) Close(ctx context.Context) {}
@@ -2,7 +2,11 @@ package block_processor
 
 import (
 	"context"
+	"github.com/veljkomatic/be-homework/pkg/confirmation"
 	"github.com/veljkomatic/be-homework/pkg/storage/block"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
 	"log"
 	"sync"
 	"time"
@@ -12,11 +16,12 @@ import (
 )
 
 const (
-	blockMonitorInterval         = 10 * time.Second
-	retryDelay                   = 2 * time.Second
-	maxRetries                   = 3
-	maxConcurrentBlocksToProcess = 20
-	maxConcurrentBlockRetries    = 10
+	blockMonitorInterval          = 10 * time.Second
+	retryDelay                    = 2 * time.Second
+	maxRetries                    = 3
+	maxConcurrentBatchesToProcess = 4
+	blocksBatchSize               = 20
+	maxConcurrentBlockRetries     = 10
 )
 
 // BlockProcessor is responsible for processing new blocks
@@ -30,10 +35,15 @@ type BlockProcessor interface {
 }
 
 type blockProcessor struct {
-	rpcProvider     provider.Provider
-	blockRepository block.Repository
+	rpcProvider           provider.Provider
+	blockRepository       block.Repository
+	transactionRepository transaction.WriteRepository
+	transferRepository    transfer.WriteRepository
+	traceRepository       trace.WriteRepository
+	recentBlocks          *blockWindow
+	sequencer             *blockSequencer
+	confirmationConfig    confirmation.Config
 
-	processedBlockChan  chan<- *blockchain.Block
 	failedToProcessChan chan *blockchain.BlockNumber
 	processingMutex     sync.Mutex
 }
@@ -41,36 +51,76 @@ type blockProcessor struct {
 func NewBlockProcessor(
 	rpcProvider provider.Provider,
 	blockRepository block.Repository,
+	transactionRepository transaction.WriteRepository,
+	transferRepository transfer.WriteRepository,
+	traceRepository trace.WriteRepository,
+	confirmationConfig confirmation.Config,
 
 	processedBlockChannel chan<- *blockchain.Block,
 	failedToProcessChan chan *blockchain.BlockNumber,
+	// inFlightBlocks is done by the transaction filter when data of the processed block is stored
+	inFlightBlocks *sync.WaitGroup,
 ) BlockProcessor {
+	recentBlocks := newBlockWindow(reorgWindowSize)
 	return &blockProcessor{
-		rpcProvider:     rpcProvider,
-		blockRepository: blockRepository,
+		rpcProvider:           rpcProvider,
+		blockRepository:       blockRepository,
+		transactionRepository: transactionRepository,
+		transferRepository:    transferRepository,
+		traceRepository:       traceRepository,
+		recentBlocks:          recentBlocks,
+		sequencer:             newBlockSequencer(recentBlocks, processedBlockChannel, inFlightBlocks),
+		confirmationConfig:    confirmationConfig,
 
-		processedBlockChan:  processedBlockChannel,
 		failedToProcessChan: failedToProcessChan,
 	}
 }
 
+// Start processes new blocks on every new head pushed by the provider,
+// if provider is not able to push new heads or subscription is down, it falls back to polling.
 func (p *blockProcessor) Start(ctx context.Context) {
 	ticker := time.NewTicker(blockMonitorInterval)
 	defer ticker.Stop()
 
+	var headSubscription provider.HeadSubscription
+	var heads <-chan *blockchain.Block
+	if headSubscriber, ok := p.rpcProvider.(provider.HeadSubscriber); ok {
+		headSubscription = headSubscriber.SubscribeNewHeads(ctx)
+		heads = headSubscription.Heads()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case head, ok := <-heads:
+			if !ok {
+				heads = nil
+				continue
+			}
+			log.Printf("New head %s received.", head.Number)
+			p.process(ctx)
 		case <-ticker.C:
-			err := p.processNewBlocks(ctx)
-			if err != nil {
-				log.Println(ctx, err, "process new blocks")
+			if headSubscription != nil && headSubscription.Connected() {
+				continue
 			}
+			p.process(ctx)
 		}
 	}
 }
 
+// process processes new blocks and updates finalized block
+func (p *blockProcessor) process(ctx context.Context) {
+	err := p.processNewBlocks(ctx)
+	if err != nil {
+		log.Println(ctx, err, "process new blocks")
+	}
+	err = p.updateFinalizedBlock(ctx)
+	if err != nil {
+		log.Println(ctx, err, "update finalized block")
+	}
+}
+
 func (p *blockProcessor) HandleFailedBlocks(ctx context.Context) {
 	// The semaphore channel
 	retrySemaphore := make(chan struct{}, maxConcurrentBlockRetries)
@@ -93,10 +143,12 @@ func (p *blockProcessor) HandleFailedBlocks(ctx context.Context) {
 			retrySemaphore <- struct{}{} // acquire a semaphore slot
 
 			go func(block *blockchain.BlockNumber) {
-				defer func() { <-retrySemaphore }() // release a semaphore slot when we're done
 				err := p.processBlock(ctx, *block)
+				<-retrySemaphore // release a semaphore slot when we're done
 				if err != nil {
+					// blocks are sent in order, so the block is retried until it is processed
 					log.Println(ctx, err, "Error processing block: %s.\n", err, block)
+					p.failedToProcessChan <- block
 				}
 			}(block)
 		}
@@ -117,6 +169,7 @@ func (p *blockProcessor) processNewBlocks(ctx context.Context) error {
 		return err
 	}
 	if currentBlockNumber == blockchain.EarliestBlockNumber {
+		p.sequencer.reset(latestBlockNumber)
 		err := p.processBlock(ctx, latestBlockNumber)
 		if err != nil {
 			return err
@@ -124,21 +177,42 @@ func (p *blockProcessor) processNewBlocks(ctx context.Context) error {
 		return p.blockRepository.SaveBlockNumber(ctx, latestBlockNumber)
 	}
 
+	if !p.sequencer.started() {
+		p.sequencer.reset(currentBlockNumber.Inc())
+	}
+
+	// make sure that blocks we already processed are still canonical on every tick,
+	// block at the tip can be replaced by a block at the same height
+	// if not, continue processing from the common ancestor
+	currentBlockNumber, err = p.handleReorg(ctx, currentBlockNumber, latestBlockNumber)
+	if err != nil {
+		return err
+	}
 	if latestBlockNumber > currentBlockNumber {
 		// call processBlocksInParallel in separate goroutine
 		// to avoid blocking the main thread
 		go p.processBlocksInParallel(ctx, currentBlockNumber.Inc(), latestBlockNumber)
 		// optimistic update
-		// if error occurs, the block will be retried and eventually processed as success
-		// if the block is not processed even in HandleFailedBlocks,
-		// we could send failed blocks to queue and retry later via a separate job
+		// if error occurs, the block will be retried in HandleFailedBlocks until it is processed,
+		// blocks after it wait in the sequencer, so transactions are still notified in block order
 		return p.blockRepository.SaveBlockNumber(ctx, latestBlockNumber)
 	}
-
-	//TODO: handle reorgs in future
 	return nil
 }
 
+// updateFinalizedBlock fetches the block with configured finality tag
+// and saves its number, so transactions in blocks up to it are considered finalized.
+func (p *blockProcessor) updateFinalizedBlock(ctx context.Context) error {
+	if !p.confirmationConfig.TracksFinality() {
+		return nil
+	}
+	finalizedBlockNumber, err := p.rpcProvider.GetBlockNumberByTag(ctx, p.confirmationConfig.FinalityTag)
+	if err != nil {
+		return err
+	}
+	return p.blockRepository.SaveFinalizedBlockNumber(ctx, finalizedBlockNumber)
+}
+
 func (p *blockProcessor) processBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
 	var currentRetry int
 
@@ -148,13 +222,18 @@ func (p *blockProcessor) processBlock(ctx context.Context, blockNumber blockchai
 	var block *blockchain.Block
 
 	for currentRetry < maxRetries {
+		// block was already sent or sequencer was reset after reorg and the block will be fetched again
+		if !p.sequencer.expects(blockNumber) {
+			return nil
+		}
+		epoch := p.sequencer.currentEpoch()
 		block, err = p.rpcProvider.GetBlockByNumber(ctx, blockNumber)
 		if err != nil {
 			log.Println(ctx, err, "Error fetching block number %d: %s. Retry %d/%d.\n", blockNumber, err, currentRetry+1, maxRetries)
 			currentRetry++
 			continue
 		}
-		p.processedBlockChan <- block
+		p.sequencer.add(epoch, block)
 		break
 	}
 
@@ -165,31 +244,61 @@ func (p *blockProcessor) processBlock(ctx context.Context, blockNumber blockchai
 	return nil
 }
 
+// processBlocksInParallel fetches blocks of the range in batches, so catching up after downtime
+// does not send a separate request for every block. Blocks that failed to be fetched are retried separately.
 func (p *blockProcessor) processBlocksInParallel(ctx context.Context, startBlockNumber blockchain.BlockNumber, endBlockNumber blockchain.BlockNumber) {
 	// The semaphore channel
-	processSemaphore := make(chan struct{}, maxConcurrentBlocksToProcess)
+	processSemaphore := make(chan struct{}, maxConcurrentBatchesToProcess)
 
 	wg := sync.WaitGroup{}
-	for i := startBlockNumber.ToInt64(); i <= endBlockNumber.ToInt64(); i++ {
+	for from := startBlockNumber; from <= endBlockNumber; from += blocksBatchSize {
+		to := from + blocksBatchSize - 1
+		if to > endBlockNumber {
+			to = endBlockNumber
+		}
+
 		wg.Add(1)
 		processSemaphore <- struct{}{} // Acquire a semaphore slot
 
-		go func(blockNumber blockchain.BlockNumber) {
+		go func(from blockchain.BlockNumber, to blockchain.BlockNumber) {
 			defer wg.Done()
 			defer func() { <-processSemaphore }() // Release a semaphore slot when we're done
 
-			err := p.processBlock(ctx, blockNumber)
-			if err != nil {
-				p.failedToProcessChan <- &blockNumber
-			}
-		}(blockchain.BlockNumber(i))
+			p.processBlockRange(ctx, from, to)
+		}(from, to)
 	}
 
 	wg.Wait()
 	close(processSemaphore)
 }
 
+// processBlockRange fetches blocks of the inclusive range in a single batch,
+// blocks missing from the batch are sent to the failed blocks channel.
+func (p *blockProcessor) processBlockRange(ctx context.Context, from blockchain.BlockNumber, to blockchain.BlockNumber) {
+	log.Printf("Processing blocks %d-%d.", from, to)
+
+	epoch := p.sequencer.currentEpoch()
+	blocks, err := p.rpcProvider.GetBlocksByRange(ctx, from, to)
+	if err != nil {
+		log.Println(ctx, err, "Error fetching blocks range")
+	}
+
+	processed := make(map[blockchain.BlockNumber]bool, len(blocks))
+	for _, block := range blocks {
+		blockNumber := blockchain.NewBlockNumberBuilder().FromQuantity(block.Number).Value()
+		processed[blockNumber] = true
+		p.sequencer.add(epoch, block)
+	}
+
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		if !processed[blockNumber] && p.sequencer.expects(blockNumber) {
+			failedBlockNumber := blockNumber
+			p.failedToProcessChan <- &failedBlockNumber
+		}
+	}
+}
+
 func (p *blockProcessor) Close(ctx context.Context) {
-	close(p.processedBlockChan)
+	close(p.sequencer.processedBlockChan)
 	close(p.failedToProcessChan)
 }
@@ -0,0 +1,160 @@
+package block_processor
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// reorgWindowSize is the number of recent block hashes kept to detect reorgs.
+// Reorgs deeper than this window are rolled back to the oldest block in the window.
+const reorgWindowSize = 128
+
+// blockWindow keeps hashes of recently processed blocks.
+// Blocks are processed in parallel, so the window can have gaps until all blocks are processed.
+type blockWindow struct {
+	hashes map[blockchain.BlockNumber]blockchain.Hash
+	size   blockchain.BlockNumber
+	mutex  sync.RWMutex
+}
+
+func newBlockWindow(size int) *blockWindow {
+	return &blockWindow{
+		hashes: make(map[blockchain.BlockNumber]blockchain.Hash, size),
+		size:   blockchain.BlockNumber(size),
+	}
+}
+
+// add stores hash of the block and evicts blocks that fell out of the window
+func (w *blockWindow) add(blockNumber blockchain.BlockNumber, hash blockchain.Hash) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.hashes[blockNumber] = hash
+	for number := range w.hashes {
+		if number <= blockNumber-w.size {
+			delete(w.hashes, number)
+		}
+	}
+}
+
+// hash returns stored hash of the block
+func (w *blockWindow) hash(blockNumber blockchain.BlockNumber) (blockchain.Hash, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	hash, ok := w.hashes[blockNumber]
+	return hash, ok
+}
+
+// lowest returns the lowest block number in the window
+func (w *blockWindow) lowest() (blockchain.BlockNumber, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	lowest := blockchain.InvalidBlockNumber
+	for number := range w.hashes {
+		if lowest == blockchain.InvalidBlockNumber || number < lowest {
+			lowest = number
+		}
+	}
+	return lowest, lowest != blockchain.InvalidBlockNumber
+}
+
+// truncate removes all blocks starting from given block number
+func (w *blockWindow) truncate(fromBlockNumber blockchain.BlockNumber) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for number := range w.hashes {
+		if number >= fromBlockNumber {
+			delete(w.hashes, number)
+		}
+	}
+}
+
+// detectReorg checks if the last block sent to the transaction filter is still part of the canonical chain.
+// It fetches the canonical block at the same height and compares its hash with the hash we stored,
+// so the block at the tip replaced by a block at the same height is detected too.
+// It returns true if the chain was reorganized.
+func (p *blockProcessor) detectReorg(ctx context.Context, lastBlockNumber blockchain.BlockNumber) (bool, error) {
+	storedHash, ok := p.recentBlocks.hash(lastBlockNumber)
+	if !ok {
+		// nothing to compare with, service was restarted or the block fell out of the window
+		return false, nil
+	}
+	canonicalBlock, err := p.rpcProvider.GetBlockByNumber(ctx, lastBlockNumber)
+	if err != nil {
+		return false, err
+	}
+	return canonicalBlock.Hash != storedHash, nil
+}
+
+// findCommonAncestor walks back through the window of recent blocks
+// and returns the highest block which hash matches the canonical chain.
+func (p *blockProcessor) findCommonAncestor(ctx context.Context, orphanedBlockNumber blockchain.BlockNumber) (blockchain.BlockNumber, error) {
+	lowest, ok := p.recentBlocks.lowest()
+	if !ok {
+		return orphanedBlockNumber - 1, nil
+	}
+	for blockNumber := orphanedBlockNumber - 1; blockNumber >= lowest; blockNumber-- {
+		storedHash, ok := p.recentBlocks.hash(blockNumber)
+		if !ok {
+			// we can not verify block that is not in the window, treat it as orphaned
+			continue
+		}
+		canonicalBlock, err := p.rpcProvider.GetBlockByNumber(ctx, blockNumber)
+		if err != nil {
+			return blockchain.InvalidBlockNumber, err
+		}
+		if canonicalBlock.Hash == storedHash {
+			return blockNumber, nil
+		}
+	}
+	log.Printf("Reorg is deeper than window of %d blocks, rolling back to block %d.", reorgWindowSize, lowest-1)
+	return lowest - 1, nil
+}
+
+// rollback removes transactions, token transfers and internal transfers stored for orphaned blocks and rewinds the last processed block number
+// to the common ancestor, so canonical blocks are processed again.
+// Sequencer has to be fenced before, so no orphaned block is being filtered at this moment.
+func (p *blockProcessor) rollback(ctx context.Context, commonAncestor blockchain.BlockNumber) error {
+	if err := p.transactionRepository.DeleteTransactionsFromBlock(ctx, commonAncestor.Inc()); err != nil {
+		return err
+	}
+	if err := p.transferRepository.DeleteTransfersFromBlock(ctx, commonAncestor.Inc()); err != nil {
+		return err
+	}
+	if err := p.traceRepository.DeleteInternalTransfersFromBlock(ctx, commonAncestor.Inc()); err != nil {
+		return err
+	}
+	p.recentBlocks.truncate(commonAncestor.Inc())
+	return p.blockRepository.SaveBlockNumber(ctx, commonAncestor)
+}
+
+// handleReorg detects a reorg and rolls back to the common ancestor.
+// Blocks sent to the transaction filter are drained before the rollback and blocks being fetched are dropped,
+// then the sequencer continues from the common ancestor.
+// It returns the block number from which processing should continue.
+func (p *blockProcessor) handleReorg(ctx context.Context, currentBlockNumber blockchain.BlockNumber, latestBlockNumber blockchain.BlockNumber) (blockchain.BlockNumber, error) {
+	lastBlockNumber, ok := p.sequencer.lastSent()
+	if !ok || lastBlockNumber > latestBlockNumber {
+		// provider is behind the last sent block, it is checked once provider catches up
+		return currentBlockNumber, nil
+	}
+	reorged, err := p.detectReorg(ctx, lastBlockNumber)
+	if err != nil || !reorged {
+		return currentBlockNumber, err
+	}
+	commonAncestor, err := p.findCommonAncestor(ctx, lastBlockNumber)
+	if err != nil {
+		return currentBlockNumber, err
+	}
+	log.Printf("Reorg detected at block %d, common ancestor is block %d.", lastBlockNumber, commonAncestor)
+	p.sequencer.fence()
+	if err := p.rollback(ctx, commonAncestor); err != nil {
+		// orphaned blocks are still in the window, so the reorg is detected again on the next tick
+		p.sequencer.reset(lastBlockNumber.Inc())
+		return currentBlockNumber, err
+	}
+	p.sequencer.reset(commonAncestor.Inc())
+	return commonAncestor, nil
+}
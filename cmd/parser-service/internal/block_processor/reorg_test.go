@@ -0,0 +1,109 @@
+package block_processor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/confirmation"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/storage/block"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
+)
+
+// canonicalChain is a provider which returns blocks of the canonical chain by number
+type canonicalChain struct {
+	provider.Provider
+	blocks map[blockchain.BlockNumber]*blockchain.Block
+}
+
+func (c *canonicalChain) GetBlockByNumber(ctx context.Context, blockNumber blockchain.BlockNumber) (*blockchain.Block, error) {
+	canonicalBlock, ok := c.blocks[blockNumber]
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+	return canonicalBlock, nil
+}
+
+// repositories are embedded under their own names, they have methods of the same name
+type (
+	transactionRepository = transaction.WriteRepository
+	transferRepository    = transfer.WriteRepository
+	traceRepository       = trace.WriteRepository
+)
+
+// rollbackRecorder records block numbers from which data was rolled back and the saved block number
+type rollbackRecorder struct {
+	block.Repository
+	transactionRepository
+	transferRepository
+	traceRepository
+	deletedFrom []blockchain.BlockNumber
+	saved       blockchain.BlockNumber
+}
+
+func (r *rollbackRecorder) DeleteTransactionsFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	r.deletedFrom = append(r.deletedFrom, blockNumber)
+	return nil
+}
+
+func (r *rollbackRecorder) DeleteTransfersFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	r.deletedFrom = append(r.deletedFrom, blockNumber)
+	return nil
+}
+
+func (r *rollbackRecorder) DeleteInternalTransfersFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	r.deletedFrom = append(r.deletedFrom, blockNumber)
+	return nil
+}
+
+func (r *rollbackRecorder) SaveBlockNumber(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	r.saved = blockNumber
+	return nil
+}
+
+func TestHandleReorgDetectsBlockReplacedAtTheTip(t *testing.T) {
+	chain := &canonicalChain{blocks: map[blockchain.BlockNumber]*blockchain.Block{
+		10: testBlock(10, "aa"),
+		11: testBlock(11, "aa"),
+	}}
+	recorder := &rollbackRecorder{}
+	blocks := make(chan *blockchain.Block, 10)
+	var inFlightBlocks sync.WaitGroup
+	p := NewBlockProcessor(chain, recorder, recorder, recorder, recorder, confirmation.Config{}, blocks, nil, &inFlightBlocks).(*blockProcessor)
+
+	p.sequencer.reset(10)
+	epoch := p.sequencer.currentEpoch()
+	p.sequencer.add(epoch, testBlock(10, "aa"))
+	p.sequencer.add(epoch, testBlock(11, "aa"))
+	// blocks are stored by the transaction filter
+	for range []int{10, 11} {
+		<-blocks
+		inFlightBlocks.Done()
+	}
+
+	currentBlockNumber, err := p.handleReorg(context.Background(), 11, 11)
+	if err != nil || currentBlockNumber != 11 {
+		t.Fatalf("expected no reorg, got block %d and error %v", currentBlockNumber, err)
+	}
+
+	// block 11 is replaced by a block at the same height, latest block number does not change
+	chain.blocks[11] = testBlock(11, "bb")
+	currentBlockNumber, err = p.handleReorg(context.Background(), 11, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if currentBlockNumber != 10 || recorder.saved != 10 {
+		t.Fatalf("expected rollback to block 10, continued from %d and saved %d", currentBlockNumber, recorder.saved)
+	}
+	if fmt.Sprint(recorder.deletedFrom) != "[11 11 11]" {
+		t.Fatalf("expected data deleted from block 11, got %v", recorder.deletedFrom)
+	}
+	if p.sequencer.expects(10) || !p.sequencer.expects(11) {
+		t.Fatal("expected sequencer to continue from block 11")
+	}
+}
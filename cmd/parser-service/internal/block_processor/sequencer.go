@@ -0,0 +1,112 @@
+package block_processor
+
+import (
+	"sync"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// blockSequencer sends fetched blocks to the transaction filter in order of their numbers.
+// Blocks are fetched by parallel batches and failed blocks are retried later, so a block can be fetched
+// before the blocks preceding it, it is pending until all of them are sent.
+// Transaction filter notifies blocks in order it receives them, so listeners receive transactions in block order
+// and cursor of any notified transaction is a valid resume point.
+type blockSequencer struct {
+	// next is the number of the next block to send, it is InvalidBlockNumber until the sequencer is reset
+	next blockchain.BlockNumber
+	// epoch changes on every fence and reset, blocks fetched in previous epoch can be orphaned and are dropped
+	epoch   int
+	pending map[blockchain.BlockNumber]*blockchain.Block
+	window  *blockWindow
+
+	processedBlockChan chan<- *blockchain.Block
+	// inFlightBlocks counts blocks sent to the transaction filter which are not stored yet
+	inFlightBlocks *sync.WaitGroup
+	mutex          sync.Mutex
+}
+
+func newBlockSequencer(window *blockWindow, processedBlockChan chan<- *blockchain.Block, inFlightBlocks *sync.WaitGroup) *blockSequencer {
+	return &blockSequencer{
+		next:               blockchain.InvalidBlockNumber,
+		pending:            make(map[blockchain.BlockNumber]*blockchain.Block),
+		window:             window,
+		processedBlockChan: processedBlockChan,
+		inFlightBlocks:     inFlightBlocks,
+	}
+}
+
+// currentEpoch returns epoch of blocks fetched from now on, it is passed to add together with the fetched block
+func (s *blockSequencer) currentEpoch() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.epoch
+}
+
+// started returns true if the sequencer was reset and is not fenced
+func (s *blockSequencer) started() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.next != blockchain.InvalidBlockNumber
+}
+
+// expects returns true if the block was not sent yet and is not dropped when it is added
+func (s *blockSequencer) expects(blockNumber blockchain.BlockNumber) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.next != blockchain.InvalidBlockNumber && blockNumber >= s.next
+}
+
+// lastSent returns number of the last block sent to the transaction filter
+func (s *blockSequencer) lastSent() (blockchain.BlockNumber, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.next == blockchain.InvalidBlockNumber {
+		return blockchain.InvalidBlockNumber, false
+	}
+	return s.next - 1, true
+}
+
+// add stores the block fetched in the epoch and sends pending blocks which follow the last sent one.
+// Block is dropped if it was fetched before the last fence or reset, or if the block was already sent.
+// Hash of the sent block is added to the window of recent blocks, so reorgs are detected only for sent blocks.
+func (s *blockSequencer) add(epoch int, block *blockchain.Block) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	blockNumber := blockchain.NewBlockNumberBuilder().FromQuantity(block.Number).Value()
+	if epoch != s.epoch || s.next == blockchain.InvalidBlockNumber || blockNumber < s.next {
+		return
+	}
+	s.pending[blockNumber] = block
+	for {
+		nextBlock, ok := s.pending[s.next]
+		if !ok {
+			return
+		}
+		delete(s.pending, s.next)
+		s.window.add(s.next, nextBlock.Hash)
+		s.inFlightBlocks.Add(1)
+		s.processedBlockChan <- nextBlock
+		s.next++
+	}
+}
+
+// fence stops sending blocks until the sequencer is reset and drops pending blocks and blocks being fetched.
+// It waits until blocks already sent are stored, so data of orphaned block is never stored after it is rolled back.
+func (s *blockSequencer) fence() {
+	s.mutex.Lock()
+	s.epoch++
+	s.next = blockchain.InvalidBlockNumber
+	s.pending = make(map[blockchain.BlockNumber]*blockchain.Block)
+	s.mutex.Unlock()
+
+	s.inFlightBlocks.Wait()
+}
+
+// reset sends blocks starting from given block number, pending blocks and blocks being fetched are dropped
+func (s *blockSequencer) reset(next blockchain.BlockNumber) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.epoch++
+	s.next = next
+	s.pending = make(map[blockchain.BlockNumber]*blockchain.Block)
+}
@@ -0,0 +1,111 @@
+package block_processor
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+func testBlock(number int64, fork string) *blockchain.Block {
+	return &blockchain.Block{
+		Number: blockchain.QuantityFromInt64(number),
+		Hash:   blockchain.Hash(fmt.Sprintf("0x%s%062x", fork, number)),
+	}
+}
+
+// receivedNumbers receives all blocks sent so far and marks them stored
+func receivedNumbers(blocks chan *blockchain.Block, inFlightBlocks *sync.WaitGroup) []int64 {
+	var numbers []int64
+	for {
+		select {
+		case block := <-blocks:
+			numbers = append(numbers, block.Number.Int64())
+			inFlightBlocks.Done()
+		default:
+			return numbers
+		}
+	}
+}
+
+func TestBlockSequencerSendsBlocksInOrder(t *testing.T) {
+	blocks := make(chan *blockchain.Block, 10)
+	var inFlightBlocks sync.WaitGroup
+	sequencer := newBlockSequencer(newBlockWindow(reorgWindowSize), blocks, &inFlightBlocks)
+
+	epoch := sequencer.currentEpoch()
+	// block added before the sequencer is reset is dropped
+	sequencer.add(epoch, testBlock(10, "aa"))
+	sequencer.reset(10)
+	epoch = sequencer.currentEpoch()
+
+	// blocks of later batch and retried block arrive first
+	for _, number := range []int64{12, 13, 11} {
+		sequencer.add(epoch, testBlock(number, "aa"))
+	}
+	if numbers := receivedNumbers(blocks, &inFlightBlocks); len(numbers) != 0 {
+		t.Fatalf("blocks %v sent before block 10", numbers)
+	}
+	sequencer.add(epoch, testBlock(10, "aa"))
+	// block already sent is dropped
+	sequencer.add(epoch, testBlock(11, "aa"))
+
+	numbers := receivedNumbers(blocks, &inFlightBlocks)
+	if fmt.Sprint(numbers) != "[10 11 12 13]" {
+		t.Fatalf("expected blocks [10 11 12 13], got %v", numbers)
+	}
+	if lastSent, ok := sequencer.lastSent(); !ok || lastSent != 13 {
+		t.Fatalf("expected last sent block 13, got %d", lastSent)
+	}
+	if hash, ok := sequencer.window.hash(13); !ok || hash != testBlock(13, "aa").Hash {
+		t.Fatalf("expected hash of sent block in window, got %s", hash)
+	}
+}
+
+func TestBlockSequencerDropsBlocksFetchedBeforeFence(t *testing.T) {
+	blocks := make(chan *blockchain.Block, 10)
+	var inFlightBlocks sync.WaitGroup
+	sequencer := newBlockSequencer(newBlockWindow(reorgWindowSize), blocks, &inFlightBlocks)
+	sequencer.reset(10)
+
+	orphanedEpoch := sequencer.currentEpoch()
+	sequencer.add(orphanedEpoch, testBlock(10, "aa"))
+	// pending orphaned block is dropped by fence
+	sequencer.add(orphanedEpoch, testBlock(12, "aa"))
+
+	fenced := make(chan struct{})
+	go func() {
+		sequencer.fence()
+		close(fenced)
+	}()
+	select {
+	case <-fenced:
+		t.Fatal("fence returned before sent block was stored")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if numbers := receivedNumbers(blocks, &inFlightBlocks); fmt.Sprint(numbers) != "[10]" {
+		t.Fatalf("expected block [10], got %v", numbers)
+	}
+	select {
+	case <-fenced:
+	case <-time.After(time.Second):
+		t.Fatal("fence did not return after sent block was stored")
+	}
+
+	// orphaned block fetched before the fence is dropped while fenced and after reset
+	sequencer.add(orphanedEpoch, testBlock(11, "aa"))
+	sequencer.reset(10)
+	sequencer.add(orphanedEpoch, testBlock(11, "aa"))
+	epoch := sequencer.currentEpoch()
+	for _, number := range []int64{10, 11, 12} {
+		sequencer.add(epoch, testBlock(number, "bb"))
+	}
+	if numbers := receivedNumbers(blocks, &inFlightBlocks); fmt.Sprint(numbers) != "[10 11 12]" {
+		t.Fatalf("expected blocks [10 11 12], got %v", numbers)
+	}
+	if hash, _ := sequencer.window.hash(12); hash != testBlock(12, "bb").Hash {
+		t.Fatalf("expected hash of canonical block 12, got %s", hash)
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/tenant"
+)
+
+type CreateKeyBody struct {
+	TenantID string `json:"tenant_id"`
+	// RateLimit is the number of requests per second, default rate limit is used if it is not set
+	RateLimit float64 `json:"rate_limit,omitempty"`
+	// Burst is the number of requests allowed at once, default burst is used if it is not set
+	Burst int `json:"burst,omitempty"`
+	// MaxSubscriptions is the quota of addresses subscribed by the tenant, default quota is used if it is not set
+	MaxSubscriptions int `json:"max_subscriptions,omitempty"`
+	// SignatureRequired rejects requests which are not signed with secret of the key
+	SignatureRequired bool `json:"signature_required,omitempty"`
+}
+
+type GetKeysResponse struct {
+	Keys []*tenant.Key `json:"keys"`
+}
+
+// AdminKeysHandler lists api keys with GET and creates api key with POST /admin/keys,
+// response of created key contains its secret
+func AdminKeysHandler(keyring tenant.Keyring) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(GetKeysResponse{
+				Keys: keyring.ListKeys(r.Context()),
+			})
+		case http.MethodPost:
+			var body CreateKeyBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			key, err := keyring.CreateKey(r.Context(), &tenant.Key{
+				TenantID:          body.TenantID,
+				RateLimit:         body.RateLimit,
+				Burst:             body.Burst,
+				MaxSubscriptions:  body.MaxSubscriptions,
+				SignatureRequired: body.SignatureRequired,
+			})
+			if errors.Is(err, tenant.ErrInvalidKey) {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(key)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+}
+
+// AdminKeyHandler serves api key: GET, DELETE /admin/keys/:id, keys configured at startup can not be deleted
+func AdminKeyHandler(keyring tenant.Keyring) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[1] != "admin" || parts[2] != "keys" {
+			http.NotFound(w, r)
+			return
+		}
+		id := parts[3]
+
+		switch r.Method {
+		case http.MethodGet:
+			key, err := keyring.GetKey(r.Context(), id)
+			if err != nil {
+				writeKeyError(w, err)
+				return
+			}
+			// secret is returned only when key is created
+			json.NewEncoder(w).Encode(key.Redacted())
+		case http.MethodDelete:
+			if err := keyring.DeleteKey(r.Context(), id); err != nil {
+				writeKeyError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+}
+
+// writeKeyError writes not found response for unknown key, conflict for static key, otherwise internal server error
+func writeKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, tenant.ErrKeyNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, tenant.ErrStaticKey):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,451 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/veljkomatic/be-homework/pkg/ratelimit"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
+)
+
+const (
+	// APIKeyHeader is the header of api key identifying tenant of the caller
+	APIKeyHeader = "X-API-Key"
+	// APIKeyIDHeader is the header of id of api key which signed the request, secret is not sent with signed request
+	APIKeyIDHeader = "X-API-Key-ID"
+	// APITimestampHeader is the header of unix timestamp of signed request
+	APITimestampHeader = "X-API-Timestamp"
+	// APISignatureHeader is the header of signature of the request, see tenant.Sign
+	APISignatureHeader = "X-API-Signature"
+	// AdminKeyHeader is the header of admin key, it is required by admin endpoints
+	AdminKeyHeader = "X-Admin-Key"
+	// apiKeyQueryParameter is used by websocket and server-sent events clients which can not set headers
+	apiKeyQueryParameter = "api_key"
+	// apiKeyMetadata is the gRPC metadata of api key
+	apiKeyMetadata = "x-api-key"
+	// retryAfterMetadata is the gRPC metadata of seconds after which rate limited call can be retried
+	retryAfterMetadata = "retry-after"
+	// maxSignatureAge is the maximum difference between timestamp of signed request and the server time
+	maxSignatureAge = 5 * time.Minute
+	// maxSignedBodySize is the maximum size of body of signed request
+	maxSignedBodySize = 1 << 20
+	// signatureCleanupInterval is the interval in which expired signatures of accepted requests are dropped
+	signatureCleanupInterval = time.Minute
+)
+
+// codes of errors returned by authentication and limits
+const (
+	UnauthorizedCode  = "unauthorized"
+	RateLimitedCode   = "rate_limited"
+	QuotaExceededCode = "quota_exceeded"
+)
+
+// AuthConfig is the configuration of authentication and limits of api requests
+type AuthConfig struct {
+	Keyring tenant.Keyring
+	// AdminKey is the key of admin endpoints, they are disabled if it is empty
+	AdminKey string
+	// KeyRate is the default rate limit of api keys, it is used when key does not have its own rate limit
+	KeyRate ratelimit.Rate
+	// IPRate is the rate limit of every client ip address, it is applied before authentication, it is disabled by default
+	IPRate ratelimit.Rate
+	// ClientIPHeader is the header with client ip address set by trusted proxies, e.g. X-Forwarded-For or X-Real-IP,
+	// it is used only for requests from TrustedProxies, remote address of the connection is the client ip otherwise
+	ClientIPHeader string
+	TrustedProxies []*net.IPNet
+	// MaxSubscriptions is the default quota of addresses subscribed by tenant of api key, 0 is unlimited
+	MaxSubscriptions int
+}
+
+// Authenticator authenticates callers by api keys and limits their requests,
+// it is shared by rest and gRPC servers, so both are limited by the same buckets
+type Authenticator struct {
+	config     AuthConfig
+	limiter    *ratelimit.Limiter
+	signatures *signatureCache
+}
+
+func NewAuthenticator(config AuthConfig) *Authenticator {
+	return &Authenticator{
+		config:     config,
+		limiter:    ratelimit.NewLimiter(),
+		signatures: newSignatureCache(),
+	}
+}
+
+// authError is error of authentication or rate limit, it is written as structured error response
+type authError struct {
+	statusCode int
+	code       string
+	message    string
+	retryAfter time.Duration
+}
+
+func unauthorized(message string) *authError {
+	return &authError{statusCode: http.StatusUnauthorized, code: UnauthorizedCode, message: message}
+}
+
+// retryAfterSeconds returns whole seconds after which request can be retried, it is at least one second
+func (e *authError) retryAfterSeconds() int {
+	return int(math.Max(1, math.Ceil(e.retryAfter.Seconds())))
+}
+
+// limitIP takes a token from the bucket of client ip address
+func (a *Authenticator) limitIP(ip string) *authError {
+	if allowed, retryAfter := a.limiter.Allow("ip:"+ip, a.config.IPRate); !allowed {
+		return &authError{
+			statusCode: http.StatusTooManyRequests,
+			code:       RateLimitedCode,
+			message:    "rate limit of ip address exceeded",
+			retryAfter: retryAfter,
+		}
+	}
+	return nil
+}
+
+// limitKey takes a token from the bucket of api key, default rate limit is used if key does not have its own
+func (a *Authenticator) limitKey(key *tenant.Key) *authError {
+	rate := a.config.KeyRate
+	if key.RateLimit > 0 {
+		rate = ratelimit.Rate{PerSecond: key.RateLimit, Burst: key.Burst}
+	}
+	if rate.Burst == 0 {
+		rate.Burst = a.config.KeyRate.Burst
+	}
+	if allowed, retryAfter := a.limiter.Allow("key:"+key.ID, rate); !allowed {
+		return &authError{
+			statusCode: http.StatusTooManyRequests,
+			code:       RateLimitedCode,
+			message:    "rate limit of api key exceeded",
+			retryAfter: retryAfter,
+		}
+	}
+	return nil
+}
+
+// authenticate returns api key with the secret, keys which require signature are not accepted
+func (a *Authenticator) authenticate(ctx context.Context, secret string) (*tenant.Key, *authError) {
+	key, err := a.config.Keyring.Authenticate(ctx, secret)
+	if err != nil {
+		return nil, unauthorized(err.Error())
+	}
+	if key.SignatureRequired {
+		return nil, unauthorized("api key requires signed requests")
+	}
+	return key, nil
+}
+
+// verifySignature returns api key which signed the request, body of the request is read and restored
+func (a *Authenticator) verifySignature(r *http.Request) (*tenant.Key, *authError) {
+	key, err := a.config.Keyring.GetKey(r.Context(), r.Header.Get(APIKeyIDHeader))
+	if err != nil {
+		return nil, unauthorized(tenant.ErrUnknownKey.Error())
+	}
+	timestamp := r.Header.Get(APITimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, unauthorized("invalid request timestamp")
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return nil, unauthorized("request timestamp is too old or in the future")
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodySize+1))
+	if err != nil {
+		return nil, unauthorized("failed to read request body")
+	}
+	if len(body) > maxSignedBodySize {
+		return nil, &authError{statusCode: http.StatusRequestEntityTooLarge, message: "request body is too large"}
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	signature := r.Header.Get(APISignatureHeader)
+	expected := tenant.Sign(key.Secret, timestamp, r.Method, r.URL.RequestURI(), body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, unauthorized("invalid request signature")
+	}
+	// signature is valid until the timestamp is too old, request with the same signature is a replay
+	if !a.signatures.add(key.ID+":"+signature, time.Unix(unix, 0).Add(maxSignatureAge)) {
+		return nil, unauthorized("signed request was already accepted")
+	}
+	return key, nil
+}
+
+// signatureCache remembers signatures of accepted signed requests until their timestamps are too old,
+// so signed request can not be replayed while its timestamp is within the allowed clock skew
+type signatureCache struct {
+	// expirations of signatures by key id and signature
+	expirations map[string]time.Time
+	lastCleanup time.Time
+	mutex       sync.Mutex
+}
+
+func newSignatureCache() *signatureCache {
+	return &signatureCache{
+		expirations: make(map[string]time.Time),
+		lastCleanup: time.Now(),
+	}
+}
+
+// add remembers signature until it expires, false is returned if signature is already remembered
+func (c *signatureCache) add(signature string, expiresAt time.Time) bool {
+	now := time.Now()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if now.Sub(c.lastCleanup) >= signatureCleanupInterval {
+		c.lastCleanup = now
+		for s, expiration := range c.expirations {
+			if expiration.Before(now) {
+				delete(c.expirations, s)
+			}
+		}
+	}
+	if expiration, exists := c.expirations[signature]; exists && !expiration.Before(now) {
+		return false
+	}
+	c.expirations[signature] = expiresAt
+	return true
+}
+
+// tenantOf returns tenant of the caller with quota of its api key
+func (a *Authenticator) tenantOf(key *tenant.Key) *tenant.Tenant {
+	caller := &tenant.Tenant{
+		ID:               tenant.DefaultID,
+		MaxSubscriptions: a.config.MaxSubscriptions,
+	}
+	if key != nil {
+		caller.ID = key.TenantID
+		if key.MaxSubscriptions > 0 {
+			caller.MaxSubscriptions = key.MaxSubscriptions
+		}
+	}
+	return caller
+}
+
+// withAuth limits requests of client ip address, resolves tenant of the caller and limits requests of its api key.
+// Caller is authenticated by api key or by request signed with secret of the key, when X-API-Key-ID header is set.
+// Without any api key every caller is the default tenant. Admin endpoints are authenticated by admin key.
+func withAuth(auth *Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authErr := auth.limitIP(auth.clientIP(r.RemoteAddr, r.Header.Get(auth.config.ClientIPHeader))); authErr != nil {
+			writeAuthError(w, authErr)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !auth.config.Keyring.Enabled() {
+			next.ServeHTTP(w, r.WithContext(tenant.NewContext(r.Context(), auth.tenantOf(nil))))
+			return
+		}
+
+		var key *tenant.Key
+		var authErr *authError
+		if r.Header.Get(APIKeyIDHeader) != "" {
+			key, authErr = auth.verifySignature(r)
+		} else {
+			secret := r.Header.Get(APIKeyHeader)
+			if secret == "" {
+				secret = r.URL.Query().Get(apiKeyQueryParameter)
+			}
+			key, authErr = auth.authenticate(r.Context(), secret)
+		}
+		if authErr == nil {
+			authErr = auth.limitKey(key)
+		}
+		if authErr != nil {
+			writeAuthError(w, authErr)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.NewContext(r.Context(), auth.tenantOf(key))))
+	})
+}
+
+// withAdmin rejects requests without admin key, admin endpoints are not found if admin key is not configured
+func withAdmin(auth *Authenticator, next httpHandler) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if auth.config.AdminKey == "" {
+			http.NotFound(w, r)
+			return
+		}
+		adminKey := r.Header.Get(AdminKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(adminKey), []byte(auth.config.AdminKey)) != 1 {
+			writeAuthError(w, unauthorized("invalid admin key"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// writeAuthError writes structured error response, rate limited response has Retry-After header
+func writeAuthError(w http.ResponseWriter, err *authError) {
+	response := ErrorResponse{
+		Error: err.message,
+		Code:  err.code,
+	}
+	if err.retryAfter > 0 {
+		response.RetryAfter = err.retryAfterSeconds()
+		w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+	}
+	writeErrorResponse(w, err.statusCode, response)
+}
+
+// clientIP returns ip address of the client, it is read from the client ip header only when remote address
+// is a trusted proxy. Header can be a list of addresses appended by proxies, the last address which is not
+// a trusted proxy is the client, addresses before it can be set by the client itself.
+func (a *Authenticator) clientIP(remoteAddr string, header string) string {
+	ip := remoteIP(remoteAddr)
+	if a.config.ClientIPHeader == "" || header == "" || !a.trustedProxy(ip) {
+		return ip
+	}
+	addresses := strings.Split(header, ",")
+	for i := len(addresses) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(addresses[i])
+		if net.ParseIP(address) == nil {
+			break
+		}
+		ip = address
+		if !a.trustedProxy(address) {
+			break
+		}
+	}
+	return ip
+}
+
+// trustedProxy checks if ip address belongs to trusted proxies
+func (a *Authenticator) trustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range a.config.TrustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns ip address of the remote address
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// ParseTrustedProxies parses ip addresses and CIDR networks of trusted proxies
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// grpcAuthContext returns context with tenant of the caller resolved from api key metadata,
+// calls are limited by the same rate limits as rest requests. Signed requests are supported only by rest api.
+func grpcAuthContext(ctx context.Context, auth *Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok {
+		// client ip header of trusted proxy is passed in metadata
+		var header string
+		if values := md.Get(auth.config.ClientIPHeader); auth.config.ClientIPHeader != "" && len(values) > 0 {
+			header = strings.Join(values, ",")
+		}
+		if authErr := auth.limitIP(auth.clientIP(p.Addr.String(), header)); authErr != nil {
+			return nil, grpcAuthError(ctx, authErr)
+		}
+	}
+	if !auth.config.Keyring.Enabled() {
+		return tenant.NewContext(ctx, auth.tenantOf(nil)), nil
+	}
+	var secret string
+	if values := md.Get(apiKeyMetadata); len(values) > 0 {
+		secret = values[0]
+	}
+	key, authErr := auth.authenticate(ctx, secret)
+	if authErr == nil {
+		authErr = auth.limitKey(key)
+	}
+	if authErr != nil {
+		return nil, grpcAuthError(ctx, authErr)
+	}
+	return tenant.NewContext(ctx, auth.tenantOf(key)), nil
+}
+
+// grpcAuthError converts error to gRPC status, seconds after which rate limited call can be retried
+// are sent in retry-after header
+func grpcAuthError(ctx context.Context, err *authError) error {
+	if err.statusCode == http.StatusUnauthorized {
+		return status.Error(codes.Unauthenticated, err.message)
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, strconv.Itoa(err.retryAfterSeconds())))
+	return status.Error(codes.ResourceExhausted, fmt.Sprintf("%s, retry after %d seconds", err.message, err.retryAfterSeconds()))
+}
+
+// grpcUnaryAuthInterceptor passes tenant of the caller to unary calls
+func grpcUnaryAuthInterceptor(auth *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := grpcAuthContext(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// grpcStreamAuthInterceptor passes tenant of the caller to streaming calls
+func grpcStreamAuthInterceptor(auth *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := grpcAuthContext(stream.Context(), auth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// tenantServerStream is server stream with context carrying tenant of the caller
+type tenantServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,354 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	parserv1 "github.com/veljkomatic/be-homework/api/parser/v1"
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/parser"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+)
+
+// StartGRPCServer starts gRPC server, it shares the service and authenticator with the rest server,
+// api key of the caller is passed in x-api-key metadata
+func StartGRPCServer(service Service, port string, auth *Authenticator) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatalln("Error starting gRPC server:", err)
+	}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcUnaryAuthInterceptor(auth)),
+		grpc.StreamInterceptor(grpcStreamAuthInterceptor(auth)),
+	)
+	parserv1.RegisterParserServiceServer(server, NewGRPCServer(service))
+
+	log.Printf("gRPC server started on port %s\n", port)
+	if err := server.Serve(listener); err != nil {
+		log.Fatalln("Error serving gRPC:", err)
+	}
+}
+
+// backfillErrorTrailer is the trailer of subscribe response with the reason why backfill was not started
+const backfillErrorTrailer = "x-backfill-error"
+
+var _ parserv1.ParserServiceServer = (*grpcServer)(nil)
+
+type grpcServer struct {
+	parserv1.UnimplementedParserServiceServer
+	service Service
+}
+
+func NewGRPCServer(service Service) parserv1.ParserServiceServer {
+	return &grpcServer{
+		service: service,
+	}
+}
+
+func (s *grpcServer) GetCurrentBlock(ctx context.Context, request *parserv1.GetCurrentBlockRequest) (*parserv1.GetCurrentBlockResponse, error) {
+	return &parserv1.GetCurrentBlockResponse{
+		BlockNumber: int64(s.service.GetCurrentBlockNumber(ctx)),
+	}, nil
+}
+
+func (s *grpcServer) Subscribe(ctx context.Context, request *parserv1.SubscribeRequest) (*parserv1.SubscribeResponse, error) {
+	if request.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	var criteria *subscriber.Criteria
+	if request.GetCriteria() != nil {
+		criteria = toSubscriptionCriteria(request.GetCriteria())
+		if err := criteria.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	err := s.service.Subscribe(ctx, request.GetAddress(), criteria)
+	if errors.Is(err, blockchain.ErrInvalidAddress) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, subscriber.ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	response := &parserv1.SubscribeResponse{
+		Subscribed: err == nil,
+	}
+	if response.Subscribed {
+		var fromBlock *blockchain.BlockNumber
+		if request.FromBlock != nil {
+			fromBlock = blockchain.NewBlockNumberBuilder().FromInt64(request.GetFromBlock()).Pointer()
+		}
+		job, err := s.service.Backfill(ctx, request.GetAddress(), fromBlock)
+		if err != nil {
+			log.Println("error starting backfill of address", request.GetAddress(), err)
+			// response message has no field for it, reason is sent in the trailer
+			grpc.SetTrailer(ctx, metadata.Pairs(backfillErrorTrailer, err.Error()))
+		}
+		if job != nil {
+			response.BackfillJobId = job.ID
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcServer) Unsubscribe(ctx context.Context, request *parserv1.UnsubscribeRequest) (*parserv1.UnsubscribeResponse, error) {
+	if request.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	return &parserv1.UnsubscribeResponse{
+		Unsubscribed: s.service.Unsubscribe(ctx, request.GetAddress(), request.GetPurge()),
+	}, nil
+}
+
+func (s *grpcServer) GetTransactions(ctx context.Context, request *parserv1.GetTransactionsRequest) (*parserv1.GetTransactionsResponse, error) {
+	if request.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	// request is parsed as query parameters of the rest endpoint, so both apis validate it the same way
+	query, err := parseTransactionsQuery(transactionsQueryValues(request))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !s.service.IsSubscribed(ctx, request.GetAddress()) {
+		return nil, status.Error(codes.NotFound, subscriber.ErrNotSubscribed.Error())
+	}
+	page := s.service.GetTransactions(ctx, request.GetAddress(), query)
+	if page == nil {
+		return nil, status.Error(codes.Internal, "failed to get transactions")
+	}
+
+	response := &parserv1.GetTransactionsResponse{
+		Transactions: make([]*parserv1.Transaction, 0, len(page.Transactions)),
+		NextCursor:   page.NextCursor,
+	}
+	for _, tx := range page.Transactions {
+		response.Transactions = append(response.Transactions, toProtoTransaction(tx))
+	}
+	return response, nil
+}
+
+func (s *grpcServer) WatchTransactions(request *parserv1.WatchTransactionsRequest, stream parserv1.ParserService_WatchTransactionsServer) error {
+	addresses := make([]string, 0, len(request.GetAddresses()))
+	for _, address := range request.GetAddresses() {
+		if address = strings.ToLower(strings.TrimSpace(address)); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return status.Error(codes.InvalidArgument, "address is required")
+	}
+	for _, address := range addresses {
+		if !s.service.IsSubscribed(stream.Context(), address) {
+			return status.Errorf(codes.NotFound, "address %s is not subscribed", address)
+		}
+	}
+	var after *streamCursor
+	if request.GetCursor() != "" {
+		var err error
+		if after, err = parseStreamCursor(request.GetCursor()); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	ctx := stream.Context()
+	watch := newTransactionWatch(ctx, s.service, addresses)
+	defer watch.close()
+
+	emit := func(cursor streamCursor, event *StreamEvent) error {
+		return stream.Send(&parserv1.WatchTransactionsResponse{
+			Address:     event.Address,
+			Cursor:      cursor.String(),
+			Transaction: toProtoTransaction(event.Transaction),
+		})
+	}
+	if after != nil {
+		if err := watch.replay(ctx, *after, emit); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case tx, ok := <-watch.transactions():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "stream dropped because client is too slow, resume with the last cursor")
+			}
+			cursor, event := watch.event(ctx, tx)
+			if event == nil {
+				continue
+			}
+			if err := emit(cursor, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// transactionsQueryValues converts request to query parameters of the rest endpoint
+func transactionsQueryValues(request *parserv1.GetTransactionsRequest) url.Values {
+	values := url.Values{}
+	setValue := func(name string, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	setOptionalInt64 := func(name string, value *int64) {
+		if value != nil {
+			values.Set(name, strconv.FormatInt(*value, 10))
+		}
+	}
+
+	if request.GetLimit() != 0 {
+		values.Set("limit", strconv.Itoa(int(request.GetLimit())))
+	}
+	setValue("cursor", request.GetCursor())
+	switch request.GetOrder() {
+	case parserv1.Order_ORDER_ASC:
+		values.Set("order", string(transaction.AscendingOrder))
+	case parserv1.Order_ORDER_DESC:
+		values.Set("order", string(transaction.DescendingOrder))
+	}
+	switch request.GetDirection() {
+	case parserv1.Direction_DIRECTION_INBOUND:
+		values.Set("direction", string(transaction.InboundDirection))
+	case parserv1.Direction_DIRECTION_OUTBOUND:
+		values.Set("direction", string(transaction.OutboundDirection))
+	case parserv1.Direction_DIRECTION_SELF:
+		values.Set("direction", string(transaction.SelfDirection))
+	}
+	setOptionalInt64("fromBlock", request.FromBlock)
+	setOptionalInt64("toBlock", request.ToBlock)
+	setOptionalInt64("fromTime", request.FromTime)
+	setOptionalInt64("toTime", request.ToTime)
+	setValue("minValue", request.GetMinValue())
+	setValue("maxValue", request.GetMaxValue())
+	setValue("counterparty", request.GetCounterparty())
+	switch request.GetExecution() {
+	case parserv1.Execution_EXECUTION_SUCCEEDED:
+		values.Set("execution", string(transaction.SucceededExecution))
+	case parserv1.Execution_EXECUTION_FAILED:
+		values.Set("execution", string(transaction.FailedExecution))
+	}
+	return values
+}
+
+func toProtoTransaction(tx *parser.Transaction) *parserv1.Transaction {
+	accessList := make([]*parserv1.AccessTuple, 0, len(tx.AccessList))
+	for _, tuple := range tx.AccessList {
+		accessList = append(accessList, &parserv1.AccessTuple{
+			Address:     tuple.Address.String(),
+			StorageKeys: hashStrings(tuple.StorageKeys),
+		})
+	}
+	return &parserv1.Transaction{
+		Hash:                 tx.Hash.String(),
+		Nonce:                tx.Nonce.String(),
+		BlockHash:            tx.BlockHash.String(),
+		BlockNumber:          tx.BlockNumber.String(),
+		TransactionIndex:     tx.TransactionIndex.String(),
+		From:                 tx.From.String(),
+		To:                   tx.To.String(),
+		Value:                tx.Value.String(),
+		GasPrice:             tx.GasPrice.String(),
+		Gas:                  tx.Gas.String(),
+		Input:                tx.Input.String(),
+		BlockTimestamp:       tx.BlockTimestamp,
+		Status:               string(tx.Status),
+		Type:                 tx.Type.String(),
+		ChainId:              optionalQuantityString(tx.ChainID),
+		MaxFeePerGas:         optionalQuantityString(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: optionalQuantityString(tx.MaxPriorityFeePerGas),
+		AccessList:           accessList,
+		MaxFeePerBlobGas:     optionalQuantityString(tx.MaxFeePerBlobGas),
+		BlobVersionedHashes:  hashStrings(tx.BlobVersionedHashes),
+		V:                    tx.V.String(),
+		R:                    tx.R.String(),
+		S:                    tx.S.String(),
+		YParity:              optionalQuantityString(tx.YParity),
+		Direction:            string(tx.Direction),
+		Execution:            string(tx.Execution),
+		Receipt:              toProtoReceipt(tx.Receipt),
+	}
+}
+
+// toProtoReceipt converts receipt of the transaction, it is nil when transaction is stored without receipt
+func toProtoReceipt(receipt *blockchain.Receipt) *parserv1.Receipt {
+	if receipt == nil {
+		return nil
+	}
+	logs := make([]*parserv1.Log, 0, len(receipt.Logs))
+	for _, receiptLog := range receipt.Logs {
+		logs = append(logs, &parserv1.Log{
+			Address:          receiptLog.Address.String(),
+			Topics:           hashStrings(receiptLog.Topics),
+			Data:             receiptLog.Data.String(),
+			BlockNumber:      receiptLog.BlockNumber.String(),
+			BlockHash:        receiptLog.BlockHash.String(),
+			TransactionHash:  receiptLog.TransactionHash.String(),
+			TransactionIndex: receiptLog.TransactionIndex.String(),
+			LogIndex:         receiptLog.LogIndex.String(),
+			Removed:          receiptLog.Removed,
+		})
+	}
+	return &parserv1.Receipt{
+		TransactionHash:   receipt.TransactionHash.String(),
+		TransactionIndex:  receipt.TransactionIndex.String(),
+		BlockHash:         receipt.BlockHash.String(),
+		BlockNumber:       receipt.BlockNumber.String(),
+		From:              receipt.From.String(),
+		To:                receipt.To.String(),
+		Type:              receipt.Type.String(),
+		CumulativeGasUsed: receipt.CumulativeGasUsed.String(),
+		GasUsed:           receipt.GasUsed.String(),
+		EffectiveGasPrice: receipt.EffectiveGasPrice.String(),
+		ContractAddress:   receipt.ContractAddress.String(),
+		Logs:              logs,
+		LogsBloom:         receipt.LogsBloom.String(),
+		Status:            optionalQuantityString(receipt.Status),
+		Root:              receipt.Root.String(),
+		BlobGasUsed:       optionalQuantityString(receipt.BlobGasUsed),
+		BlobGasPrice:      optionalQuantityString(receipt.BlobGasPrice),
+	}
+}
+
+// optionalQuantityString returns hex encoded quantity, it is empty when quantity is missing
+func optionalQuantityString(quantity *blockchain.Quantity) string {
+	if quantity == nil {
+		return ""
+	}
+	return quantity.String()
+}
+
+func hashStrings(hashes []blockchain.Hash) []string {
+	values := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		values = append(values, hash.String())
+	}
+	return values
+}
+
+// toSubscriptionCriteria converts criteria of subscribe request
+func toSubscriptionCriteria(criteria *parserv1.SubscriptionCriteria) *subscriber.Criteria {
+	kinds := make([]blockchain.TransactionKind, 0, len(criteria.GetKinds()))
+	for _, kind := range criteria.GetKinds() {
+		kinds = append(kinds, blockchain.TransactionKind(kind))
+	}
+	return &subscriber.Criteria{
+		Direction:       subscriber.Direction(criteria.GetDirection()),
+		Kinds:           kinds,
+		MinValue:        criteria.GetMinValue(),
+		MethodSelectors: criteria.GetMethodSelectors(),
+	}
+}
@@ -2,7 +2,12 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/veljkomatic/be-homework/cmd/parser-service/internal/backfill"
 	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/parser"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+	"log"
 	"net/http"
 	"strings"
 )
@@ -32,10 +37,20 @@ func GetCurrentBlockNumberHandler(service Service) httpHandler {
 
 type SubscribeResponse struct {
 	Subscribed bool `json:"subscribed"`
+	// BackfillJob is the job backfilling historical transactions of the address
+	BackfillJob *backfill.Job `json:"backfill_job,omitempty"`
+	// BackfillError is the reason why backfill was not started, address is subscribed regardless of it
+	BackfillError string `json:"backfill_error,omitempty"`
 }
 
 type SubscribeBody struct {
 	Address string `json:"address"`
+	// FromBlock is the block from which historical transactions are backfilled,
+	// if it is not set, the most recent blocks are backfilled
+	FromBlock *int64 `json:"from_block,omitempty"`
+	// Criteria of matched transactions, if they are not set, subscribed address keeps its criteria
+	// and new subscription matches every transaction
+	*subscriber.Criteria
 }
 
 func SubscribeHandler(service Service) httpHandler {
@@ -53,20 +68,55 @@ func SubscribeHandler(service Service) httpHandler {
 			return
 		}
 
-		subscribed := service.Subscribe(r.Context(), body.Address)
+		if body.Criteria != nil {
+			if err := body.Criteria.Validate(); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		err = service.Subscribe(r.Context(), body.Address, body.Criteria)
+		if errors.Is(err, blockchain.ErrInvalidAddress) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, subscriber.ErrQuotaExceeded) {
+			writeErrorResponse(w, http.StatusForbidden, ErrorResponse{
+				Error: err.Error(),
+				Code:  QuotaExceededCode,
+			})
+			return
+		}
 		resp := SubscribeResponse{
-			Subscribed: subscribed,
+			Subscribed: err == nil,
+		}
+		if resp.Subscribed {
+			var fromBlock *blockchain.BlockNumber
+			if body.FromBlock != nil {
+				fromBlock = blockchain.NewBlockNumberBuilder().FromInt64(*body.FromBlock).Pointer()
+			}
+			job, err := service.Backfill(r.Context(), body.Address, fromBlock)
+			if err != nil {
+				log.Println("error starting backfill of address", body.Address, err)
+				resp.BackfillError = err.Error()
+			}
+			resp.BackfillJob = job
 		}
 		json.NewEncoder(w).Encode(resp)
 		return
 	}
 }
 
-// GetTransactionsResponse TODO in future do not return blockchain.Transaction rather some DTO
+// GetTransactionsResponse TODO in future do not return parser.Transaction rather some DTO
 type GetTransactionsResponse struct {
-	Transactions []*blockchain.Transaction `json:"transactions"`
+	Transactions []*parser.Transaction `json:"transactions"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
 }
 
+// GetTransactionsHandler returns page of transactions for address,
+// supported query parameters are limit, cursor, order (asc, desc), direction (inbound, outbound),
+// fromBlock, toBlock, fromTime, toTime (unix seconds), minValue, maxValue (wei), counterparty
+// and execution (succeeded, failed).
 func GetTransactionsHandler(service Service) httpHandler {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -80,14 +130,181 @@ func GetTransactionsHandler(service Service) httpHandler {
 			http.NotFound(w, r)
 			return
 		}
+		if !service.IsSubscribed(r.Context(), parts[2]) {
+			writeError(w, http.StatusNotFound, subscriber.ErrNotSubscribed.Error())
+			return
+		}
+		if len(parts) > 3 && parts[3] == "stream" {
+			streamTransactions(w, r, service, []string{strings.ToLower(parts[2])})
+			return
+		}
+
+		query, err := parseTransactionsQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
 		address := parts[2]
-		transactions := service.GetTransactions(r.Context(), address)
+		page := service.GetTransactions(r.Context(), address, query)
+		if page == nil {
+			writeError(w, http.StatusInternalServerError, "failed to get transactions")
+			return
+		}
 
 		resp := GetTransactionsResponse{
-			Transactions: transactions,
+			Transactions: page.Transactions,
+			NextCursor:   page.NextCursor,
+		}
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+}
+
+// GetTokenTransfersResponse is a page of token transfers of an address
+type GetTokenTransfersResponse struct {
+	Transfers  []*parser.TokenTransfer `json:"transfers"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+// GetTokenTransfersHandler returns page of ERC-20, ERC-721 and ERC-1155 token transfers sent or received by address,
+// supported query parameters are limit, cursor, order (asc, desc), direction (inbound, outbound),
+// fromBlock, toBlock, token (contract address) and standard (erc20, erc721, erc1155).
+func GetTokenTransfersHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) != 3 || parts[1] != "token-transfers" {
+			http.NotFound(w, r)
+			return
+		}
+		address := parts[2]
+		if !service.IsSubscribed(r.Context(), address) {
+			writeError(w, http.StatusNotFound, subscriber.ErrNotSubscribed.Error())
+			return
+		}
+
+		query, err := parseTokenTransfersQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		page := service.GetTokenTransfers(r.Context(), address, query)
+		if page == nil {
+			writeError(w, http.StatusInternalServerError, "failed to get token transfers")
+			return
+		}
+
+		resp := GetTokenTransfersResponse{
+			Transfers:  page.Transfers,
+			NextCursor: page.NextCursor,
+		}
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+}
+
+// GetInternalTransfersResponse is a page of internal transfers of an address
+type GetInternalTransfersResponse struct {
+	Transfers  []*parser.InternalTransfer `json:"transfers"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+// GetInternalTransfersHandler returns page of internal transfers of ETH sent or received by address in calls inside of transactions,
+// supported query parameters are limit, cursor, order (asc, desc), direction (inbound, outbound), fromBlock and toBlock.
+// Internal transfers are found only when tracing is enabled.
+func GetInternalTransfersHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) != 3 || parts[1] != "internal-transfers" {
+			http.NotFound(w, r)
+			return
+		}
+		address := parts[2]
+		if !service.IsSubscribed(r.Context(), address) {
+			writeError(w, http.StatusNotFound, subscriber.ErrNotSubscribed.Error())
+			return
+		}
+
+		query, err := parseInternalTransfersQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		page := service.GetInternalTransfers(r.Context(), address, query)
+		if page == nil {
+			writeError(w, http.StatusInternalServerError, "failed to get internal transfers")
+			return
+		}
+
+		resp := GetInternalTransfersResponse{
+			Transfers:  page.Transfers,
+			NextCursor: page.NextCursor,
 		}
 		json.NewEncoder(w).Encode(resp)
 		return
 	}
 }
+
+func GetBackfillJobHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) < 3 || parts[1] != "backfill" {
+			http.NotFound(w, r)
+			return
+		}
+
+		job, err := service.GetBackfillJob(r.Context(), parts[2])
+		if errors.Is(err, backfill.ErrJobNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+	// Code identifies errors of authentication and limits
+	Code string `json:"code,omitempty"`
+	// RetryAfter is the number of seconds after which rate limited request can be retried
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// writeError writes error response with given status code
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeErrorResponse(w, statusCode, ErrorResponse{
+		Error: message,
+	})
+}
+
+// writeErrorResponse writes structured error response with given status code
+func writeErrorResponse(w http.ResponseWriter, statusCode int, response ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
+)
+
+// parseTransactionsQuery parses transactions query from url query parameters
+func parseTransactionsQuery(values url.Values) (transaction.Query, error) {
+	var query transaction.Query
+	var err error
+
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit <= 0 {
+			return query, fmt.Errorf("invalid limit %s", limit)
+		}
+	}
+	if cursor := values.Get("cursor"); cursor != "" {
+		query.After, err = transaction.ParseCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+	}
+	switch order := transaction.Order(values.Get("order")); order {
+	case "", transaction.AscendingOrder, transaction.DescendingOrder:
+		query.Order = order
+	default:
+		return query, fmt.Errorf("invalid order %s", order)
+	}
+	switch direction := transaction.Direction(values.Get("direction")); direction {
+	case "", transaction.InboundDirection, transaction.OutboundDirection, transaction.SelfDirection:
+		query.Direction = direction
+	default:
+		return query, fmt.Errorf("invalid direction %s", direction)
+	}
+	if query.FromBlock, err = parseOptionalInt64(values, "fromBlock"); err != nil {
+		return query, err
+	}
+	if query.ToBlock, err = parseOptionalInt64(values, "toBlock"); err != nil {
+		return query, err
+	}
+	if query.FromTime, err = parseOptionalInt64(values, "fromTime"); err != nil {
+		return query, err
+	}
+	if query.ToTime, err = parseOptionalInt64(values, "toTime"); err != nil {
+		return query, err
+	}
+	if query.MinValue, err = parseOptionalValue(values, "minValue"); err != nil {
+		return query, err
+	}
+	if query.MaxValue, err = parseOptionalValue(values, "maxValue"); err != nil {
+		return query, err
+	}
+	if counterparty := values.Get("counterparty"); counterparty != "" {
+		query.Counterparty, err = blockchain.ParseAddress(counterparty)
+		if err != nil {
+			return query, fmt.Errorf("invalid counterparty %s", counterparty)
+		}
+	}
+	switch execution := transaction.Execution(values.Get("execution")); execution {
+	case "", transaction.SucceededExecution, transaction.FailedExecution:
+		query.Execution = execution
+	default:
+		return query, fmt.Errorf("invalid execution %s", execution)
+	}
+	return query, nil
+}
+
+// parseTokenTransfersQuery parses token transfers query from url query parameters
+func parseTokenTransfersQuery(values url.Values) (transfer.Query, error) {
+	var query transfer.Query
+	var err error
+
+	if query.Query, err = parseRecordQuery(values); err != nil {
+		return query, err
+	}
+	if token := values.Get("token"); token != "" {
+		query.Token, err = blockchain.ParseAddress(token)
+		if err != nil {
+			return query, fmt.Errorf("invalid token %s", token)
+		}
+	}
+	if standard := blockchain.TokenStandard(values.Get("standard")); standard != "" {
+		if !standard.IsValid() {
+			return query, fmt.Errorf("invalid standard %s", standard)
+		}
+		query.Standard = standard
+	}
+	return query, nil
+}
+
+// parseInternalTransfersQuery parses internal transfers query from url query parameters
+func parseInternalTransfersQuery(values url.Values) (trace.Query, error) {
+	var query trace.Query
+	var err error
+
+	query.Query, err = parseRecordQuery(values)
+	return query, err
+}
+
+// parseRecordQuery parses limit, cursor, order, direction and block range of token or internal transfers query
+func parseRecordQuery(values url.Values) (record.Query, error) {
+	var query record.Query
+	var err error
+
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit <= 0 {
+			return query, fmt.Errorf("invalid limit %s", limit)
+		}
+	}
+	if cursor := values.Get("cursor"); cursor != "" {
+		query.After, err = record.ParseCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+	}
+	switch order := transaction.Order(values.Get("order")); order {
+	case "", transaction.AscendingOrder, transaction.DescendingOrder:
+		query.Order = order
+	default:
+		return query, fmt.Errorf("invalid order %s", order)
+	}
+	switch direction := transaction.Direction(values.Get("direction")); direction {
+	case "", transaction.InboundDirection, transaction.OutboundDirection, transaction.SelfDirection:
+		query.Direction = direction
+	default:
+		return query, fmt.Errorf("invalid direction %s", direction)
+	}
+	if query.FromBlock, err = parseOptionalInt64(values, "fromBlock"); err != nil {
+		return query, err
+	}
+	if query.ToBlock, err = parseOptionalInt64(values, "toBlock"); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func parseOptionalInt64(values url.Values, name string) (*int64, error) {
+	value := values.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		return nil, fmt.Errorf("invalid %s %s", name, value)
+	}
+	return &parsed, nil
+}
+
+// parseOptionalValue parses value in wei, as decimal or 0x prefixed hex number
+func parseOptionalValue(values url.Values, name string) (*big.Int, error) {
+	value := values.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, ok := new(big.Int).SetString(value, 0)
+	if !ok || parsed.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s %s", name, value)
+	}
+	return parsed, nil
+}
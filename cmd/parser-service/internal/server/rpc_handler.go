@@ -0,0 +1,291 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/common/jsonrpc"
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+)
+
+const maxRPCBodySize = 1 << 20
+
+// rpcMethod executes jsonrpc method with its params and returns result
+type rpcMethod func(ctx context.Context, service Service, params json.RawMessage) (any, *jsonrpc.Error)
+
+// rpcMethods are methods exposed by jsonrpc endpoint, params are positional as in ethereum jsonrpc
+var rpcMethods = map[string]rpcMethod{
+	// parser_getCurrentBlock() returns hex encoded last processed block number
+	"parser_getCurrentBlock": rpcGetCurrentBlock,
+	// parser_subscribe(address, fromBlock?) subscribes address and starts backfill from block
+	"parser_subscribe": rpcSubscribe,
+	// parser_unsubscribe(address) unsubscribes address
+	"parser_unsubscribe": rpcUnsubscribe,
+	// parser_getTransactions(address, query?) returns page of transactions,
+	// query object has the same fields as query parameters of the rest endpoint
+	"parser_getTransactions": rpcGetTransactions,
+}
+
+// RPCHandler serves jsonrpc 2.0 requests, batches and notifications
+func RPCHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRPCBodySize))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = bytes.TrimSpace(body)
+
+		if len(body) > 0 && body[0] == '[' {
+			var batch []json.RawMessage
+			if err := json.Unmarshal(body, &batch); err != nil {
+				writeRPCResponse(w, jsonrpc.NewErrorResponse(jsonrpc.NullID, jsonrpc.NewError(jsonrpc.ParseErrorCode, "parse error")))
+				return
+			}
+			if len(batch) == 0 {
+				writeRPCResponse(w, jsonrpc.NewErrorResponse(jsonrpc.NullID, jsonrpc.NewError(jsonrpc.InvalidRequestCode, "empty batch")))
+				return
+			}
+			responses := make(jsonrpc.BatchResponse, 0, len(batch))
+			for _, request := range batch {
+				if response := handleRPCRequest(r.Context(), service, request); response != nil {
+					responses = append(responses, response)
+				}
+			}
+			if len(responses) == 0 {
+				// batch of notifications is not replied
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			writeRPCResponse(w, responses)
+			return
+		}
+
+		if !json.Valid(body) {
+			writeRPCResponse(w, jsonrpc.NewErrorResponse(jsonrpc.NullID, jsonrpc.NewError(jsonrpc.ParseErrorCode, "parse error")))
+			return
+		}
+		response := handleRPCRequest(r.Context(), service, body)
+		if response == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeRPCResponse(w, response)
+		return
+	}
+}
+
+// handleRPCRequest executes single request, response is nil for notification
+func handleRPCRequest(ctx context.Context, service Service, body json.RawMessage) *jsonrpc.Response {
+	var request jsonrpc.Request
+	if err := json.Unmarshal(body, &request); err != nil || request.Version != "2.0" || request.Method == "" {
+		id := request.ID
+		if err != nil {
+			// id is not trusted when request could not be read
+			id = jsonrpc.NullID
+		}
+		return jsonrpc.NewErrorResponse(id, jsonrpc.NewError(jsonrpc.InvalidRequestCode, "invalid request"))
+	}
+
+	var response *jsonrpc.Response
+	method, ok := rpcMethods[request.Method]
+	if !ok {
+		response = jsonrpc.NewErrorResponse(request.ID, jsonrpc.NewError(jsonrpc.MethodNotFoundCode, fmt.Sprintf("method %s not found", request.Method)))
+	} else if result, rpcErr := method(ctx, service, request.Params); rpcErr != nil {
+		response = jsonrpc.NewErrorResponse(request.ID, rpcErr)
+	} else if encoded, err := json.Marshal(result); err != nil {
+		log.Println("error encoding result of method", request.Method, err)
+		response = jsonrpc.NewErrorResponse(request.ID, jsonrpc.NewError(jsonrpc.InternalErrorCode, "internal error"))
+	} else {
+		response = jsonrpc.NewResponse(request.ID, encoded)
+	}
+
+	if request.IsNotification() {
+		return nil
+	}
+	return response
+}
+
+func rpcGetCurrentBlock(ctx context.Context, service Service, params json.RawMessage) (any, *jsonrpc.Error) {
+	if _, err := parseRPCParams(params, 0); err != nil {
+		return nil, err
+	}
+	return blockchain.BlockNumber(service.GetCurrentBlockNumber(ctx)).ToHex(), nil
+}
+
+func rpcSubscribe(ctx context.Context, service Service, params json.RawMessage) (any, *jsonrpc.Error) {
+	args, rpcErr := parseRPCParams(params, 1, 3)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	address, rpcErr := parseRPCAddress(args[0])
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var fromBlock *blockchain.BlockNumber
+	if len(args) > 1 && !isRPCNull(args[1]) {
+		blockNumber, err := parseRPCInt64(args[1])
+		if err != nil || blockNumber < 0 {
+			return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, "invalid fromBlock")
+		}
+		fromBlock = blockchain.NewBlockNumberBuilder().FromInt64(blockNumber).Pointer()
+	}
+	var criteria *subscriber.Criteria
+	if len(args) > 2 && !isRPCNull(args[2]) {
+		criteria = &subscriber.Criteria{}
+		if err := json.Unmarshal(args[2], criteria); err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, "criteria has to be an object")
+		}
+		if err := criteria.Validate(); err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, err.Error())
+		}
+	}
+
+	err := service.Subscribe(ctx, address, criteria)
+	if errors.Is(err, blockchain.ErrInvalidAddress) {
+		return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, err.Error())
+	}
+	if errors.Is(err, subscriber.ErrQuotaExceeded) {
+		rpcErr := jsonrpc.NewError(jsonrpc.InvalidRequestCode, err.Error())
+		rpcErr.Data = QuotaExceededCode
+		return nil, rpcErr
+	}
+	resp := SubscribeResponse{
+		Subscribed: err == nil,
+	}
+	if resp.Subscribed {
+		job, err := service.Backfill(ctx, address, fromBlock)
+		if err != nil {
+			log.Println("error starting backfill of address", address, err)
+			resp.BackfillError = err.Error()
+		}
+		resp.BackfillJob = job
+	}
+	return resp, nil
+}
+
+func rpcUnsubscribe(ctx context.Context, service Service, params json.RawMessage) (any, *jsonrpc.Error) {
+	args, rpcErr := parseRPCParams(params, 1, 2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	address, rpcErr := parseRPCAddress(args[0])
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var purge bool
+	if len(args) > 1 && !isRPCNull(args[1]) {
+		if err := json.Unmarshal(args[1], &purge); err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, "purge has to be a boolean")
+		}
+	}
+	return service.Unsubscribe(ctx, address, purge), nil
+}
+
+func rpcGetTransactions(ctx context.Context, service Service, params json.RawMessage) (any, *jsonrpc.Error) {
+	args, rpcErr := parseRPCParams(params, 1, 2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	address, rpcErr := parseRPCAddress(args[0])
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// query object is parsed as query parameters of the rest endpoint, so both apis validate it the same way
+	values := url.Values{}
+	if len(args) > 1 && !isRPCNull(args[1]) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(args[1], &fields); err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, "query has to be an object")
+		}
+		for name, value := range fields {
+			var text string
+			if err := json.Unmarshal(value, &text); err != nil {
+				text = string(value)
+			}
+			values.Set(name, text)
+		}
+	}
+	query, err := parseTransactionsQuery(values)
+	if err != nil {
+		return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, err.Error())
+	}
+
+	if !service.IsSubscribed(ctx, address) {
+		return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, subscriber.ErrNotSubscribed.Error())
+	}
+	page := service.GetTransactions(ctx, address, query)
+	if page == nil {
+		return nil, jsonrpc.NewError(jsonrpc.InternalErrorCode, "failed to get transactions")
+	}
+	return GetTransactionsResponse{
+		Transactions: page.Transactions,
+		NextCursor:   page.NextCursor,
+	}, nil
+}
+
+// parseRPCParams parses positional params, number of params has to be between min and max,
+// max is equal to min if it is not passed
+func parseRPCParams(params json.RawMessage, min int, max ...int) ([]json.RawMessage, *jsonrpc.Error) {
+	maxParams := min
+	if len(max) > 0 {
+		maxParams = max[0]
+	}
+	args := make([]json.RawMessage, 0)
+	if len(params) > 0 && !isRPCNull(params) {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, "params have to be an array")
+		}
+	}
+	if len(args) < min || len(args) > maxParams {
+		expected := strconv.Itoa(min)
+		if maxParams != min {
+			expected = fmt.Sprintf("%d to %d", min, maxParams)
+		}
+		return nil, jsonrpc.NewError(jsonrpc.InvalidParamsCode, fmt.Sprintf("expected %s params, got %d", expected, len(args)))
+	}
+	return args, nil
+}
+
+func parseRPCAddress(param json.RawMessage) (string, *jsonrpc.Error) {
+	var address string
+	if err := json.Unmarshal(param, &address); err != nil || strings.TrimSpace(address) == "" {
+		return "", jsonrpc.NewError(jsonrpc.InvalidParamsCode, "address has to be a non empty string")
+	}
+	return address, nil
+}
+
+// parseRPCInt64 parses number or decimal or 0x prefixed hex string
+func parseRPCInt64(param json.RawMessage) (int64, error) {
+	var text string
+	if err := json.Unmarshal(param, &text); err != nil {
+		text = string(param)
+	}
+	return strconv.ParseInt(text, 0, 64)
+}
+
+func isRPCNull(param json.RawMessage) bool {
+	return string(bytes.TrimSpace(param)) == "null"
+}
+
+func writeRPCResponse(w http.ResponseWriter, response any) {
+	json.NewEncoder(w).Encode(response)
+}
@@ -5,11 +5,26 @@ import (
 	"net/http"
 )
 
-func StartServer(service Service, port string) {
+// StartServer starts rest server, api key of the caller is passed in X-API-Key header
+// (or api_key query parameter for streams), or request is signed with secret of the key.
+// Without any api key every caller is the default tenant. Websocket connections are accepted from the allowed origins.
+func StartServer(service Service, port string, auth *Authenticator, allowedOrigins []string) {
 	http.HandleFunc("/block-number", GetCurrentBlockNumberHandler(service))
 	http.HandleFunc("/subscribe", SubscribeHandler(service))
+	http.HandleFunc("/subscriptions", GetSubscriptionsHandler(service))
+	http.HandleFunc("/subscriptions/", SubscriptionHandler(service))
 	http.HandleFunc("/transactions/", GetTransactionsHandler(service))
+	http.HandleFunc("/token-transfers/", GetTokenTransfersHandler(service))
+	http.HandleFunc("/internal-transfers/", GetInternalTransfersHandler(service))
+	http.HandleFunc("/backfill/", GetBackfillJobHandler(service))
+	http.HandleFunc("/stream", StreamHandler(service))
+	http.HandleFunc("/rpc", RPCHandler(service))
+	http.HandleFunc("/ws", WebSocketHandler(service, allowedOrigins))
+	http.HandleFunc("/webhooks", RegisterWebhookHandler(service))
+	http.HandleFunc("/webhooks/", WebhookHandler(service))
+	http.HandleFunc("/admin/keys", withAdmin(auth, AdminKeysHandler(auth.config.Keyring)))
+	http.HandleFunc("/admin/keys/", withAdmin(auth, AdminKeyHandler(auth.config.Keyring)))
 
 	log.Printf("Server started on port %s\n", port)
-	http.ListenAndServe(":"+port, nil)
+	http.ListenAndServe(":"+port, withAuth(auth, http.DefaultServeMux))
 }
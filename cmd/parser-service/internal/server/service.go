@@ -2,25 +2,80 @@ package server
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/veljkomatic/be-homework/cmd/parser-service/internal/backfill"
 	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/notification"
 	"github.com/veljkomatic/be-homework/pkg/parser"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
 )
 
 type Service interface {
 	GetCurrentBlockNumber(ctx context.Context) int
-	Subscribe(ctx context.Context, address string) bool
-	GetTransactions(ctx context.Context, address string) []*blockchain.Transaction
+	// Subscribe subscribes address, nil criteria keep criteria of already subscribed address,
+	// subscriber.ErrQuotaExceeded is returned when caller already subscribed addresses allowed by its quota
+	Subscribe(ctx context.Context, address string, criteria *subscriber.Criteria) error
+	// Unsubscribe unsubscribes address, stored transactions, token transfers and internal transfers of the address
+	// are deleted if purge is set
+	Unsubscribe(ctx context.Context, address string, purge bool) bool
+	GetSubscription(ctx context.Context, address string) (*parser.Subscription, error)
+	// GetSubscriptions returns page of subscriptions ordered by address
+	GetSubscriptions(ctx context.Context, cursor string, limit int) (*parser.SubscriptionsPage, error)
+	GetTransactions(ctx context.Context, address string, query transaction.Query) *parser.TransactionsPage
+	// GetTokenTransfers returns page of token transfers sent or received by subscribed address
+	GetTokenTransfers(ctx context.Context, address string, query transfer.Query) *parser.TokenTransfersPage
+	// GetInternalTransfers returns page of internal transfers sent or received by subscribed address
+	GetInternalTransfers(ctx context.Context, address string, query trace.Query) *parser.InternalTransfersPage
+	// IsSubscribed checks if caller is subscribed to address
+	IsSubscribed(ctx context.Context, address string) bool
+	// Match checks if transaction of the address is matched by subscription of the caller
+	Match(ctx context.Context, address string, tx *blockchain.Transaction) bool
+	// WatchTransactions returns listener of transactions of the addresses stored from now on
+	WatchTransactions(ctx context.Context, addresses []string) *notification.Listener
+	// WatchHeads returns listener of new heads
+	WatchHeads(ctx context.Context) *notification.HeadListener
+	// ToTransactions returns stored transactions with their current confirmation status
+	ToTransactions(ctx context.Context, transactions []*transaction.AddressTransaction) []*parser.Transaction
+	// Backfill starts backfill of historical transactions of subscribed address
+	Backfill(ctx context.Context, address string, fromBlock *blockchain.BlockNumber) (*backfill.Job, error)
+	// GetBackfillJob returns progress of backfill job, jobs of other tenants are not found by the caller
+	GetBackfillJob(ctx context.Context, id string) (*backfill.Job, error)
+	// RegisterWebhook registers webhook of the caller notified about matched transactions of the address,
+	// webhooks of other tenants are not found by the caller
+	RegisterWebhook(ctx context.Context, address string, url string, secret string) (*notification.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*notification.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	// GetWebhookDeliveries returns delivery log of the webhook, dead letters are deliveries with dead status
+	GetWebhookDeliveries(ctx context.Context, id string, status notification.DeliveryStatus, limit int) ([]*notification.Delivery, error)
+	// RedeliverDeadLetters queues dead letters of the webhook for delivery again
+	RedeliverDeadLetters(ctx context.Context, id string) (int, error)
 }
 
 var _ Service = (*service)(nil)
 
 type service struct {
-	parser parser.Parser
+	parser      parser.Parser
+	backfiller  backfill.Backfiller
+	dispatcher  notification.Dispatcher
+	broadcaster notification.Broadcaster
 }
 
-func NewService(parser parser.Parser) Service {
+func NewService(
+	parser parser.Parser,
+	backfiller backfill.Backfiller,
+	dispatcher notification.Dispatcher,
+	broadcaster notification.Broadcaster,
+) Service {
 	return &service{
-		parser: parser,
+		parser:      parser,
+		backfiller:  backfiller,
+		dispatcher:  dispatcher,
+		broadcaster: broadcaster,
 	}
 }
 
@@ -28,10 +83,104 @@ func (s *service) GetCurrentBlockNumber(ctx context.Context) int {
 	return s.parser.GetCurrentBlock(ctx)
 }
 
-func (s *service) Subscribe(ctx context.Context, address string) bool {
-	return s.parser.Subscribe(ctx, address)
+func (s *service) Subscribe(ctx context.Context, address string, criteria *subscriber.Criteria) error {
+	return s.parser.Subscribe(ctx, address, criteria)
+}
+
+func (s *service) Unsubscribe(ctx context.Context, address string, purge bool) bool {
+	return s.parser.Unsubscribe(ctx, address, purge)
+}
+
+func (s *service) GetSubscription(ctx context.Context, address string) (*parser.Subscription, error) {
+	return s.parser.GetSubscription(ctx, address)
+}
+
+func (s *service) GetSubscriptions(ctx context.Context, cursor string, limit int) (*parser.SubscriptionsPage, error) {
+	return s.parser.GetSubscriptions(ctx, cursor, limit)
+}
+
+func (s *service) GetTransactions(ctx context.Context, address string, query transaction.Query) *parser.TransactionsPage {
+	return s.parser.GetTransactions(ctx, address, query)
+}
+
+func (s *service) GetTokenTransfers(ctx context.Context, address string, query transfer.Query) *parser.TokenTransfersPage {
+	return s.parser.GetTokenTransfers(ctx, address, query)
 }
 
-func (s *service) GetTransactions(ctx context.Context, address string) []*blockchain.Transaction {
-	return s.parser.GetTransactions(ctx, address)
+func (s *service) GetInternalTransfers(ctx context.Context, address string, query trace.Query) *parser.InternalTransfersPage {
+	return s.parser.GetInternalTransfers(ctx, address, query)
+}
+
+func (s *service) IsSubscribed(ctx context.Context, address string) bool {
+	return s.parser.IsSubscribed(ctx, address)
+}
+
+func (s *service) Match(ctx context.Context, address string, tx *blockchain.Transaction) bool {
+	return s.parser.Match(ctx, address, tx)
+}
+
+func (s *service) WatchTransactions(ctx context.Context, addresses []string) *notification.Listener {
+	return s.broadcaster.Listen(addresses)
+}
+
+func (s *service) WatchHeads(ctx context.Context) *notification.HeadListener {
+	return s.broadcaster.ListenHeads()
+}
+
+func (s *service) ToTransactions(ctx context.Context, transactions []*transaction.AddressTransaction) []*parser.Transaction {
+	return s.parser.ToTransactions(ctx, transactions)
+}
+
+func (s *service) Backfill(ctx context.Context, address string, fromBlock *blockchain.BlockNumber) (*backfill.Job, error) {
+	return s.backfiller.Backfill(ctx, address, fromBlock)
+}
+
+func (s *service) GetBackfillJob(ctx context.Context, id string) (*backfill.Job, error) {
+	job, err := s.backfiller.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.TenantID != tenant.IDFromContext(ctx) {
+		return nil, backfill.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (s *service) RegisterWebhook(ctx context.Context, address string, url string, secret string) (*notification.Webhook, error) {
+	if !s.parser.IsSubscribed(ctx, address) {
+		return nil, fmt.Errorf("%w: address %s is not subscribed", notification.ErrInvalidWebhook, address)
+	}
+	return s.dispatcher.RegisterWebhook(ctx, tenant.IDFromContext(ctx), address, url, secret)
+}
+
+func (s *service) GetWebhook(ctx context.Context, id string) (*notification.Webhook, error) {
+	webhook, err := s.dispatcher.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.TenantID != tenant.IDFromContext(ctx) {
+		return nil, notification.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (s *service) DeleteWebhook(ctx context.Context, id string) error {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return err
+	}
+	return s.dispatcher.DeleteWebhook(ctx, id)
+}
+
+func (s *service) GetWebhookDeliveries(ctx context.Context, id string, status notification.DeliveryStatus, limit int) ([]*notification.Delivery, error) {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.dispatcher.GetDeliveries(ctx, id, status, limit)
+}
+
+func (s *service) RedeliverDeadLetters(ctx context.Context, id string) (int, error) {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return 0, err
+	}
+	return s.dispatcher.RedeliverDeadLetters(ctx, id)
 }
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/parser"
+)
+
+const streamKeepAliveInterval = 15 * time.Second
+
+// StreamEvent is data of transaction event of the stream
+type StreamEvent struct {
+	Address     string              `json:"address"`
+	Transaction *parser.Transaction `json:"transaction"`
+}
+
+// StreamHandler streams transactions of multiple addresses as server-sent events,
+// addresses are passed as repeated or comma separated address query parameter
+func StreamHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		addresses := make([]string, 0)
+		for _, value := range r.URL.Query()["address"] {
+			for _, address := range strings.Split(value, ",") {
+				if address = strings.TrimSpace(address); address != "" {
+					addresses = append(addresses, strings.ToLower(address))
+				}
+			}
+		}
+		if len(addresses) == 0 {
+			writeError(w, http.StatusBadRequest, "address is required")
+			return
+		}
+		for _, address := range addresses {
+			if !service.IsSubscribed(r.Context(), address) {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("address %s is not subscribed", address))
+				return
+			}
+		}
+		streamTransactions(w, r, service, addresses)
+	}
+}
+
+// streamTransactions streams transactions of the addresses as server-sent events.
+// Event id is the durable cursor of the transaction of the address, when it is passed back in Last-Event-ID header
+// or cursor query parameter, transactions stored after the cursor are replayed before live transactions.
+// Stream is closed when client does not keep up with transactions, client is expected to reconnect and resume.
+func streamTransactions(w http.ResponseWriter, r *http.Request, service Service, addresses []string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	cursor := r.Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = r.URL.Query().Get("cursor")
+	}
+	var after *streamCursor
+	if cursor != "" {
+		var err error
+		after, err = parseStreamCursor(cursor)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	watch := newTransactionWatch(r.Context(), service, addresses)
+	defer watch.close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	emit := func(cursor streamCursor, event *StreamEvent) error {
+		if err := writeStreamEvent(w, cursor, event); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	if after != nil {
+		if err := watch.replay(r.Context(), *after, emit); err != nil {
+			writeStreamError(w, flusher, err.Error())
+			return
+		}
+	}
+
+	keepAliveTicker := time.NewTicker(streamKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAliveTicker.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case tx, ok := <-watch.transactions():
+			if !ok {
+				writeStreamError(w, flusher, "stream dropped because client is too slow, reconnect with Last-Event-ID")
+				return
+			}
+			cursor, event := watch.event(r.Context(), tx)
+			if event == nil {
+				continue
+			}
+			if err := emit(cursor, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeStreamEvent writes transaction event with its cursor as event id
+func writeStreamEvent(w http.ResponseWriter, cursor streamCursor, event *StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: transaction\ndata: %s\n\n", cursor, data)
+	return err
+}
+
+// writeStreamError writes error event, stream is closed after it
+func writeStreamError(w http.ResponseWriter, flusher http.Flusher, message string) {
+	data, _ := json.Marshal(ErrorResponse{Error: message})
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+	flusher.Flush()
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/parser"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+)
+
+type UnsubscribeResponse struct {
+	Unsubscribed bool `json:"unsubscribed"`
+	Purged       bool `json:"purged"`
+}
+
+// GetSubscriptionsHandler returns page of subscriptions ordered by address,
+// next page is requested with cursor query parameter set to next cursor of the previous page
+func GetSubscriptionsHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		limit, err := parseLimit(r.URL.Query(), parser.DefaultSubscriptionsLimit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if limit > parser.MaxSubscriptionsLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must not be greater than %d", parser.MaxSubscriptionsLimit))
+			return
+		}
+		page, err := service.GetSubscriptions(r.Context(), r.URL.Query().Get("cursor"), limit)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+}
+
+// SubscriptionHandler serves subscription of the address:
+// GET /subscriptions/:address returns subscription with counts of stored transactions,
+// DELETE /subscriptions/:address unsubscribes the address, stored transactions are deleted with purge=true
+func SubscriptionHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		address := strings.ToLower(strings.Trim(strings.TrimPrefix(r.URL.Path, "/subscriptions/"), "/"))
+		if address == "" || strings.Contains(address, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		subscription, err := service.GetSubscription(r.Context(), address)
+		if errors.Is(err, subscriber.ErrNotSubscribed) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(subscription)
+		case http.MethodDelete:
+			purge := false
+			if value := r.URL.Query().Get("purge"); value != "" {
+				purge, err = strconv.ParseBool(value)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid purge %s", value))
+					return
+				}
+			}
+			unsubscribed := service.Unsubscribe(r.Context(), address, purge)
+			if !unsubscribed {
+				writeError(w, http.StatusInternalServerError, "error unsubscribing from address")
+				return
+			}
+			json.NewEncoder(w).Encode(UnsubscribeResponse{
+				Unsubscribed: unsubscribed,
+				Purged:       purge,
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/notification"
+	"github.com/veljkomatic/be-homework/pkg/parser"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+// emitFunc sends transaction event with its cursor to the client
+type emitFunc func(cursor streamCursor, event *StreamEvent) error
+
+// streamCursor is the resume point of a stream, transactions are streamed in order of their position and address,
+// so transaction matched for more addresses has a different cursor for every address
+type streamCursor struct {
+	position transaction.Position
+	address  string
+}
+
+// less returns true if cursor is before the other cursor in stream order
+func (c streamCursor) less(other streamCursor) bool {
+	if c.position != other.position {
+		return c.position.Less(other.position)
+	}
+	return c.address < other.address
+}
+
+// String is an opaque representation of the cursor
+func (c streamCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%s", c.position.BlockNumber, c.position.TransactionIndex, c.address)))
+}
+
+// parseStreamCursor parses cursor of a stream, cursor of transactions page is accepted too,
+// stream resumed from it replays transactions of all addresses at its position
+func parseStreamCursor(cursor string) (*streamCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, transaction.ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(decoded), ":", 3)
+	if len(parts) < 2 {
+		return nil, transaction.ErrInvalidCursor
+	}
+	position, err := transaction.ParsePosition(parts[0] + ":" + parts[1])
+	if err != nil {
+		return nil, err
+	}
+	parsed := streamCursor{position: *position}
+	if len(parts) == 3 {
+		parsed.address = strings.ToLower(parts[2])
+	}
+	return &parsed, nil
+}
+
+// transactionWatch replays transactions of addresses stored after a cursor and streams live transactions,
+// it is shared by server-sent events and gRPC streams
+type transactionWatch struct {
+	service  Service
+	listener *notification.Listener
+	// replayed transactions are skipped when they are received from the listener
+	replayed  map[string]struct{}
+	addresses []string
+}
+
+// newTransactionWatch starts listening before replay, so transactions stored during replay are not missed
+func newTransactionWatch(ctx context.Context, service Service, addresses []string) *transactionWatch {
+	return &transactionWatch{
+		service:   service,
+		listener:  service.WatchTransactions(ctx, addresses),
+		replayed:  make(map[string]struct{}),
+		addresses: addresses,
+	}
+}
+
+// transactions returns channel of live transactions, it is closed when client does not keep up
+func (w *transactionWatch) transactions() <-chan *transaction.AddressTransaction {
+	return w.listener.Transactions()
+}
+
+// event returns event of live transaction, it is nil if transaction was already replayed
+// or it is not matched by subscription of the caller
+func (w *transactionWatch) event(ctx context.Context, tx *transaction.AddressTransaction) (streamCursor, *StreamEvent) {
+	cursor := streamCursor{position: tx.Position(), address: tx.ID.String()}
+	if _, ok := w.replayed[cursor.String()]; ok {
+		return cursor, nil
+	}
+	if !w.service.Match(ctx, tx.ID.String(), tx.Transaction) {
+		return cursor, nil
+	}
+	transactions := w.service.ToTransactions(ctx, []*transaction.AddressTransaction{tx})
+	if len(transactions) == 0 {
+		return cursor, nil
+	}
+	return cursor, &StreamEvent{
+		Address:     tx.ID.String(),
+		Transaction: transactions[0],
+	}
+}
+
+// replay emits transactions stored after the cursor, transactions of all addresses are merged
+// in stream order, so the cursor of any emitted transaction is a valid resume point
+func (w *transactionWatch) replay(ctx context.Context, after streamCursor, emit emitFunc) error {
+	cursors := make([]*replayCursor, 0, len(w.addresses))
+	for _, address := range w.addresses {
+		// transactions of other addresses at the position of the cursor can be after it, so the whole block is read
+		fromBlock := after.position.BlockNumber
+		cursors = append(cursors, &replayCursor{
+			address: address,
+			after:   after,
+			query: transaction.Query{
+				Limit:     transaction.MaxLimit,
+				FromBlock: &fromBlock,
+			},
+		})
+	}
+
+	for {
+		var earliest *replayCursor
+		var earliestCursor streamCursor
+		var earliestTransaction *parser.Transaction
+		for _, cursor := range cursors {
+			tx, err := cursor.next(ctx, w.service)
+			if err != nil {
+				return err
+			}
+			if tx == nil {
+				continue
+			}
+			txCursor := streamCursor{position: transactionPosition(tx), address: cursor.address}
+			if earliestTransaction == nil || txCursor.less(earliestCursor) {
+				earliest, earliestCursor, earliestTransaction = cursor, txCursor, tx
+			}
+		}
+		if earliest == nil {
+			return nil
+		}
+		earliest.transactions = earliest.transactions[1:]
+
+		w.replayed[earliestCursor.String()] = struct{}{}
+		if err := emit(earliestCursor, &StreamEvent{Address: earliest.address, Transaction: earliestTransaction}); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *transactionWatch) close() {
+	w.listener.Close()
+}
+
+// replayCursor iterates pages of stored transactions of an address after the stream cursor
+type replayCursor struct {
+	address      string
+	after        streamCursor
+	query        transaction.Query
+	transactions []*parser.Transaction
+	// exhausted is true when the last page is read
+	exhausted bool
+}
+
+// next returns the next transaction of the address, it is nil when all transactions are replayed
+func (c *replayCursor) next(ctx context.Context, service Service) (*parser.Transaction, error) {
+	for {
+		for len(c.transactions) > 0 {
+			position := transactionPosition(c.transactions[0])
+			if c.after.less(streamCursor{position: position, address: c.address}) {
+				return c.transactions[0], nil
+			}
+			c.transactions = c.transactions[1:]
+		}
+		if c.exhausted {
+			return nil, nil
+		}
+		// page can be empty when it is not the last one, e.g. transactions of the page are not matched by subscription of the caller
+		page := service.GetTransactions(ctx, c.address, c.query)
+		if page == nil {
+			return nil, fmt.Errorf("failed to replay transactions of address %s", c.address)
+		}
+		c.transactions = page.Transactions
+		c.exhausted = true
+		if page.NextCursor != "" {
+			after, err := transaction.ParseCursor(page.NextCursor)
+			if err != nil {
+				return nil, err
+			}
+			c.query.After = after
+			c.exhausted = false
+		}
+	}
+}
+
+func transactionPosition(tx *parser.Transaction) transaction.Position {
+	addressTransaction := &transaction.AddressTransaction{Transaction: tx.Transaction}
+	return addressTransaction.Position()
+}
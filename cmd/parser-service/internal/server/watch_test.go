@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+const watchedAddress = "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5"
+
+func TestStreamCursor(t *testing.T) {
+	position := transaction.Position{BlockNumber: 19000000, TransactionIndex: 42}
+	tests := []struct {
+		name     string
+		cursor   string
+		expected streamCursor
+	}{
+		{
+			name:     "stream cursor",
+			cursor:   streamCursor{position: position, address: watchedAddress}.String(),
+			expected: streamCursor{position: position, address: watchedAddress},
+		},
+		{
+			name:     "checksummed address",
+			cursor:   base64.RawURLEncoding.EncodeToString([]byte("19000000:42:0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5")),
+			expected: streamCursor{position: position, address: watchedAddress},
+		},
+		{
+			// transaction cursor is before every address at its position
+			name:     "transaction cursor",
+			cursor:   position.Cursor(),
+			expected: streamCursor{position: position},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := parseStreamCursor(test.cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *parsed != test.expected {
+				t.Fatalf("expected cursor %+v, got %+v", test.expected, *parsed)
+			}
+		})
+	}
+}
+
+func TestParseInvalidStreamCursor(t *testing.T) {
+	for _, decoded := range []string{"", "10", "10:", "-1:0:" + watchedAddress, "10:2x:" + watchedAddress, "010:2"} {
+		cursor := base64.RawURLEncoding.EncodeToString([]byte(decoded))
+		if parsed, err := parseStreamCursor(cursor); !errors.Is(err, transaction.ErrInvalidCursor) {
+			t.Fatalf("expected invalid cursor error of %q, got cursor %+v and error %v", decoded, parsed, err)
+		}
+	}
+	if _, err := parseStreamCursor("not base64!"); !errors.Is(err, transaction.ErrInvalidCursor) {
+		t.Fatalf("expected invalid cursor error, got %v", err)
+	}
+}
+
+func TestStreamCursorOrder(t *testing.T) {
+	cursors := []streamCursor{
+		{position: transaction.Position{BlockNumber: 10, TransactionIndex: 2}},
+		{position: transaction.Position{BlockNumber: 10, TransactionIndex: 2}, address: "0x00000000219ab540356cbb839cbe05303d7705fa"},
+		{position: transaction.Position{BlockNumber: 10, TransactionIndex: 2}, address: watchedAddress},
+		{position: transaction.Position{BlockNumber: 10, TransactionIndex: 3}},
+		{position: transaction.Position{BlockNumber: 11, TransactionIndex: 0}, address: watchedAddress},
+	}
+	for i := 1; i < len(cursors); i++ {
+		if !cursors[i-1].less(cursors[i]) || cursors[i].less(cursors[i-1]) {
+			t.Fatalf("expected cursor %+v before %+v", cursors[i-1], cursors[i])
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/notification"
+)
+
+const defaultDeliveriesLimit = 100
+
+type RegisterWebhookBody struct {
+	Address string `json:"address"`
+	URL     string `json:"url"`
+	// Secret is the key of payload signature, it is generated if it is not set
+	Secret string `json:"secret,omitempty"`
+}
+
+type GetWebhookDeliveriesResponse struct {
+	Deliveries []*notification.Delivery `json:"deliveries"`
+}
+
+type RedeliverDeadLettersResponse struct {
+	Redelivered int `json:"redelivered"`
+}
+
+// RegisterWebhookHandler registers webhook of the address, response contains the secret of the webhook
+func RegisterWebhookHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		var body RegisterWebhookBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		webhook, err := service.RegisterWebhook(r.Context(), body.Address, body.URL, body.Secret)
+		if errors.Is(err, notification.ErrInvalidWebhook) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(webhook)
+		return
+	}
+}
+
+// WebhookHandler serves webhook and its delivery log:
+// GET, DELETE /webhooks/:id, GET /webhooks/:id/deliveries (status, limit),
+// GET /webhooks/:id/dead-letters (limit) and POST /webhooks/:id/dead-letters/redeliver
+func WebhookHandler(service Service) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+		if len(parts) < 3 || parts[1] != "webhooks" {
+			http.NotFound(w, r)
+			return
+		}
+		id := parts[2]
+		route := strings.Join(parts[3:], "/")
+
+		switch {
+		case route == "" && r.Method == http.MethodGet:
+			webhook, err := service.GetWebhook(r.Context(), id)
+			if err != nil {
+				writeWebhookError(w, err)
+				return
+			}
+			// secret is returned only when webhook is registered
+			json.NewEncoder(w).Encode(webhook.Redacted())
+		case route == "" && r.Method == http.MethodDelete:
+			if err := service.DeleteWebhook(r.Context(), id); err != nil {
+				writeWebhookError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case route == "deliveries" && r.Method == http.MethodGet:
+			status := notification.DeliveryStatus(r.URL.Query().Get("status"))
+			switch status {
+			case "", notification.DeliveryPending, notification.DeliveryDelivered, notification.DeliveryDead:
+			default:
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid status %s", status))
+				return
+			}
+			writeDeliveries(w, r, service, id, status)
+		case route == "dead-letters" && r.Method == http.MethodGet:
+			writeDeliveries(w, r, service, id, notification.DeliveryDead)
+		case route == "dead-letters/redeliver" && r.Method == http.MethodPost:
+			redelivered, err := service.RedeliverDeadLetters(r.Context(), id)
+			if err != nil {
+				writeWebhookError(w, err)
+				return
+			}
+			json.NewEncoder(w).Encode(RedeliverDeadLettersResponse{
+				Redelivered: redelivered,
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+}
+
+func writeDeliveries(w http.ResponseWriter, r *http.Request, service Service, id string, status notification.DeliveryStatus) {
+	limit, err := parseLimit(r.URL.Query(), defaultDeliveriesLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	deliveries, err := service.GetWebhookDeliveries(r.Context(), id, status, limit)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(GetWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+	})
+}
+
+// writeWebhookError writes not found response for unknown webhook, otherwise internal server error
+func writeWebhookError(w http.ResponseWriter, err error) {
+	if errors.Is(err, notification.ErrWebhookNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// parseLimit parses positive limit query parameter, default limit is returned if it is not set
+func parseLimit(values url.Values, defaultLimit int) (int, error) {
+	limit := values.Get("limit")
+	if limit == "" {
+		return defaultLimit, nil
+	}
+	parsed, err := strconv.Atoi(limit)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid limit %s", limit)
+	}
+	return parsed, nil
+}
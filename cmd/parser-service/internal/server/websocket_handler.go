@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/veljkomatic/be-homework/pkg/notification"
+	"github.com/veljkomatic/be-homework/pkg/parser"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+const (
+	webSocketPingInterval      = 30 * time.Second
+	webSocketPongTimeout       = 2 * webSocketPingInterval
+	webSocketWriteTimeout      = 10 * time.Second
+	webSocketMaxMessageSize    = 64 * 1024
+	webSocketRequestBufferSize = 16
+)
+
+// WebSocket request types sent by client
+const (
+	SubscribeRequest   = "subscribe"
+	UnsubscribeRequest = "unsubscribe"
+)
+
+// WebSocket event types sent by server
+const (
+	SubscribedEvent   = "subscribed"
+	UnsubscribedEvent = "unsubscribed"
+	TransactionEvent  = "transaction"
+	NewHeadEvent      = "newHead"
+	ErrorEvent        = "error"
+)
+
+// WebSocketRequest subscribes or unsubscribes addresses and new heads
+type WebSocketRequest struct {
+	Type      string   `json:"type"`
+	Addresses []string `json:"addresses,omitempty"`
+	NewHeads  bool     `json:"newHeads,omitempty"`
+}
+
+// WebSocketEvent is a reply to request, matched transaction, new head or error
+type WebSocketEvent struct {
+	Type        string              `json:"type"`
+	Addresses   []string            `json:"addresses,omitempty"`
+	NewHeads    bool                `json:"newHeads,omitempty"`
+	Address     string              `json:"address,omitempty"`
+	Transaction *parser.Transaction `json:"transaction,omitempty"`
+	Head        *notification.Head  `json:"head,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// allowAnyOrigin in allowed origins accepts connections from every origin
+const allowAnyOrigin = "*"
+
+// WebSocketHandler upgrades connection to websocket, client subscribes and unsubscribes
+// multiple addresses and new heads over the connection and receives them in real time.
+// Connections are accepted only from the allowed origins, without them only from the same origin.
+func WebSocketHandler(service Service, allowedOrigins []string) httpHandler {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: checkOrigin(allowedOrigins),
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// upgrader already replied with error
+			return
+		}
+		session := &webSocketSession{
+			service: service,
+			conn:    conn,
+		}
+		session.run(r.Context())
+	}
+}
+
+// webSocketSession is a single websocket connection, all writes and subscription changes
+// are done by the run loop, reader only forwards requests to it
+type webSocketSession struct {
+	service      Service
+	conn         *websocket.Conn
+	transactions *notification.Listener
+	heads        *notification.HeadListener
+}
+
+// run pushes events until connection is closed, connection is dropped when client does not keep up
+func (s *webSocketSession) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer s.conn.Close()
+
+	s.transactions = s.service.WatchTransactions(ctx, nil)
+	defer s.transactions.Close()
+	defer func() {
+		if s.heads != nil {
+			s.heads.Close()
+		}
+	}()
+
+	requests := make(chan *WebSocketRequest, webSocketRequestBufferSize)
+	go s.read(ctx, cancel, requests)
+
+	pingTicker := time.NewTicker(webSocketPingInterval)
+	defer pingTicker.Stop()
+	for {
+		var heads <-chan *notification.Head
+		if s.heads != nil {
+			heads = s.heads.Heads()
+		}
+
+		var event *WebSocketEvent
+		select {
+		case <-ctx.Done():
+			return
+		case <-pingTicker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteTimeout)); err != nil {
+				return
+			}
+			continue
+		case request := <-requests:
+			event = s.handle(ctx, request)
+		case tx, ok := <-s.transactions.Transactions():
+			if !ok {
+				s.closeSlowConsumer()
+				return
+			}
+			event = s.transactionEvent(ctx, tx)
+		case head, ok := <-heads:
+			if !ok {
+				s.closeSlowConsumer()
+				return
+			}
+			event = &WebSocketEvent{
+				Type: NewHeadEvent,
+				Head: head,
+			}
+		}
+		if event == nil {
+			continue
+		}
+		s.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+		if err := s.conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+}
+
+// read reads requests until connection is closed or client stops responding to pings
+func (s *webSocketSession) read(ctx context.Context, cancel context.CancelFunc, requests chan<- *WebSocketRequest) {
+	defer cancel()
+	s.conn.SetReadLimit(webSocketMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(webSocketPongTimeout))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(webSocketPongTimeout))
+	})
+
+	for {
+		var request WebSocketRequest
+		if err := s.conn.ReadJSON(&request); err != nil {
+			return
+		}
+		s.conn.SetReadDeadline(time.Now().Add(webSocketPongTimeout))
+		select {
+		case <-ctx.Done():
+			return
+		case requests <- &request:
+		}
+	}
+}
+
+// handle applies request to subscriptions of the connection and returns reply
+func (s *webSocketSession) handle(ctx context.Context, request *WebSocketRequest) *WebSocketEvent {
+	addresses := make([]string, 0, len(request.Addresses))
+	for _, address := range request.Addresses {
+		if address = strings.ToLower(strings.TrimSpace(address)); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	switch request.Type {
+	case SubscribeRequest:
+		for _, address := range addresses {
+			// subscription of the connection only listens, address has to be subscribed by the tenant,
+			// so the parser observes it, and it stays subscribed after the connection is closed
+			if !s.service.IsSubscribed(ctx, address) {
+				return errorEvent(fmt.Sprintf("address %s is not subscribed", address))
+			}
+		}
+		s.transactions.Add(addresses)
+		if request.NewHeads && s.heads == nil {
+			s.heads = s.service.WatchHeads(ctx)
+		}
+		return &WebSocketEvent{
+			Type:      SubscribedEvent,
+			Addresses: addresses,
+			NewHeads:  request.NewHeads,
+		}
+	case UnsubscribeRequest:
+		// subscription of the tenant is kept, address is only not pushed to this connection anymore
+		s.transactions.Remove(addresses)
+		if request.NewHeads && s.heads != nil {
+			s.heads.Close()
+			s.heads = nil
+		}
+		return &WebSocketEvent{
+			Type:      UnsubscribedEvent,
+			Addresses: addresses,
+			NewHeads:  request.NewHeads,
+		}
+	default:
+		return errorEvent(fmt.Sprintf("unknown request type %s", request.Type))
+	}
+}
+
+func (s *webSocketSession) transactionEvent(ctx context.Context, tx *transaction.AddressTransaction) *WebSocketEvent {
+	// address is shared with other tenants, only transactions matched by subscription of the caller are pushed
+	if !s.service.Match(ctx, tx.ID.String(), tx.Transaction) {
+		return nil
+	}
+	transactions := s.service.ToTransactions(ctx, []*transaction.AddressTransaction{tx})
+	if len(transactions) == 0 {
+		return nil
+	}
+	return &WebSocketEvent{
+		Type:        TransactionEvent,
+		Address:     tx.ID.String(),
+		Transaction: transactions[0],
+	}
+}
+
+// closeSlowConsumer closes connection of client which does not keep up with events
+func (s *webSocketSession) closeSlowConsumer() {
+	message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client does not keep up with events")
+	s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(webSocketWriteTimeout))
+}
+
+// checkOrigin returns origin check of the upgrader, request without origin header is not sent by browser
+// and is always accepted, as well as request from the same origin
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+	}
+	_, allowAny := allowed[allowAnyOrigin]
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowAny {
+			return true
+		}
+		if _, ok := allowed[strings.ToLower(origin)]; ok {
+			return true
+		}
+		originURL, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(originURL.Host, r.Host)
+	}
+}
+
+func errorEvent(message string) *WebSocketEvent {
+	return &WebSocketEvent{
+		Type:  ErrorEvent,
+		Error: message,
+	}
+}
@@ -0,0 +1,49 @@
+package transaction_filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+)
+
+// ContractCreations returns contract creation transactions of the block which are not attributed to any address yet.
+// Address of created contract is known only from the receipt, so their receipts are fetched together
+// with receipts of filtered transactions of the block, receipts of the block are fetched once.
+func ContractCreations(block *blockchain.Block) []*transaction.AddressTransaction {
+	blockTimestamp := block.Timestamp.Int64()
+	creations := make([]*transaction.AddressTransaction, 0)
+	for _, tx := range block.Transactions {
+		if tx.To.IsZero() {
+			creations = append(creations, &transaction.AddressTransaction{
+				Transaction:    tx,
+				BlockTimestamp: blockTimestamp,
+			})
+		}
+	}
+	return creations
+}
+
+// FilterContractCreations returns contract creations of the block which created contract matches the filter,
+// they are stored for the created contract with in direction and with receipt attached.
+// Receipts have to be attached to creations, error is returned if any of them is missing, so the block can be retried.
+// Contract creation is matched for its deployer by FilterBlock.
+func FilterContractCreations(ctx context.Context, filter subscriber.Filter, block *blockchain.Block, creations []*transaction.AddressTransaction) ([]*transaction.AddressTransaction, error) {
+	blockTimestamp := block.Timestamp.Int64()
+	filteredTransactions := make([]*transaction.AddressTransaction, 0)
+	for _, creation := range creations {
+		if creation.Receipt == nil {
+			return nil, fmt.Errorf("missing receipt of contract creation %s", creation.Transaction.Hash)
+		}
+		contractAddress := creation.Receipt.ContractAddress
+		if contractAddress.IsZero() || !filter.Test(ctx, contractAddress, creation.Transaction) {
+			continue
+		}
+		filteredTransaction := transaction.NewAddressTransaction(contractAddress, creation.Transaction, blockTimestamp)
+		filteredTransaction.Receipt = creation.Receipt
+		filteredTransactions = append(filteredTransactions, filteredTransaction)
+	}
+	return filteredTransactions, nil
+}
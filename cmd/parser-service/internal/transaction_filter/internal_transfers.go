@@ -0,0 +1,29 @@
+package transaction_filter
+
+import (
+	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+)
+
+// FilterInternalTransfers walks call frames of traced transactions of the block and returns internal transfers
+// which sender or recipient matches the filter, transfer is matched for each of its addresses separately.
+// ETH sent from inside of contract call, e.g. by multisig or exchange hot wallet, is not a transaction of the recipient,
+// so it is found only by tracing.
+func FilterInternalTransfers(ctx context.Context, filter subscriber.Filter, block *blockchain.Block, traces []*blockchain.TransactionTrace) []*trace.AddressTransfer {
+	blockTimestamp := block.Timestamp.Int64()
+	filteredTransfers := make([]*trace.AddressTransfer, 0)
+	for _, transactionTrace := range traces {
+		if transactionTrace.BlockHash != block.Hash {
+			continue
+		}
+		for _, internalTransfer := range transactionTrace.InternalTransfers() {
+			for _, address := range matchedAddresses(ctx, filter, internalTransfer.From, internalTransfer.To) {
+				filteredTransfers = append(filteredTransfers, trace.NewAddressTransfer(address, internalTransfer, blockTimestamp))
+			}
+		}
+	}
+	return filteredTransfers
+}
@@ -0,0 +1,130 @@
+package transaction_filter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+)
+
+const (
+	multisig  = blockchain.Address("0x00000000000000000000000000000000000000aa")
+	recipient = blockchain.Address("0x00000000000000000000000000000000000000bb")
+)
+
+// flakyTracer fails to trace the block given number of times, then returns a transaction with internal transfer
+type flakyTracer struct {
+	failures int
+	mutex    sync.Mutex
+}
+
+func (t *flakyTracer) TraceBlock(ctx context.Context, block *blockchain.Block) ([]*blockchain.TransactionTrace, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.failures > 0 {
+		t.failures--
+		return nil, errors.New("trace timeout")
+	}
+	value := blockchain.QuantityFromInt64(1)
+	return []*blockchain.TransactionTrace{{
+		TransactionHash: blockchain.Hash("0x01"),
+		BlockHash:       block.Hash,
+		BlockNumber:     block.Number,
+		Call: &blockchain.CallFrame{
+			Type: blockchain.CallCallType,
+			To:   multisig,
+			Calls: []*blockchain.CallFrame{
+				{Type: blockchain.CallCallType, From: multisig, To: recipient, Value: &value},
+			},
+		},
+	}}, nil
+}
+
+// chainTip is a provider which returns block with given hash at any height
+type chainTip struct {
+	provider.Provider
+	hash blockchain.Hash
+}
+
+func (c *chainTip) GetBlockByNumber(ctx context.Context, blockNumber blockchain.BlockNumber) (*blockchain.Block, error) {
+	return &blockchain.Block{Number: blockchain.QuantityFromInt64(blockNumber.ToInt64()), Hash: c.hash}, nil
+}
+
+// recipientFilter matches only the recipient
+type recipientFilter struct{}
+
+func (recipientFilter) Test(ctx context.Context, address blockchain.Address, tx *blockchain.Transaction) bool {
+	return address == recipient
+}
+
+func (recipientFilter) TestAddress(ctx context.Context, address blockchain.Address) bool {
+	return address == recipient
+}
+
+type internalTransfersRecorder struct {
+	trace.WriteRepository
+	transfers []*trace.AddressTransfer
+	mutex     sync.Mutex
+}
+
+func (r *internalTransfersRecorder) InsertInternalTransfers(ctx context.Context, transfers []*trace.AddressTransfer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.transfers = append(r.transfers, transfers...)
+	return nil
+}
+
+func (r *internalTransfersRecorder) stored() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.transfers)
+}
+
+func TestFailedBlockIsRetriedUntilInternalTransfersAreStored(t *testing.T) {
+	tests := []struct {
+		name          string
+		canonicalHash blockchain.Hash
+		stored        int
+	}{
+		{name: "canonical block", canonicalHash: "0xaa", stored: 1},
+		{name: "orphaned block", canonicalHash: "0xbb", stored: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var inFlightBlocks sync.WaitGroup
+			recorder := &internalTransfersRecorder{}
+			filter := NewTransactionFilter(&chainTip{hash: test.canonicalHash}, &flakyTracer{failures: 3}, recipientFilter{}, nil, nil, nil, recorder, nil, &inFlightBlocks).(*transactionFilter)
+			go filter.HandleFailedBlocks(ctx)
+
+			block := &blockchain.Block{Number: blockchain.QuantityFromInt64(10), Hash: "0xaa"}
+			inFlightBlocks.Add(1)
+			filter.filterInternalTransfers(ctx, block)
+			inFlightBlocks.Done()
+			if recorder.stored() != 0 {
+				t.Fatal("internal transfers stored although tracing failed")
+			}
+
+			retried := make(chan struct{})
+			go func() {
+				inFlightBlocks.Wait()
+				close(retried)
+			}()
+			select {
+			case <-retried:
+			case <-time.After(5 * time.Second):
+				t.Fatal("failed block was not retried")
+			}
+			if recorder.stored() != test.stored {
+				t.Fatalf("expected %d stored internal transfers, got %d", test.stored, recorder.stored())
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package transaction_filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"github.com/veljkomatic/be-homework/common/jsonrpc"
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+// ReceiptFetcher fetches receipts of filtered transactions. Receipts of the whole block are fetched in a single call,
+// if endpoint does not support it, receipts of filtered transactions are fetched one by one
+// and receipts of blocks are not requested anymore.
+type ReceiptFetcher struct {
+	rpcProvider provider.Provider
+	// blockReceiptsUnsupported is set when endpoint replied that eth_getBlockReceipts does not exist
+	blockReceiptsUnsupported atomic.Bool
+}
+
+func NewReceiptFetcher(rpcProvider provider.Provider) *ReceiptFetcher {
+	return &ReceiptFetcher{
+		rpcProvider: rpcProvider,
+	}
+}
+
+// AttachReceipts fetches receipts of the block and sets them to filtered transactions of the block.
+// Transactions which already have receipt are skipped, so failed call can be retried.
+// Transactions whose receipts could not be fetched are left without receipt and error is returned.
+func (f *ReceiptFetcher) AttachReceipts(ctx context.Context, block *blockchain.Block, transactions []*transaction.AddressTransaction) error {
+	missing := make([]*transaction.AddressTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if tx.Receipt == nil {
+			missing = append(missing, tx)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	receipts := make(map[blockchain.Hash]*blockchain.Receipt)
+	for _, receipt := range f.blockReceipts(ctx, block) {
+		receipts[receipt.TransactionHash] = receipt
+	}
+
+	var failed int
+	for _, tx := range missing {
+		receipt, ok := receipts[tx.Transaction.Hash]
+		if !ok {
+			var err error
+			receipt, err = f.rpcProvider.GetTransactionReceipt(ctx, tx.Transaction.Hash)
+			if err != nil {
+				failed++
+				continue
+			}
+			receipts[receipt.TransactionHash] = receipt
+		}
+		// receipt of reorged block belongs to another block, transaction is stored without it
+		if receipt.BlockHash != block.Hash {
+			failed++
+			continue
+		}
+		tx.Receipt = receipt
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to fetch %d receipts of block %s", failed, block.Number)
+	}
+	return nil
+}
+
+// blockReceipts returns receipts of the block, they are empty if endpoint does not support it or the call failed
+func (f *ReceiptFetcher) blockReceipts(ctx context.Context, block *blockchain.Block) []*blockchain.Receipt {
+	if f.blockReceiptsUnsupported.Load() {
+		return nil
+	}
+	receipts, err := f.rpcProvider.GetBlockReceipts(ctx, block.Hash)
+	if err == nil {
+		return receipts
+	}
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.Code == jsonrpc.MethodNotFoundCode {
+		log.Printf("Endpoint does not support receipts of blocks: %s, fetching receipts of transactions from now on.", err)
+		f.blockReceiptsUnsupported.Store(true)
+		return nil
+	}
+	log.Printf("Error fetching receipts of block %s: %s, fetching receipts of transactions.", block.Number, err)
+	return nil
+}
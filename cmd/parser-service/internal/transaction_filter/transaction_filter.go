@@ -3,42 +3,87 @@ package transaction_filter
 import (
 	"context"
 	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/notification"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
 	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
 	"github.com/veljkomatic/be-homework/pkg/subscriber"
 	"log"
+	"sort"
+	"sync"
+	"time"
 )
 
-const maxConcurrentFilters = 20
+const (
+	maxConcurrentFilters = 20
+	// retryDelay is the delay between retries of rpc calls
+	retryDelay = 500 * time.Millisecond
+	// maxFailedBlockRetryDelay bounds exponential backoff of retries of failed blocks
+	maxFailedBlockRetryDelay = time.Minute
+)
 
 // TransactionFilter is a service that listens for processed new blocks and filters transactions.
 type TransactionFilter interface {
 	// Listen starts listening for new blocks and filters transactions.
 	Listen(ctx context.Context)
+	// HandleFailedBlocks retries internal transfers of blocks which failed to be traced.
+	HandleFailedBlocks(ctx context.Context)
 	// Close closes the transaction filter.
 	Close(ctx context.Context)
 }
 
 type transactionFilter struct {
+	rpcProvider    provider.Provider
+	receiptFetcher *ReceiptFetcher
+	// tracer is nil when tracing is disabled, internal transfers are not filtered then
+	tracer                provider.Tracer
 	filter                subscriber.Filter
 	processedBlockChannel <-chan *blockchain.Block
 	transactionRepository transaction.WriteRepository
+	transferRepository    transfer.WriteRepository
+	traceRepository       trace.WriteRepository
+	notifier              notification.Notifier
+	// inFlightBlocks is done when data of the received block is stored, block processor waits for it before rollback
+	inFlightBlocks *sync.WaitGroup
+	// failedBlockChan receives blocks which failed to be traced, they are retried by HandleFailedBlocks
+	failedBlockChan chan *blockchain.Block
 }
 
 func NewTransactionFilter(
+	rpcProvider provider.Provider,
+	tracer provider.Tracer,
 	filter subscriber.Filter,
 	processedBlockChannel <-chan *blockchain.Block,
 	transactionRepository transaction.WriteRepository,
+	transferRepository transfer.WriteRepository,
+	traceRepository trace.WriteRepository,
+	notifier notification.Notifier,
+	inFlightBlocks *sync.WaitGroup,
 ) TransactionFilter {
 	return &transactionFilter{
+		rpcProvider:           rpcProvider,
+		receiptFetcher:        NewReceiptFetcher(rpcProvider),
+		tracer:                tracer,
 		filter:                filter,
 		processedBlockChannel: processedBlockChannel,
 		transactionRepository: transactionRepository,
+		transferRepository:    transferRepository,
+		traceRepository:       traceRepository,
+		notifier:              notifier,
+		inFlightBlocks:        inFlightBlocks,
+		failedBlockChan:       make(chan *blockchain.Block, maxConcurrentFilters),
 	}
 }
 
 func (t *transactionFilter) Listen(ctx context.Context) {
 	// semaphore to limit the number of concurrent filters
 	filterSemaphore := make(chan struct{}, maxConcurrentFilters)
+	// block processor sends blocks in order of their numbers, blocks are filtered concurrently,
+	// but notifications of a block wait for notifications of the previous block,
+	// so listeners receive transactions in order of blocks and cursor of any notified transaction is a valid resume point
+	previousNotified := make(chan struct{})
+	close(previousNotified)
 
 	for {
 		select {
@@ -54,37 +99,215 @@ func (t *transactionFilter) Listen(ctx context.Context) {
 			filterSemaphore <- struct{}{} // acquire a semaphore slot
 			// filter transactions in a separate goroutine
 			// so we can continue listening for new blocks
-			go func(block *blockchain.Block) {
+			notified := make(chan struct{})
+			go func(block *blockchain.Block, previousNotified <-chan struct{}, notified chan<- struct{}) {
 				defer func() { <-filterSemaphore }() // release the semaphore slot
-				t.filterTransactions(ctx, block)
-			}(block)
+				defer t.inFlightBlocks.Done()
+				t.filterTransactions(ctx, block, previousNotified, notified)
+				t.filterTransfers(ctx, block)
+				t.filterInternalTransfers(ctx, block)
+			}(block, previousNotified, notified)
+			previousNotified = notified
 		}
 	}
 }
 
 // filterTransactions filters transactions from a block if they match the filter and stores them in the database.
-// here we are using a simple filter that checks if the transaction's from or to address matches the filter.
-// in a real world scenario we would probably want to use a bloom filter to check if the transaction's from or to address matches the filter.
-// here we could send filtered transactions to a queue so notification service can send notifications to subscribers.
-func (t *transactionFilter) filterTransactions(ctx context.Context, block *blockchain.Block) {
+// receipts of filtered transactions are fetched before they are stored, so subscribers know if transaction failed,
+// they are fetched together with receipts of contract creations, which are needed to match created contracts.
+// stored transactions are passed to the notifier, so subscribers are notified only about transactions they can query.
+// Notifier is called after notifications of the previous block are done, notified is closed in any case.
+func (t *transactionFilter) filterTransactions(ctx context.Context, block *blockchain.Block, previousNotified <-chan struct{}, notified chan<- struct{}) {
+	defer close(notified)
+	filteredTransactions := FilterBlock(ctx, t.filter, block)
+	creations := ContractCreations(block)
+	t.attachReceipts(ctx, block, filteredTransactions, creations)
+	filteredCreations, err := FilterContractCreations(ctx, t.filter, block, creations)
+	if err != nil {
+		log.Printf("Skipping contract creations of block %s: %s.", block.Number, err)
+	}
+	filteredTransactions = append(filteredTransactions, filteredCreations...)
+	if err := t.storeObservedTransactions(ctx, filteredTransactions); err != nil {
+		log.Println(ctx, err, "Error storing observed transactions")
+		return
+	}
+	// transactions are notified in order of their position and address, as they are streamed
+	sort.SliceStable(filteredTransactions, func(i, j int) bool {
+		positionI, positionJ := filteredTransactions[i].Position(), filteredTransactions[j].Position()
+		if positionI != positionJ {
+			return positionI.Less(positionJ)
+		}
+		return filteredTransactions[i].ID < filteredTransactions[j].ID
+	})
+	select {
+	case <-ctx.Done():
+		return
+	case <-previousNotified:
+	}
+	if len(filteredTransactions) > 0 {
+		t.notifier.Notify(ctx, filteredTransactions)
+	}
+}
+
+// FilterBlock returns transactions of the block which from or to address matches the filter,
+// transaction is matched for each of its addresses separately.
+// addresses which are not subscribed are rejected by bloom filter of the subscriber, so most transactions are filtered without locking.
+// Contract creation has no recipient, it is matched for its deployer only, created contract is matched by FilterContractCreations.
+// Transaction sent to itself is stored once with self direction.
+func FilterBlock(ctx context.Context, filter subscriber.Filter, block *blockchain.Block) []*transaction.AddressTransaction {
+	blockTimestamp := block.Timestamp.Int64()
 	filteredTransactions := make([]*transaction.AddressTransaction, 0, len(block.Transactions))
 	for _, tx := range block.Transactions {
-		if t.filter.Test(ctx, tx.From) {
-			filteredTransactions = append(filteredTransactions, &transaction.AddressTransaction{
-				ID:          transaction.NewAddressTransactionID(tx.From),
-				Transaction: tx,
-			})
+		if filter.Test(ctx, tx.From, tx) {
+			filteredTransactions = append(filteredTransactions, transaction.NewAddressTransaction(tx.From, tx, blockTimestamp))
 		}
-		if t.filter.Test(ctx, tx.To) {
-			filteredTransactions = append(filteredTransactions, &transaction.AddressTransaction{
-				ID:          transaction.NewAddressTransactionID(tx.To),
-				Transaction: tx,
-			})
+		if tx.To.IsZero() || tx.To == tx.From {
+			continue
+		}
+		if filter.Test(ctx, tx.To, tx) {
+			filteredTransactions = append(filteredTransactions, transaction.NewAddressTransaction(tx.To, tx, blockTimestamp))
 		}
 	}
-	if err := t.storeObservedTransactions(ctx, filteredTransactions); err != nil {
-		log.Println(ctx, err, "Error storing observed transactions")
+	return filteredTransactions
+}
+
+// filterTransfers filters token transfers decoded from event logs of the block if their sender or recipient
+// matches the filter and stores them in the database.
+func (t *transactionFilter) filterTransfers(ctx context.Context, block *blockchain.Block) {
+	const maxRetries = 3
+
+	var logs []*blockchain.Log
+	var err error
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if logs, err = FetchTransferLogs(ctx, t.rpcProvider, block); err == nil {
+			break
+		}
+		log.Printf("Error fetching token transfer logs: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+	}
+	if err != nil {
+		log.Printf("Skipping token transfers of block %s: %s.", block.Number, err)
+		return
+	}
+	filteredTransfers := FilterTransfers(ctx, t.filter, block, logs)
+	if len(filteredTransfers) == 0 {
+		return
+	}
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if err = t.transferRepository.InsertTransfers(ctx, filteredTransfers); err == nil {
+			return
+		}
+		log.Printf("Error inserting token transfers: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+	}
+	log.Printf("Error inserting token transfers of block %s: %s. Max retries exceeded.", block.Number, err)
+}
+
+// filterInternalTransfers traces transactions of the block and stores internal transfers
+// which sender or recipient matches the filter, it does nothing when tracing is disabled.
+// Block which could not be traced is sent to failed blocks, it stays in flight until its internal transfers are stored,
+// so it is not rolled back before they are stored.
+func (t *transactionFilter) filterInternalTransfers(ctx context.Context, block *blockchain.Block) {
+	if t.tracer == nil {
+		return
+	}
+	if err := t.storeInternalTransfers(ctx, block); err != nil {
+		log.Printf("Error storing internal transfers of block %s: %s. Retrying in %v...", block.Number, err, retryDelay)
+		t.inFlightBlocks.Add(1)
+		t.failedBlockChan <- block
+	}
+}
+
+// storeInternalTransfers traces transactions of the block with retries and stores internal transfers matching the filter
+func (t *transactionFilter) storeInternalTransfers(ctx context.Context, block *blockchain.Block) error {
+	const maxRetries = 3
+
+	var traces []*blockchain.TransactionTrace
+	var err error
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if traces, err = t.tracer.TraceBlock(ctx, block); err == nil {
+			break
+		}
+		log.Printf("Error tracing block: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+		time.Sleep(retryDelay)
+	}
+	if err != nil {
+		return err
+	}
+	filteredTransfers := FilterInternalTransfers(ctx, t.filter, block, traces)
+	if len(filteredTransfers) == 0 {
+		return nil
+	}
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if err = t.traceRepository.InsertInternalTransfers(ctx, filteredTransfers); err == nil {
+			return nil
+		}
+		log.Printf("Error inserting internal transfers: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+		time.Sleep(retryDelay)
 	}
+	return err
+}
+
+func (t *transactionFilter) HandleFailedBlocks(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case block := <-t.failedBlockChan:
+			// defensive programming
+			// we should never receive a nil block
+			if block == nil {
+				continue
+			}
+			go t.retryInternalTransfers(ctx, block)
+		}
+	}
+}
+
+// retryInternalTransfers retries internal transfers of the failed block with exponential backoff until they are stored.
+// Block which is no longer canonical is dropped, it is rolled back and its replacement is processed.
+func (t *transactionFilter) retryInternalTransfers(ctx context.Context, block *blockchain.Block) {
+	defer t.inFlightBlocks.Done()
+
+	blockNumber := blockchain.NewBlockNumberBuilder().FromQuantity(block.Number).Value()
+	delay := retryDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxFailedBlockRetryDelay {
+			delay = maxFailedBlockRetryDelay
+		}
+
+		canonicalBlock, err := t.rpcProvider.GetBlockByNumber(ctx, blockNumber)
+		if err == nil && canonicalBlock.Hash != block.Hash {
+			log.Printf("Dropping internal transfers of orphaned block %s.", block.Number)
+			return
+		}
+		if err = t.storeInternalTransfers(ctx, block); err == nil {
+			return
+		}
+		log.Printf("Error storing internal transfers of block %s: %s. Retrying in %v...", block.Number, err, delay)
+	}
+}
+
+// attachReceipts fetches receipts of filtered transactions and contract creations of the block with retries,
+// receipts of the block are fetched once for both. Filtered transactions whose receipts could not be fetched are stored without them.
+func (t *transactionFilter) attachReceipts(ctx context.Context, block *blockchain.Block, filteredTransactions, creations []*transaction.AddressTransaction) {
+	const maxRetries = 3
+
+	transactions := make([]*transaction.AddressTransaction, 0, len(filteredTransactions)+len(creations))
+	transactions = append(append(transactions, filteredTransactions...), creations...)
+	var err error
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if err = t.receiptFetcher.AttachReceipts(ctx, block, transactions); err == nil {
+			return
+		}
+		log.Printf("Error fetching receipts: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+		time.Sleep(retryDelay)
+	}
+	log.Printf("Storing transactions of block %s without receipts: %s.", block.Number, err)
 }
 
 // storeObservedTransactions stores filtered transactions in the database (in memory).
@@ -94,25 +317,18 @@ func (t *transactionFilter) storeObservedTransactions(ctx context.Context, filte
 		return nil
 	}
 
-	var currentRetry int
 	const maxRetries = 3
 
 	var err error
-
-	for currentRetry < maxRetries {
-		if err := t.transactionRepository.InsertTransactions(ctx, filteredTransactions); err != nil {
-			log.Println(ctx, err, "Error inserting transactions: %s. Retry %d/%d.\n", err, currentRetry+1, maxRetries)
-			currentRetry++
-			continue
+	for currentRetry := 0; currentRetry < maxRetries; currentRetry++ {
+		if err = t.transactionRepository.InsertTransactions(ctx, filteredTransactions); err == nil {
+			return nil
 		}
-		break
-	}
-
-	if currentRetry == maxRetries {
-		log.Println(ctx, err, "Error inserting transactions: %s. Max retries exceeded.\n", err)
-		return err
+		log.Printf("Error inserting transactions: %s. Retry %d/%d.", err, currentRetry+1, maxRetries)
+		time.Sleep(retryDelay)
 	}
-	return nil
+	log.Printf("Error inserting transactions: %s. Max retries exceeded.", err)
+	return err
 }
 
 func (t *transactionFilter) Close(ctx context.Context) {}
@@ -0,0 +1,74 @@
+package transaction_filter
+
+import (
+	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/provider"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
+	"github.com/veljkomatic/be-homework/pkg/subscriber"
+)
+
+// TransferLogFilter returns filter of token transfer event logs,
+// logs of blocks in the range are fetched by block number and logs of a single block by its hash
+func TransferLogFilter() provider.LogFilter {
+	return provider.LogFilter{
+		Topics: [][]blockchain.Hash{blockchain.TokenTransferTopics},
+	}
+}
+
+// AddressTransferLogFilters returns filters of token transfer event logs sent or received by the address,
+// the address is matched in indexed topics of sender and recipient, so only logs of the address are fetched.
+// Logs of transfer to itself are returned by both filters of the event.
+func AddressTransferLogFilters(address blockchain.Address) []provider.LogFilter {
+	addressTopic := []blockchain.Hash{blockchain.AddressTopic(address)}
+	transferTopic := []blockchain.Hash{blockchain.TransferEventTopic}
+	// operator is the first indexed address of ERC-1155 events, sender and recipient are the next ones
+	erc1155Topics := []blockchain.Hash{blockchain.TransferSingleEventTopic, blockchain.TransferBatchEventTopic}
+	return []provider.LogFilter{
+		{Topics: [][]blockchain.Hash{transferTopic, addressTopic}},
+		{Topics: [][]blockchain.Hash{transferTopic, nil, addressTopic}},
+		{Topics: [][]blockchain.Hash{erc1155Topics, nil, addressTopic}},
+		{Topics: [][]blockchain.Hash{erc1155Topics, nil, nil, addressTopic}},
+	}
+}
+
+// FetchTransferLogs fetches token transfer event logs of the block,
+// logs are requested by block hash, so logs of a block which was reorged out are not returned for its replacement
+func FetchTransferLogs(ctx context.Context, rpcProvider provider.Provider, block *blockchain.Block) ([]*blockchain.Log, error) {
+	logFilter := TransferLogFilter()
+	logFilter.BlockHash = block.Hash
+	return rpcProvider.GetLogs(ctx, logFilter)
+}
+
+// FilterTransfers returns token transfers decoded from logs of the block which sender or recipient matches the filter,
+// transfer is matched for each of its addresses separately.
+// Token transfer to subscribed address is found even though its transaction is sent to the token contract.
+func FilterTransfers(ctx context.Context, filter subscriber.Filter, block *blockchain.Block, logs []*blockchain.Log) []*transfer.AddressTransfer {
+	blockTimestamp := block.Timestamp.Int64()
+	filteredTransfers := make([]*transfer.AddressTransfer, 0)
+	for _, log := range logs {
+		if log.BlockHash != block.Hash {
+			continue
+		}
+		for _, tokenTransfer := range blockchain.DecodeTokenTransfers(log) {
+			for _, address := range matchedAddresses(ctx, filter, tokenTransfer.From, tokenTransfer.To) {
+				filteredTransfers = append(filteredTransfers, transfer.NewAddressTransfer(address, tokenTransfer, blockTimestamp))
+			}
+		}
+	}
+	return filteredTransfers
+}
+
+// matchedAddresses returns sender and recipient of a transfer which match the filter,
+// transfer to itself is stored once with self direction, so its address is returned once
+func matchedAddresses(ctx context.Context, filter subscriber.Filter, from, to blockchain.Address) []blockchain.Address {
+	addresses := make([]blockchain.Address, 0, 2)
+	if filter.TestAddress(ctx, from) {
+		addresses = append(addresses, from)
+	}
+	if to != from && filter.TestAddress(ctx, to) {
+		addresses = append(addresses, to)
+	}
+	return addresses
+}
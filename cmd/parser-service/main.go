@@ -2,32 +2,54 @@ package main
 
 import (
 	"context"
+	"github.com/veljkomatic/be-homework/cmd/parser-service/internal/backfill"
 	"github.com/veljkomatic/be-homework/cmd/parser-service/internal/server"
+	"github.com/veljkomatic/be-homework/pkg/confirmation"
+	"github.com/veljkomatic/be-homework/pkg/notification"
 	"github.com/veljkomatic/be-homework/pkg/parser"
 	"github.com/veljkomatic/be-homework/pkg/storage/block"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
 	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
 	"log"
+	"os"
+	"sync"
 	"time"
 
+	fanout "github.com/veljkomatic/be-homework/cmd/parser-service/internal/block_fanout"
 	processor "github.com/veljkomatic/be-homework/cmd/parser-service/internal/block_processor"
 	filter "github.com/veljkomatic/be-homework/cmd/parser-service/internal/transaction_filter"
 	"github.com/veljkomatic/be-homework/pkg/blockchain"
 	"github.com/veljkomatic/be-homework/pkg/provider"
 	subscriberpkg "github.com/veljkomatic/be-homework/pkg/subscriber"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
 )
 
 const (
 	bufferSize        = 100
 	heartbeatInterval = 5 * time.Minute
 	serverPort        = "8080"
+	grpcPort          = "9090"
+	// confirmationDepth is the number of blocks after which transaction is considered confirmed
+	confirmationDepth = confirmation.DefaultDepth
+	// finalityTag is the block tag used to mark transactions as finalized
+	finalityTag = blockchain.FinalizedBlockTag
 )
 
 func main() {
 	ctx, cancelContext := context.WithCancel(context.Background())
 	defer cancelContext()
 
-	app := &App{}
-	app.init()
+	// migrate subcommand only applies pending migrations of sql storage backend
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(ctx, parseConfig(os.Args[2:])); err != nil {
+			log.Fatalln("Error migrating database:", err)
+		}
+		return
+	}
+
+	app := &App{config: parseConfig(os.Args[1:])}
+	app.init(ctx)
 	defer app.close(ctx)
 
 	app.startProcessing(ctx)
@@ -48,68 +70,177 @@ func main() {
 
 // App is the main application
 type App struct {
+	config   Config
+	storages *storages
+
 	blockRepository       block.Repository
 	transactionRepository transaction.Repository
+	transferRepository    transfer.Repository
+	traceRepository       trace.Repository
 	subscriber            subscriberpkg.Subscriber
+	keyring               tenant.Keyring
 
+	rpcProvider           provider.Provider
 	processedBlockChannel chan *blockchain.Block
+	filterBlockChannel    chan *blockchain.Block
+	headBlockChannel      chan *blockchain.Block
 	blockProcessor        processor.BlockProcessor
+	blockFanout           fanout.BlockFanout
 	transactionFilter     filter.TransactionFilter
+	backfiller            backfill.Backfiller
+	dispatcher            notification.Dispatcher
+	broadcaster           notification.Broadcaster
+
+	// tracer traces blocks for internal transfers, it is nil when tracing is disabled
+	tracer provider.Tracer
+	// inFlightBlocks counts blocks sent by the block processor which are not stored by the transaction filter yet
+	inFlightBlocks sync.WaitGroup
 }
 
 // init initializes the application
-func (a *App) init() {
-	a.initRepositories()
+func (a *App) init(ctx context.Context) {
+	a.initRepositories(ctx)
 	a.initChannels()
-	a.initSubscriber()
+	a.initSubscriber(ctx)
+	a.initKeyring(ctx)
+	a.initProvider()
+	a.initDispatcher(ctx)
 	a.initBlockProcessor()
+	a.initBlockFanout()
 	a.initTransactionFilter()
+	a.initBackfiller()
 }
 
 // close closes the application
 func (a *App) close(ctx context.Context) {
 	a.blockProcessor.Close(ctx)
+	a.blockFanout.Close(ctx)
 	a.transactionFilter.Close(ctx)
+	a.backfiller.Close(ctx)
+	a.dispatcher.Close(ctx)
+	if err := a.storages.close(); err != nil {
+		log.Println("Error closing storages:", err)
+	}
 }
 
 // startProcessing starts the processing of new blocks and transactions
 func (a *App) startProcessing(ctx context.Context) {
 	go a.blockProcessor.Start(ctx)
 	go a.blockProcessor.HandleFailedBlocks(ctx)
+	go a.blockFanout.Start(ctx)
+	go a.broadcaster.WatchHeads(ctx, a.headBlockChannel)
 	go a.transactionFilter.Listen(ctx)
+	go a.transactionFilter.HandleFailedBlocks(ctx)
+	go a.backfiller.Start(ctx)
+	go a.dispatcher.Start(ctx)
 }
 
-// startServer starts the rest server
+// startServer starts the rest and gRPC servers
 func (a *App) startServer() {
-	parser := parser.NewParser(a.subscriber, a.transactionRepository, a.blockRepository)
-	service := server.NewService(parser)
-	go server.StartServer(service, serverPort)
+	parser := parser.NewParser(a.subscriber, a.transactionRepository, a.transferRepository, a.traceRepository, a.blockRepository, a.config.Confirmation)
+	service := server.NewService(parser, a.backfiller, a.dispatcher, a.broadcaster)
+	auth := server.NewAuthenticator(server.AuthConfig{
+		Keyring:          a.keyring,
+		AdminKey:         a.config.AdminKey,
+		KeyRate:          a.config.KeyRate,
+		IPRate:           a.config.IPRate,
+		ClientIPHeader:   a.config.ClientIPHeader,
+		TrustedProxies:   a.config.TrustedProxies,
+		MaxSubscriptions: a.config.MaxSubscriptions,
+	})
+	go server.StartServer(service, serverPort, auth, a.config.AllowedOrigins)
+	go server.StartGRPCServer(service, grpcPort, auth)
 }
 
 // initRepositories initializes the repositories
-func (a *App) initRepositories() {
-	a.blockRepository = block.NewRepository(block.NewStorage())
-	a.transactionRepository = transaction.NewRepository(transaction.NewStorage())
+func (a *App) initRepositories(ctx context.Context) {
+	storages, err := openStorages(ctx, a.config)
+	if err != nil {
+		log.Fatalln("Error opening storages:", err)
+	}
+	a.storages = storages
+	a.blockRepository = block.NewRepository(storages.block)
+	a.transactionRepository = transaction.NewRepository(storages.transaction)
+	a.transferRepository = transfer.NewRepository(storages.transfer)
+	a.traceRepository = trace.NewRepository(storages.trace)
 }
 
 // initChannels initializes the channels
 func (a *App) initChannels() {
 	a.processedBlockChannel = make(chan *blockchain.Block, bufferSize)
+	a.filterBlockChannel = make(chan *blockchain.Block, bufferSize)
+	a.headBlockChannel = make(chan *blockchain.Block, bufferSize)
+}
+
+// initSubscriber initializes the subscriber with subscriptions from the storage
+func (a *App) initSubscriber(ctx context.Context) {
+	subscriber, err := subscriberpkg.NewSubscriber(ctx, a.storages.subscription, a.config.FilterFalsePositiveRate)
+	if err != nil {
+		log.Fatalln("Error loading subscriptions:", err)
+	}
+	a.subscriber = subscriber
+}
+
+// initKeyring initializes the keyring with api keys from the config and the storage
+func (a *App) initKeyring(ctx context.Context) {
+	keyring, err := tenant.NewKeyring(ctx, a.storages.key, a.config.APIKeys)
+	if err != nil {
+		log.Fatalln("Error loading api keys:", err)
+	}
+	a.keyring = keyring
 }
 
-func (a *App) initSubscriber() {
-	a.subscriber = subscriberpkg.NewSubscriber()
+// initProvider initializes the rpc provider and the tracer if trace endpoint is configured
+func (a *App) initProvider() {
+	rpcProvider, err := provider.NewMultiProvider(a.config.Provider)
+	if err != nil {
+		log.Fatalln("Error initializing rpc provider:", err)
+	}
+	a.rpcProvider = rpcProvider
+	if a.config.WebSocketEndpoint != "" {
+		a.rpcProvider = provider.NewWebSocketProvider(a.config.WebSocketEndpoint, a.rpcProvider)
+	}
+	if a.config.TraceEndpoint != "" {
+		tracer, err := provider.NewTracer(a.config.TraceEndpoint, a.config.TraceMethod)
+		if err != nil {
+			log.Fatalln("Error initializing tracer:", err)
+		}
+		a.tracer = tracer
+	}
+}
+
+// initDispatcher initializes the dispatcher of webhook notifications with webhooks from the storage,
+// and the broadcaster of transactions to open streams
+func (a *App) initDispatcher(ctx context.Context) {
+	// webhooks are notified only about transactions matched by subscription of their tenant
+	dispatcher, err := notification.NewDispatcher(ctx, a.storages.notification, a.subscriber, a.config.AllowPrivateWebhooks)
+	if err != nil {
+		log.Fatalln("Error loading webhooks:", err)
+	}
+	a.dispatcher = dispatcher
+	a.broadcaster = notification.NewBroadcaster()
 }
 
 // initBlockProcessor initializes the block processor
 func (a *App) initBlockProcessor() {
-	rpcProvider := provider.NewProvider()
 	failedToProcessChan := make(chan *blockchain.BlockNumber, bufferSize)
-	a.blockProcessor = processor.NewBlockProcessor(rpcProvider, a.blockRepository, a.processedBlockChannel, failedToProcessChan)
+	a.blockProcessor = processor.NewBlockProcessor(a.rpcProvider, a.blockRepository, a.transactionRepository, a.transferRepository, a.traceRepository, a.config.Confirmation, a.processedBlockChannel, failedToProcessChan, &a.inFlightBlocks)
+}
+
+// initBlockFanout initializes fanout of processed blocks to transaction filter and new head notifications
+func (a *App) initBlockFanout() {
+	a.blockFanout = fanout.NewBlockFanout(a.processedBlockChannel, a.filterBlockChannel, a.headBlockChannel)
 }
 
 // initTransactionFilter initializes the transaction filter
 func (a *App) initTransactionFilter() {
 	subscriptionFilter := subscriberpkg.NewFilter(a.subscriber)
-	a.transactionFilter = filter.NewTransactionFilter(subscriptionFilter, a.processedBlockChannel, a.transactionRepository)
+	notifier := notification.Notifiers{a.dispatcher, a.broadcaster}
+	a.transactionFilter = filter.NewTransactionFilter(a.rpcProvider, a.tracer, subscriptionFilter, a.filterBlockChannel, a.transactionRepository, a.transferRepository, a.traceRepository, notifier, &a.inFlightBlocks)
+}
+
+// initBackfiller initializes the backfiller of historical transactions of subscribed addresses
+func (a *App) initBackfiller() {
+	subscriptionFilter := subscriberpkg.NewFilter(a.subscriber)
+	a.backfiller = backfill.NewBackfiller(a.rpcProvider, a.tracer, a.blockRepository, a.transactionRepository, a.transferRepository, a.traceRepository, subscriptionFilter, a.config.BackfillDepth)
 }
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/veljkomatic/be-homework/pkg/notification"
+	"github.com/veljkomatic/be-homework/pkg/storage/block"
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
+	subscriberpkg "github.com/veljkomatic/be-homework/pkg/subscriber"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
+)
+
+const (
+	// MemoryStorageBackend keeps everything in memory, everything is lost on restart
+	MemoryStorageBackend = "memory"
+	// BoltStorageBackend persists everything in embedded bolt database file
+	BoltStorageBackend = "bolt"
+	// SQLiteStorageBackend persists everything in sqlite database file
+	SQLiteStorageBackend = "sqlite"
+	// PostgresStorageBackend persists everything in postgres compatible database
+	PostgresStorageBackend = "postgres"
+
+	boltOpenTimeout = 5 * time.Second
+)
+
+// storages are storages of the selected backend
+type storages struct {
+	block        block.Storage
+	transaction  transaction.Storage
+	transfer     transfer.Storage
+	trace        trace.Storage
+	subscription subscriberpkg.Storage
+	notification notification.Storage
+	key          tenant.Storage
+	close        func() error
+}
+
+// openStorages opens storages of the configured backend, sql databases are migrated to the latest schema
+func openStorages(ctx context.Context, config Config) (*storages, error) {
+	switch config.StorageBackend {
+	case MemoryStorageBackend:
+		return &storages{
+			block:        block.NewStorage(),
+			transaction:  transaction.NewStorage(),
+			transfer:     transfer.NewStorage(),
+			trace:        trace.NewStorage(),
+			subscription: subscriberpkg.NewStorage(),
+			notification: notification.NewStorage(),
+			key:          tenant.NewStorage(),
+			close:        func() error { return nil },
+		}, nil
+	case BoltStorageBackend:
+		return openBoltStorages(config.DatabasePath)
+	case SQLiteStorageBackend, PostgresStorageBackend:
+		return openSQLStorages(ctx, config)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %s", config.StorageBackend)
+	}
+}
+
+func openBoltStorages(path string) (*storages, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+	blockStorage, err := block.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	transactionStorage, err := transaction.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	transferStorage, err := transfer.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	traceStorage, err := trace.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	subscriptionStorage, err := subscriberpkg.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	notificationStorage, err := notification.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	keyStorage, err := tenant.NewBoltStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &storages{
+		block:        blockStorage,
+		transaction:  transactionStorage,
+		transfer:     transferStorage,
+		trace:        traceStorage,
+		subscription: subscriptionStorage,
+		notification: notificationStorage,
+		key:          keyStorage,
+		close:        db.Close,
+	}, nil
+}
+
+func openSQLStorages(ctx context.Context, config Config) (*storages, error) {
+	db, err := openDatabase(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &storages{
+		block:        block.NewSQLStorage(db),
+		transaction:  transaction.NewSQLStorage(db),
+		transfer:     transfer.NewSQLStorage(db),
+		trace:        trace.NewSQLStorage(db),
+		subscription: subscriberpkg.NewSQLStorage(db),
+		notification: notification.NewSQLStorage(db),
+		key:          tenant.NewSQLStorage(db),
+		close:        db.Close,
+	}, nil
+}
+
+// openDatabase opens sql database of the configured backend
+func openDatabase(config Config) (*database.DB, error) {
+	switch config.StorageBackend {
+	case SQLiteStorageBackend:
+		return database.Open(database.SQLiteDialect, config.DatabasePath)
+	case PostgresStorageBackend:
+		return database.Open(database.PostgresDialect, config.DatabaseDSN)
+	default:
+		return nil, fmt.Errorf("storage backend %s is not sql database", config.StorageBackend)
+	}
+}
+
+// migrate applies pending migrations of the configured sql database
+func migrate(ctx context.Context, config Config) error {
+	db, err := openDatabase(config)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return database.Migrate(ctx, db)
+}
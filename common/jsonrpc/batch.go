@@ -0,0 +1,26 @@
+package jsonrpc
+
+// BatchRequest is a list of requests sent in a single call.
+type BatchRequest []*Request
+
+// NewBatchRequest creates a new batch request from passed requests.
+func NewBatchRequest(requests ...*Request) BatchRequest {
+	return requests
+}
+
+// BatchResponse is a list of responses to a batch request.
+// Server may return responses in any order, they are correlated with requests by ID.
+// Every response can be either result or error, independently of other responses.
+type BatchResponse []*Response
+
+// ByID returns responses indexed by ID of the request.
+func (b BatchResponse) ByID() map[ID]*Response {
+	responses := make(map[ID]*Response, len(b))
+	for _, response := range b {
+		if response == nil {
+			continue
+		}
+		responses[response.ID] = response
+	}
+	return responses
+}
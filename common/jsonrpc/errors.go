@@ -1,5 +1,21 @@
 package jsonrpc
 
+import "fmt"
+
+// Error codes defined by jsonrpc specification.
+const (
+	// ParseErrorCode invalid JSON was received by the server.
+	ParseErrorCode = -32700
+	// InvalidRequestCode the JSON sent is not a valid request object.
+	InvalidRequestCode = -32600
+	// MethodNotFoundCode the method does not exist or is not available.
+	MethodNotFoundCode = -32601
+	// InvalidParamsCode invalid method parameters.
+	InvalidParamsCode = -32602
+	// InternalErrorCode internal jsonrpc error.
+	InternalErrorCode = -32603
+)
+
 // Error indicates any exceptional situation during operation execution,
 type Error struct {
 	// Code is the value indicating the certain error type.
@@ -12,3 +28,16 @@ type Error struct {
 	// information about the error e.g. stack trace, error time.
 	Data any `json:"data,omitempty"`
 }
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// NewError creates a new error with the passed code and message.
+func NewError(code int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
@@ -0,0 +1,24 @@
+package jsonrpc
+
+import "encoding/json"
+
+// Notification is a request sent by the server without an id, e.g. eth_subscription
+type Notification struct {
+	// Version of a notification, set to "2.0" as per specification.
+	Version string `json:"jsonrpc"`
+
+	// Method is the name of the notification.
+	Method string `json:"method"`
+
+	// Params are parameters of the notification.
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// SubscriptionParams are params of subscription notification
+type SubscriptionParams struct {
+	// Subscription is the id of the subscription returned by subscribe call.
+	Subscription string `json:"subscription"`
+
+	// Result is the payload of the subscription notification.
+	Result json.RawMessage `json:"result"`
+}
@@ -1,7 +1,44 @@
 package jsonrpc
 
-type ID int64
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidID is returned when id is not a number, string or null
+var ErrInvalidID = errors.New("id has to be a number, string or null")
+
+// ID is the raw json of request id, it is a number, string or null and is echoed back unchanged.
+// Zero value is the missing id of a notification, it is encoded as null.
+type ID string
+
+// NullID is the id of response to request whose id could not be read
+const NullID = ID("null")
 
 func NewID(id int64) ID {
-	return ID(id)
+	return ID(strconv.FormatInt(id, 10))
+}
+
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id == "" {
+		return []byte(NullID), nil
+	}
+	return []byte(id), nil
+}
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value.(type) {
+	case nil, string, float64:
+	default:
+		return ErrInvalidID
+	}
+	*id = ID(data)
+	return nil
 }
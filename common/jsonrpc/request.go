@@ -14,7 +14,7 @@ type Request struct {
 	// If a client needs to identify the result of the operation execution,
 	// the ID should be passed by the client, then it is guaranteed
 	// that the client will receive the result frame with the same id.
-	// If ID == 0 this is treated as a notification.
+	// Request without ID is treated as a notification, null ID is not.
 	ID ID `json:"id,omitempty"`
 
 	// Version of a request, set to "2.0" as per specification.
@@ -38,3 +38,8 @@ func NewRequest(method string, params json.RawMessage) *Request {
 		Params:  params,
 	}
 }
+
+// IsNotification returns true if the request is a notification, server does not reply to notifications.
+func (r *Request) IsNotification() bool {
+	return r.ID == ""
+}
@@ -4,8 +4,8 @@ import "encoding/json"
 
 // Response is a jsonrpc response object
 type Response struct {
-	// ID is the unique identifier of the request.
-	ID ID `json:"id,omitempty"`
+	// ID is the unique identifier of the request, it is null if id of the request could not be read.
+	ID ID `json:"id"`
 
 	// Version of a request, set to "2.0" as per specification.
 	Version string `json:"jsonrpc"`
@@ -17,3 +17,21 @@ type Response struct {
 	// Error is an error returned by the server if the RPC failed.
 	Error *Error `json:"error,omitempty"`
 }
+
+// NewResponse creates a new response with the result of the request with passed id.
+func NewResponse(id ID, result json.RawMessage) *Response {
+	return &Response{
+		ID:      id,
+		Version: "2.0",
+		Result:  result,
+	}
+}
+
+// NewErrorResponse creates a new response with the error of the request with passed id.
+func NewErrorResponse(id ID, err *Error) *Response {
+	return &Response{
+		ID:      id,
+		Version: "2.0",
+		Error:   err,
+	}
+}
@@ -1,38 +1,83 @@
 package blockchain
 
-// Block represents a block in the blockchain
+// Block represents a block in the blockchain,
+// fields introduced by later forks are nil or empty in blocks produced before them
 type Block struct {
-	Number           string         `json:"number,omitempty"`
-	Hash             string         `json:"hash,omitempty"`
-	ParentHash       string         `json:"parentHash,omitempty"`
-	Nonce            string         `json:"nonce,omitempty"`
-	Sha3Uncles       string         `json:"sha3Uncles,omitempty"`
-	LogsBloom        string         `json:"logsBloom,omitempty"`
-	TransactionsRoot string         `json:"transactionsRoot,omitempty"`
-	StateRoot        string         `json:"stateRoot,omitempty"`
-	Miner            string         `json:"miner,omitempty"`
-	Difficulty       string         `json:"difficulty,omitempty"`
-	TotalDifficulty  string         `json:"totalDifficulty,omitempty"`
-	ExtraData        string         `json:"extraData,omitempty"`
-	Size             string         `json:"size,omitempty"`
-	GasLimit         string         `json:"gasLimit,omitempty"`
-	GasUsed          string         `json:"gasUsed,omitempty"`
-	Timestamp        string         `json:"timestamp,omitempty"`
+	Number           Quantity       `json:"number"`
+	Hash             Hash           `json:"hash,omitempty"`
+	ParentHash       Hash           `json:"parentHash,omitempty"`
+	Nonce            Bytes          `json:"nonce,omitempty"`
+	Sha3Uncles       Hash           `json:"sha3Uncles,omitempty"`
+	LogsBloom        Bytes          `json:"logsBloom,omitempty"`
+	TransactionsRoot Hash           `json:"transactionsRoot,omitempty"`
+	StateRoot        Hash           `json:"stateRoot,omitempty"`
+	Miner            Address        `json:"miner,omitempty"`
+	Difficulty       Quantity       `json:"difficulty"`
+	TotalDifficulty  Quantity       `json:"totalDifficulty"`
+	ExtraData        Bytes          `json:"extraData,omitempty"`
+	Size             Quantity       `json:"size"`
+	GasLimit         Quantity       `json:"gasLimit"`
+	GasUsed          Quantity       `json:"gasUsed"`
+	Timestamp        Quantity       `json:"timestamp"`
 	Transactions     []*Transaction `json:"transactions,omitempty"`
-	Uncles           []string       `json:"uncles,omitempty"`
+	Uncles           []Hash         `json:"uncles,omitempty"`
+	// BaseFeePerGas is set since London (EIP-1559)
+	BaseFeePerGas *Quantity `json:"baseFeePerGas,omitempty"`
+	// WithdrawalsRoot and Withdrawals are set since Shanghai (EIP-4895)
+	WithdrawalsRoot Hash          `json:"withdrawalsRoot,omitempty"`
+	Withdrawals     []*Withdrawal `json:"withdrawals,omitempty"`
+	// BlobGasUsed, ExcessBlobGas and ParentBeaconBlockRoot are set since Cancun (EIP-4844, EIP-4788)
+	BlobGasUsed           *Quantity `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas         *Quantity `json:"excessBlobGas,omitempty"`
+	ParentBeaconBlockRoot Hash      `json:"parentBeaconBlockRoot,omitempty"`
 }
 
-// Transaction represents a transaction in the blockchain
+// Withdrawal is a withdrawal of validator stake from the beacon chain, amount is in gwei
+type Withdrawal struct {
+	Index          Quantity `json:"index"`
+	ValidatorIndex Quantity `json:"validatorIndex"`
+	Address        Address  `json:"address"`
+	Amount         Quantity `json:"amount"`
+}
+
+// Transaction represents a transaction in the blockchain,
+// recipient of contract creation transaction is empty.
+// Fee fields which are not part of the transaction type are nil, GasPrice is the price paid per gas by every type.
 type Transaction struct {
-	Hash             string `json:"hash,omitempty"`
-	Nonce            string `json:"nonce,omitempty"`
-	BlockHash        string `json:"blockHash,omitempty"`
-	BlockNumber      string `json:"blockNumber,omitempty"`
-	TransactionIndex string `json:"transactionIndex,omitempty"`
-	From             string `json:"from,omitempty"`
-	To               string `json:"to,omitempty"`
-	Value            string `json:"value,omitempty"`
-	GasPrice         string `json:"gasPrice,omitempty"`
-	Gas              string `json:"gas,omitempty"`
-	Input            string `json:"input,omitempty"`
+	Hash             Hash            `json:"hash,omitempty"`
+	Type             TransactionType `json:"type"`
+	Nonce            Quantity        `json:"nonce"`
+	BlockHash        Hash            `json:"blockHash,omitempty"`
+	BlockNumber      Quantity        `json:"blockNumber"`
+	TransactionIndex Quantity        `json:"transactionIndex"`
+	From             Address         `json:"from,omitempty"`
+	To               Address         `json:"to,omitempty"`
+	Value            Quantity        `json:"value"`
+	GasPrice         Quantity        `json:"gasPrice"`
+	Gas              Quantity        `json:"gas"`
+	Input            Bytes           `json:"input"`
+	// ChainID is missing only in legacy transactions without replay protection
+	ChainID *Quantity `json:"chainId,omitempty"`
+	// MaxFeePerGas and MaxPriorityFeePerGas are set in dynamic fee and blob transactions
+	MaxFeePerGas         *Quantity `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *Quantity `json:"maxPriorityFeePerGas,omitempty"`
+	// AccessList is set in all but legacy transactions
+	AccessList AccessList `json:"accessList,omitempty"`
+	// MaxFeePerBlobGas and BlobVersionedHashes are set in blob transactions
+	MaxFeePerBlobGas    *Quantity `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes []Hash    `json:"blobVersionedHashes,omitempty"`
+	V                   Quantity  `json:"v"`
+	R                   Quantity  `json:"r"`
+	S                   Quantity  `json:"s"`
+	// YParity is set in all but legacy transactions, it is the same as V
+	YParity *Quantity `json:"yParity,omitempty"`
+}
+
+// AccessList is a list of addresses and storage keys transaction accesses (EIP-2930)
+type AccessList []AccessTuple
+
+// AccessTuple is an address and its storage keys accessed by transaction
+type AccessTuple struct {
+	Address     Address `json:"address"`
+	StorageKeys []Hash  `json:"storageKeys"`
 }
@@ -0,0 +1,71 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// TransactionType is the type of transaction envelope (EIP-2718), it determines how transaction pays fees
+type TransactionType uint8
+
+const (
+	// LegacyTransactionType transaction pays gas price
+	LegacyTransactionType = TransactionType(0)
+	// AccessListTransactionType transaction pays gas price and declares access list (EIP-2930)
+	AccessListTransactionType = TransactionType(1)
+	// DynamicFeeTransactionType transaction pays base fee and priority fee capped by max fee (EIP-1559)
+	DynamicFeeTransactionType = TransactionType(2)
+	// BlobTransactionType transaction is dynamic fee transaction which also pays for blobs (EIP-4844)
+	BlobTransactionType = TransactionType(3)
+)
+
+// BlobGasPerBlob is blob gas used by a single blob (EIP-4844)
+const BlobGasPerBlob = 1 << 17
+
+// String returns 0x prefixed hex type as in json rpc
+func (t TransactionType) String() string {
+	return fmt.Sprintf("0x%x", uint8(t))
+}
+
+func (t TransactionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses 0x prefixed hex type, type is a single byte
+func (t *TransactionType) UnmarshalText(text []byte) error {
+	quantity, err := ParseQuantity(string(text))
+	if err != nil {
+		return err
+	}
+	if quantity.Cmp(QuantityFromInt64(0xff)) > 0 {
+		return fmt.Errorf("%w: transaction type %s", ErrInvalidQuantity, text)
+	}
+	*t = TransactionType(quantity.Int64())
+	return nil
+}
+
+// EffectiveGasPrice returns price paid per gas in block with the base fee,
+// dynamic fee transaction pays base fee and priority fee up to its max fee.
+// Price of legacy transaction, or of transaction without base fee, is its gas price.
+func (t *Transaction) EffectiveGasPrice(baseFeePerGas *Quantity) Quantity {
+	if t.MaxFeePerGas == nil || baseFeePerGas == nil {
+		return t.GasPrice
+	}
+	price := new(big.Int).Add(baseFeePerGas.Big(), t.priorityFeePerGas().Big())
+	if price.Cmp(t.MaxFeePerGas.Big()) > 0 {
+		return *t.MaxFeePerGas
+	}
+	return NewQuantity(price)
+}
+
+// BlobGasUsed returns blob gas used by the transaction, every blob uses the same amount of gas
+func (t *Transaction) BlobGasUsed() Quantity {
+	return QuantityFromInt64(int64(len(t.BlobVersionedHashes)) * BlobGasPerBlob)
+}
+
+func (t *Transaction) priorityFeePerGas() Quantity {
+	if t.MaxPriorityFeePerGas == nil {
+		return Quantity{}
+	}
+	return *t.MaxPriorityFeePerGas
+}
@@ -0,0 +1,64 @@
+package blockchain
+
+import (
+	"math/big"
+)
+
+// TransactionKind is the kind of transaction derived from its recipient and input
+type TransactionKind string
+
+const (
+	// NativeTransferKind transaction transfers ether without input
+	NativeTransferKind = TransactionKind("native_transfer")
+	// ContractCallKind transaction calls a contract
+	ContractCallKind = TransactionKind("contract_call")
+	// ContractCreationKind transaction has no recipient and deploys a contract
+	ContractCreationKind = TransactionKind("contract_creation")
+	// ERC20TransferKind transaction calls transfer or transferFrom of ERC-20 token
+	ERC20TransferKind = TransactionKind("erc20_transfer")
+)
+
+const (
+	// ERC20TransferSelector is the selector of transfer(address,uint256)
+	ERC20TransferSelector = "0xa9059cbb"
+	// ERC20TransferFromSelector is the selector of transferFrom(address,address,uint256)
+	ERC20TransferFromSelector = "0x23b872dd"
+)
+
+// IsValid checks if kind is one of known transaction kinds
+func (k TransactionKind) IsValid() bool {
+	switch k {
+	case NativeTransferKind, ContractCallKind, ContractCreationKind, ERC20TransferKind:
+		return true
+	}
+	return false
+}
+
+// Kind returns the kind of the transaction,
+// ERC-20 transfer is recognized by selector of the called method, so it is not a contract call kind
+func (t *Transaction) Kind() TransactionKind {
+	if t.To.IsZero() {
+		return ContractCreationKind
+	}
+	switch t.MethodSelector() {
+	case "":
+		return NativeTransferKind
+	case ERC20TransferSelector, ERC20TransferFromSelector:
+		return ERC20TransferKind
+	}
+	return ContractCallKind
+}
+
+// MethodSelector returns lower case hex encoded first four bytes of the input,
+// it is empty when input is shorter than selector
+func (t *Transaction) MethodSelector() string {
+	if len(t.Input) < 4 {
+		return ""
+	}
+	return t.Input[:4].String()
+}
+
+// ValueWei returns value of the transaction in wei
+func (t *Transaction) ValueWei() *big.Int {
+	return t.Value.Big()
+}
@@ -0,0 +1,230 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// AddressLength is the length of address in bytes
+	AddressLength = 20
+	// HashLength is the length of hash in bytes
+	HashLength = 32
+	// maxQuantityDigits is the maximum number of hex digits of 256 bit quantity
+	maxQuantityDigits = 64
+)
+
+var (
+	// ErrInvalidAddress is returned when address is not 0x prefixed hex of 20 bytes
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrInvalidHash is returned when hash is not 0x prefixed hex of 32 bytes
+	ErrInvalidHash = errors.New("invalid hash")
+	// ErrInvalidQuantity is returned when quantity is not 0x prefixed hex number of at most 256 bits
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	// ErrInvalidBytes is returned when bytes are not 0x prefixed hex of even length
+	ErrInvalidBytes = errors.New("invalid bytes")
+)
+
+// Address is an account address in canonical lower case hex form, so addresses are compared with ==.
+// Empty address is the missing recipient of contract creation transaction.
+type Address string
+
+// ParseAddress parses 0x prefixed hex address of 20 bytes, hex digits can be of any case
+func ParseAddress(value string) (Address, error) {
+	if !isFixedHex(value, AddressLength) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAddress, value)
+	}
+	return Address(strings.ToLower(value)), nil
+}
+
+func (a Address) String() string {
+	return string(a)
+}
+
+// IsZero checks if address is missing
+func (a Address) IsZero() bool {
+	return a == ""
+}
+
+// UnmarshalText parses address, empty text is missing address
+func (a *Address) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = ""
+		return nil
+	}
+	address, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = address
+	return nil
+}
+
+// Hash is a block or transaction hash in canonical lower case hex form, so hashes are compared with ==.
+// Empty hash is missing hash, e.g. block hash of pending transaction.
+type Hash string
+
+// ParseHash parses 0x prefixed hex hash of 32 bytes, hex digits can be of any case
+func ParseHash(value string) (Hash, error) {
+	if !isFixedHex(value, HashLength) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidHash, value)
+	}
+	return Hash(strings.ToLower(value)), nil
+}
+
+func (h Hash) String() string {
+	return string(h)
+}
+
+// IsZero checks if hash is missing
+func (h Hash) IsZero() bool {
+	return h == ""
+}
+
+// UnmarshalText parses hash, empty text is missing hash
+func (h *Hash) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*h = ""
+		return nil
+	}
+	hash, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = hash
+	return nil
+}
+
+// Quantity is an unsigned integer of at most 256 bits encoded as 0x prefixed hex number in json rpc,
+// zero value is zero. Quantity is immutable, its big.Int is never shared.
+type Quantity struct {
+	value *big.Int
+}
+
+// NewQuantity returns quantity of the value, negative value is not a valid quantity
+func NewQuantity(value *big.Int) Quantity {
+	if value == nil {
+		return Quantity{}
+	}
+	return Quantity{value: new(big.Int).Set(value)}
+}
+
+// QuantityFromInt64 returns quantity of the value
+func QuantityFromInt64(value int64) Quantity {
+	return Quantity{value: big.NewInt(value)}
+}
+
+// ParseQuantity parses 0x prefixed hex number of at most 256 bits, hex digits can be of any case
+func ParseQuantity(value string) (Quantity, error) {
+	digits, ok := strings.CutPrefix(value, "0x")
+	// big.Int accepts sign before digits, quantity is only digits
+	if !ok || digits == "" || len(digits) > maxQuantityDigits || !isHexDigits(digits) {
+		return Quantity{}, fmt.Errorf("%w: %q", ErrInvalidQuantity, value)
+	}
+	parsed, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return Quantity{}, fmt.Errorf("%w: %q", ErrInvalidQuantity, value)
+	}
+	return Quantity{value: parsed}, nil
+}
+
+// Big returns copy of the value
+func (q Quantity) Big() *big.Int {
+	if q.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(q.value)
+}
+
+// Int64 returns the value as int64, value which does not fit is truncated
+func (q Quantity) Int64() int64 {
+	if q.value == nil {
+		return 0
+	}
+	return q.value.Int64()
+}
+
+// IsZero checks if value is zero
+func (q Quantity) IsZero() bool {
+	return q.value == nil || q.value.Sign() == 0
+}
+
+// Cmp compares quantities, it returns -1, 0 or 1 if quantity is less than, equal or greater than the other
+func (q Quantity) Cmp(other Quantity) int {
+	return q.Big().Cmp(other.Big())
+}
+
+// String returns 0x prefixed hex number without leading zeros
+func (q Quantity) String() string {
+	if q.value == nil {
+		return "0x0"
+	}
+	return "0x" + q.value.Text(16)
+}
+
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+func (q *Quantity) UnmarshalText(text []byte) error {
+	quantity, err := ParseQuantity(string(text))
+	if err != nil {
+		return err
+	}
+	*q = quantity
+	return nil
+}
+
+// Bytes are arbitrary binary data encoded as 0x prefixed hex in json rpc, e.g. transaction input
+type Bytes []byte
+
+// ParseBytes parses 0x prefixed hex of even length, hex digits can be of any case
+func ParseBytes(value string) (Bytes, error) {
+	digits, ok := strings.CutPrefix(value, "0x")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidBytes, value)
+	}
+	decoded, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidBytes, value)
+	}
+	return decoded, nil
+}
+
+// String returns 0x prefixed lower case hex
+func (b Bytes) String() string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b *Bytes) UnmarshalText(text []byte) error {
+	decoded, err := ParseBytes(string(text))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// isFixedHex checks if value is 0x prefixed hex of the given number of bytes
+func isFixedHex(value string, length int) bool {
+	digits, ok := strings.CutPrefix(value, "0x")
+	return ok && len(digits) == 2*length && isHexDigits(digits)
+}
+
+// isHexDigits checks if every character of digits is hex digit of any case
+func isHexDigits(digits string) bool {
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
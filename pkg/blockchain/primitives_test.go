@@ -0,0 +1,99 @@
+package blockchain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		valid    bool
+	}{
+		{value: "0x0", expected: "0x0", valid: true},
+		{value: "0xff", expected: "0xff", valid: true},
+		{value: "0xFF", expected: "0xff", valid: true},
+		{value: "0x000a", expected: "0xa", valid: true},
+		{value: "0x" + strings.Repeat("f", maxQuantityDigits), expected: "0x" + strings.Repeat("f", maxQuantityDigits), valid: true},
+		{value: "0x" + strings.Repeat("f", maxQuantityDigits+1)},
+		{value: ""},
+		{value: "0x"},
+		{value: "ff"},
+		{value: "0Xff"},
+		{value: "0x-1"},
+		{value: "0x+1"},
+		{value: "0xg"},
+		{value: "0x1 "},
+		{value: "0x_1"},
+	}
+	for _, test := range tests {
+		quantity, err := ParseQuantity(test.value)
+		if !test.valid {
+			if !errors.Is(err, ErrInvalidQuantity) {
+				t.Errorf("expected invalid quantity error of %q, got %v", test.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expected quantity %q to be parsed, got %s", test.value, err)
+			continue
+		}
+		if quantity.String() != test.expected {
+			t.Errorf("expected quantity %s of %q, got %s", test.expected, test.value, quantity)
+		}
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	var quantity Quantity
+	if err := quantity.UnmarshalText([]byte("0x-1")); !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("expected invalid quantity error, got %v", err)
+	}
+	if err := quantity.UnmarshalText([]byte("0x3e8")); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := quantity.MarshalText(); err != nil || string(text) != "0x3e8" {
+		t.Fatalf("expected 0x3e8, got %s and error %v", text, err)
+	}
+	if zero := (Quantity{}); zero.String() != "0x0" {
+		t.Fatalf("expected zero value to be 0x0, got %s", zero)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Address
+		valid    bool
+	}{
+		{value: "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5", expected: senderAddress, valid: true},
+		{value: "0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5", expected: senderAddress, valid: true},
+		{value: "0x0000000000000000000000000000000000000000", expected: "0x0000000000000000000000000000000000000000", valid: true},
+		{value: ""},
+		{value: "0x"},
+		{value: "95222290dd7278aa3ddd389cc1e1d165cc4bafe5"},
+		{value: "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe"},
+		{value: "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe500"},
+		{value: "0x95222290dd7278aa3ddd389cc1e1d165cc4bafeg"},
+		{value: "0X95222290dd7278aa3ddd389cc1e1d165cc4bafe5"},
+		{value: AddressTopic(senderAddress).String()},
+	}
+	for _, test := range tests {
+		address, err := ParseAddress(test.value)
+		if !test.valid {
+			if !errors.Is(err, ErrInvalidAddress) {
+				t.Errorf("expected invalid address error of %q, got %v", test.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expected address %q to be parsed, got %s", test.value, err)
+			continue
+		}
+		if address != test.expected {
+			t.Errorf("expected address %s of %q, got %s", test.expected, test.value, address)
+		}
+	}
+}
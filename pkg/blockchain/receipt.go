@@ -0,0 +1,49 @@
+package blockchain
+
+// Receipt is the result of executing a transaction
+type Receipt struct {
+	TransactionHash   Hash            `json:"transactionHash"`
+	TransactionIndex  Quantity        `json:"transactionIndex"`
+	BlockHash         Hash            `json:"blockHash"`
+	BlockNumber       Quantity        `json:"blockNumber"`
+	From              Address         `json:"from"`
+	To                Address         `json:"to,omitempty"`
+	Type              TransactionType `json:"type"`
+	CumulativeGasUsed Quantity        `json:"cumulativeGasUsed"`
+	GasUsed           Quantity        `json:"gasUsed"`
+	EffectiveGasPrice Quantity        `json:"effectiveGasPrice"`
+	// ContractAddress is the address of contract deployed by contract creation transaction
+	ContractAddress Address `json:"contractAddress,omitempty"`
+	Logs            []*Log  `json:"logs"`
+	LogsBloom       Bytes   `json:"logsBloom,omitempty"`
+	// Status is 0x1 for successful and 0x0 for failed transaction, it is missing before Byzantium,
+	// when receipts have state root instead
+	Status *Quantity `json:"status,omitempty"`
+	Root   Hash      `json:"root,omitempty"`
+	// BlobGasUsed and BlobGasPrice are set in receipts of blob transactions
+	BlobGasUsed  *Quantity `json:"blobGasUsed,omitempty"`
+	BlobGasPrice *Quantity `json:"blobGasPrice,omitempty"`
+}
+
+// Succeeded checks if transaction was executed successfully, status of receipt before Byzantium is unknown
+func (r *Receipt) Succeeded() bool {
+	return r.Status != nil && !r.Status.IsZero()
+}
+
+// Failed checks if transaction was reverted, status of receipt before Byzantium is unknown
+func (r *Receipt) Failed() bool {
+	return r.Status != nil && r.Status.IsZero()
+}
+
+// Log is an event emitted by a contract during execution of a transaction
+type Log struct {
+	Address          Address  `json:"address"`
+	Topics           []Hash   `json:"topics"`
+	Data             Bytes    `json:"data"`
+	BlockNumber      Quantity `json:"blockNumber"`
+	BlockHash        Hash     `json:"blockHash"`
+	TransactionHash  Hash     `json:"transactionHash"`
+	TransactionIndex Quantity `json:"transactionIndex"`
+	LogIndex         Quantity `json:"logIndex"`
+	Removed          bool     `json:"removed"`
+}
@@ -0,0 +1,247 @@
+package blockchain
+
+import (
+	"math/big"
+	"strings"
+)
+
+const (
+	// TransferEventTopic is the topic of Transfer(address,address,uint256) event of ERC-20 and ERC-721 tokens
+	TransferEventTopic = Hash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	// TransferSingleEventTopic is the topic of TransferSingle(address,address,address,uint256,uint256) event of ERC-1155 tokens
+	TransferSingleEventTopic = Hash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62")
+	// TransferBatchEventTopic is the topic of TransferBatch(address,address,address,uint256[],uint256[]) event of ERC-1155 tokens
+	TransferBatchEventTopic = Hash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb")
+
+	// wordLength is the length of abi encoded word in bytes
+	wordLength = 32
+	// maxBatchLength is the maximum number of transfers decoded from a single batch event
+	maxBatchLength = 1024
+)
+
+// TokenTransferTopics are topics of events decoded as token transfers
+var TokenTransferTopics = []Hash{TransferEventTopic, TransferSingleEventTopic, TransferBatchEventTopic}
+
+// TokenStandard is the standard of transferred token
+type TokenStandard string
+
+const (
+	ERC20Standard   = TokenStandard("erc20")
+	ERC721Standard  = TokenStandard("erc721")
+	ERC1155Standard = TokenStandard("erc1155")
+)
+
+// IsValid checks if standard is one of known token standards
+func (s TokenStandard) IsValid() bool {
+	switch s {
+	case ERC20Standard, ERC721Standard, ERC1155Standard:
+		return true
+	}
+	return false
+}
+
+// TokenTransfer is a transfer of tokens decoded from event log of the token contract.
+// Zero sender is mint and zero recipient is burn.
+type TokenTransfer struct {
+	Standard TokenStandard `json:"standard"`
+	// Token is the address of token contract which emitted the event
+	Token Address `json:"token"`
+	// Operator is the address which executed ERC-1155 transfer on behalf of the sender
+	Operator Address `json:"operator,omitempty"`
+	From     Address `json:"from"`
+	To       Address `json:"to"`
+	// Value is the amount of ERC-20 and ERC-1155 tokens, it is 1 for ERC-721 token
+	Value Quantity `json:"value"`
+	// TokenID is the id of ERC-721 and ERC-1155 token
+	TokenID          *Quantity `json:"tokenId,omitempty"`
+	TransactionHash  Hash      `json:"transactionHash"`
+	TransactionIndex Quantity  `json:"transactionIndex"`
+	BlockHash        Hash      `json:"blockHash"`
+	BlockNumber      Quantity  `json:"blockNumber"`
+	LogIndex         Quantity  `json:"logIndex"`
+	// BatchIndex is the index of the transfer in ERC-1155 batch event, it is 0 for other events
+	BatchIndex int64 `json:"batchIndex"`
+}
+
+// DecodeTokenTransfers decodes token transfers of Transfer, TransferSingle and TransferBatch event,
+// it returns nil for removed log, log of other event and log which does not follow the standard.
+// Transfer event is ERC-20 transfer if value is in data and ERC-721 transfer if token id is the last topic.
+func DecodeTokenTransfers(log *Log) []*TokenTransfer {
+	if log.Removed || len(log.Topics) == 0 {
+		return nil
+	}
+	switch log.Topics[0] {
+	case TransferEventTopic:
+		return decodeTransfer(log)
+	case TransferSingleEventTopic:
+		return decodeTransferSingle(log)
+	case TransferBatchEventTopic:
+		return decodeTransferBatch(log)
+	}
+	return nil
+}
+
+// decodeTransfer decodes Transfer(address indexed from, address indexed to, uint256 value) of ERC-20
+// or Transfer(address indexed from, address indexed to, uint256 indexed tokenId) of ERC-721
+func decodeTransfer(log *Log) []*TokenTransfer {
+	if len(log.Topics) < 3 {
+		return nil
+	}
+	from, ok := topicAddress(log.Topics[1])
+	if !ok {
+		return nil
+	}
+	to, ok := topicAddress(log.Topics[2])
+	if !ok {
+		return nil
+	}
+	switch {
+	case len(log.Topics) == 3 && len(log.Data) == wordLength:
+		transfer := newTokenTransfer(log, ERC20Standard, from, to)
+		transfer.Value = NewQuantity(new(big.Int).SetBytes(log.Data))
+		return []*TokenTransfer{transfer}
+	case len(log.Topics) == 4 && len(log.Data) == 0:
+		transfer := newTokenTransfer(log, ERC721Standard, from, to)
+		transfer.Value = QuantityFromInt64(1)
+		transfer.TokenID = topicQuantity(log.Topics[3])
+		return []*TokenTransfer{transfer}
+	}
+	return nil
+}
+
+// decodeTransferSingle decodes TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)
+func decodeTransferSingle(log *Log) []*TokenTransfer {
+	operator, from, to, ok := erc1155Addresses(log)
+	if !ok || len(log.Data) != 2*wordLength {
+		return nil
+	}
+	transfer := newTokenTransfer(log, ERC1155Standard, from, to)
+	transfer.Operator = operator
+	id := NewQuantity(new(big.Int).SetBytes(log.Data[:wordLength]))
+	transfer.TokenID = &id
+	transfer.Value = NewQuantity(new(big.Int).SetBytes(log.Data[wordLength:]))
+	return []*TokenTransfer{transfer}
+}
+
+// decodeTransferBatch decodes TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values),
+// every id and value pair is a separate transfer
+func decodeTransferBatch(log *Log) []*TokenTransfer {
+	operator, from, to, ok := erc1155Addresses(log)
+	if !ok {
+		return nil
+	}
+	ids, ok := decodeUintArray(log.Data, 0)
+	if !ok {
+		return nil
+	}
+	values, ok := decodeUintArray(log.Data, 1)
+	if !ok || len(ids) != len(values) {
+		return nil
+	}
+	transfers := make([]*TokenTransfer, 0, len(ids))
+	for i := range ids {
+		transfer := newTokenTransfer(log, ERC1155Standard, from, to)
+		transfer.Operator = operator
+		id := NewQuantity(ids[i])
+		transfer.TokenID = &id
+		transfer.Value = NewQuantity(values[i])
+		transfer.BatchIndex = int64(i)
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
+
+func newTokenTransfer(log *Log, standard TokenStandard, from Address, to Address) *TokenTransfer {
+	return &TokenTransfer{
+		Standard:         standard,
+		Token:            log.Address,
+		From:             from,
+		To:               to,
+		TransactionHash:  log.TransactionHash,
+		TransactionIndex: log.TransactionIndex,
+		BlockHash:        log.BlockHash,
+		BlockNumber:      log.BlockNumber,
+		LogIndex:         log.LogIndex,
+	}
+}
+
+// erc1155Addresses returns operator, sender and recipient of ERC-1155 event from its topics
+func erc1155Addresses(log *Log) (Address, Address, Address, bool) {
+	if len(log.Topics) != 4 {
+		return "", "", "", false
+	}
+	operator, ok := topicAddress(log.Topics[1])
+	if !ok {
+		return "", "", "", false
+	}
+	from, ok := topicAddress(log.Topics[2])
+	if !ok {
+		return "", "", "", false
+	}
+	to, ok := topicAddress(log.Topics[3])
+	if !ok {
+		return "", "", "", false
+	}
+	return operator, from, to, true
+}
+
+// AddressTopic returns indexed address topic of the address, the address is right aligned in the topic,
+// logs of events sent or received by the address are filtered by it
+func AddressTopic(address Address) Hash {
+	padding := strings.Repeat("0", 2*(HashLength-AddressLength))
+	return Hash("0x" + padding + strings.TrimPrefix(address.String(), "0x"))
+}
+
+// topicAddress returns address of indexed address topic, the address is right aligned in the topic
+func topicAddress(topic Hash) (Address, bool) {
+	// topic is lower case 0x prefixed hex of 32 bytes, address is in its last 20 bytes
+	padding := 2 + 2*(HashLength-AddressLength)
+	if len(topic) != 2+2*HashLength {
+		return "", false
+	}
+	for _, c := range topic[2:padding] {
+		if c != '0' {
+			return "", false
+		}
+	}
+	return Address("0x" + topic[padding:]), true
+}
+
+// topicQuantity returns uint256 of indexed topic
+func topicQuantity(topic Hash) *Quantity {
+	quantity, err := ParseQuantity(topic.String())
+	if err != nil {
+		return nil
+	}
+	return &quantity
+}
+
+// decodeUintArray decodes abi encoded dynamic uint256 array which is the argument at the index of data
+func decodeUintArray(data Bytes, argument int) ([]*big.Int, bool) {
+	offset, ok := decodeWordInt(data, argument*wordLength)
+	if !ok {
+		return nil, false
+	}
+	length, ok := decodeWordInt(data, offset)
+	if !ok || length > maxBatchLength || offset+wordLength+length*wordLength > len(data) {
+		return nil, false
+	}
+	values := make([]*big.Int, 0, length)
+	for i := 0; i < length; i++ {
+		start := offset + wordLength + i*wordLength
+		values = append(values, new(big.Int).SetBytes(data[start:start+wordLength]))
+	}
+	return values, true
+}
+
+// decodeWordInt decodes word at the position of data as int, word which does not fit into data length is invalid
+func decodeWordInt(data Bytes, position int) (int, bool) {
+	if position < 0 || position+wordLength > len(data) {
+		return 0, false
+	}
+	value := new(big.Int).SetBytes(data[position : position+wordLength])
+	if !value.IsInt64() || value.Int64() > int64(len(data)) {
+		return 0, false
+	}
+	return int(value.Int64()), true
+}
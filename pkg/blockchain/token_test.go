@@ -0,0 +1,291 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const (
+	tokenAddress    = Address("0x6b175474e89094c44da98b954eedeac495271d0f")
+	senderAddress   = Address("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	recipientAddr   = Address("0x00000000219ab540356cbb839cbe05303d7705fa")
+	operatorAddress = Address("0x1e0049783f008a0085193e00003d00cd54003c71")
+)
+
+// words returns abi encoding of the values, every value is a 32 byte word
+func words(values ...int64) Bytes {
+	data := make(Bytes, 0, len(values)*wordLength)
+	for _, value := range values {
+		word := make([]byte, wordLength)
+		big.NewInt(value).FillBytes(word)
+		data = append(data, word...)
+	}
+	return data
+}
+
+func quantityTopic(value int64) Hash {
+	return Hash(fmt.Sprintf("0x%064x", value))
+}
+
+// describe returns comparable description of decoded transfers
+func describe(transfers []*TokenTransfer) []string {
+	descriptions := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		tokenID := "-"
+		if transfer.TokenID != nil {
+			tokenID = transfer.TokenID.String()
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s %s %s->%s value %s id %s operator %s batch %d",
+			transfer.Standard, transfer.Token, transfer.From, transfer.To, transfer.Value, tokenID, transfer.Operator, transfer.BatchIndex))
+	}
+	return descriptions
+}
+
+func TestDecodeTokenTransfers(t *testing.T) {
+	erc1155Topics := func(event Hash) []Hash {
+		return []Hash{event, AddressTopic(operatorAddress), AddressTopic(senderAddress), AddressTopic(recipientAddr)}
+	}
+	tests := []struct {
+		name      string
+		log       *Log
+		transfers []string
+	}{
+		{
+			name: "erc20 transfer",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr)},
+				Data:   words(1000),
+			},
+			transfers: []string{"erc20 " + tokenAddress.String() + " " + senderAddress.String() + "->" + recipientAddr.String() + " value 0x3e8 id - operator  batch 0"},
+		},
+		{
+			name: "erc721 transfer",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr), quantityTopic(42)},
+			},
+			transfers: []string{"erc721 " + tokenAddress.String() + " " + senderAddress.String() + "->" + recipientAddr.String() + " value 0x1 id 0x2a operator  batch 0"},
+		},
+		{
+			name: "erc20 mint",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic("0x0000000000000000000000000000000000000000"), AddressTopic(recipientAddr)},
+				Data:   words(1),
+			},
+			transfers: []string{"erc20 " + tokenAddress.String() + " 0x0000000000000000000000000000000000000000->" + recipientAddr.String() + " value 0x1 id - operator  batch 0"},
+		},
+		{
+			name: "transfer with indexed value and data",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr), quantityTopic(42)},
+				Data:   words(1000),
+			},
+		},
+		{
+			name: "transfer without value",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr)},
+			},
+		},
+		{
+			name: "transfer with truncated value",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr)},
+				Data:   words(1000)[:wordLength-1],
+			},
+		},
+		{
+			name: "transfer without recipient",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, AddressTopic(senderAddress)},
+				Data:   words(1000),
+			},
+		},
+		{
+			name: "transfer with topic which is not address",
+			log: &Log{
+				Topics: []Hash{TransferEventTopic, "0xffffffffffffffffffffffff95222290dd7278aa3ddd389cc1e1d165cc4bafe5", AddressTopic(recipientAddr)},
+				Data:   words(1000),
+			},
+		},
+		{
+			name: "removed log",
+			log: &Log{
+				Topics:  []Hash{TransferEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr)},
+				Data:    words(1000),
+				Removed: true,
+			},
+		},
+		{
+			name: "other event",
+			log: &Log{
+				Topics: []Hash{Hash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"), AddressTopic(senderAddress), AddressTopic(recipientAddr)},
+				Data:   words(1000),
+			},
+		},
+		{
+			name: "log without topics",
+			log:  &Log{Data: words(1000)},
+		},
+		{
+			name: "transfer single",
+			log: &Log{
+				Topics: erc1155Topics(TransferSingleEventTopic),
+				Data:   words(7, 3),
+			},
+			transfers: []string{"erc1155 " + tokenAddress.String() + " " + senderAddress.String() + "->" + recipientAddr.String() + " value 0x3 id 0x7 operator " + operatorAddress.String() + " batch 0"},
+		},
+		{
+			name: "transfer single without value",
+			log: &Log{
+				Topics: erc1155Topics(TransferSingleEventTopic),
+				Data:   words(7),
+			},
+		},
+		{
+			name: "transfer single without operator",
+			log: &Log{
+				Topics: []Hash{TransferSingleEventTopic, AddressTopic(senderAddress), AddressTopic(recipientAddr)},
+				Data:   words(7, 3),
+			},
+		},
+		{
+			name: "transfer batch",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				// ids at offset 64, values at offset 160
+				Data: words(64, 160, 2, 1, 2, 2, 10, 20),
+			},
+			transfers: []string{
+				"erc1155 " + tokenAddress.String() + " " + senderAddress.String() + "->" + recipientAddr.String() + " value 0xa id 0x1 operator " + operatorAddress.String() + " batch 0",
+				"erc1155 " + tokenAddress.String() + " " + senderAddress.String() + "->" + recipientAddr.String() + " value 0x14 id 0x2 operator " + operatorAddress.String() + " batch 1",
+			},
+		},
+		{
+			name: "empty transfer batch",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(64, 96, 0, 0),
+			},
+			transfers: []string{},
+		},
+		{
+			name: "transfer batch with offset after data",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(1024, 160, 2, 1, 2, 2, 10, 20),
+			},
+		},
+		{
+			name: "transfer batch with offset in last word",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(64, 240, 2, 1, 2, 2, 10, 20),
+			},
+		},
+		{
+			name: "transfer batch with length after data",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(64, 160, 5, 1, 2, 2, 10, 20),
+			},
+		},
+		{
+			name: "transfer batch with more ids than values",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(64, 192, 3, 1, 2, 3, 2, 10, 20),
+			},
+		},
+		{
+			name: "transfer batch with truncated values",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(64, 160, 2, 1, 2, 2, 10, 20)[:7*wordLength+1],
+			},
+		},
+		{
+			name: "transfer batch without values",
+			log: &Log{
+				Topics: erc1155Topics(TransferBatchEventTopic),
+				Data:   words(64),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.log.Address = tokenAddress
+			transfers := DecodeTokenTransfers(test.log)
+			if test.transfers == nil {
+				if transfers != nil {
+					t.Fatalf("expected log to be skipped, got %v", describe(transfers))
+				}
+				return
+			}
+			if got, expected := strings.Join(describe(transfers), "\n"), strings.Join(test.transfers, "\n"); got != expected {
+				t.Fatalf("expected transfers\n%s\ngot\n%s", expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeUintArray(t *testing.T) {
+	huge := make(Bytes, wordLength)
+	huge[0] = 0x80
+	tests := []struct {
+		name     string
+		data     Bytes
+		argument int
+		values   []int64
+		ok       bool
+	}{
+		{name: "first argument", data: words(64, 128, 1, 5, 1, 6), argument: 0, values: []int64{5}, ok: true},
+		{name: "second argument", data: words(64, 128, 1, 5, 1, 6), argument: 1, values: []int64{6}, ok: true},
+		{name: "shared array", data: words(64, 64, 2, 5, 6), argument: 1, values: []int64{5, 6}, ok: true},
+		{name: "empty data", data: nil, argument: 0},
+		{name: "missing argument", data: words(32, 0), argument: 1},
+		{name: "offset over 64 bits", data: append(huge, words(0)...), argument: 0},
+		{name: "offset after data", data: words(96, 1, 5), argument: 0},
+		{name: "length after data", data: words(32, 3, 5, 6), argument: 0},
+		{name: "length over maximum", data: append(words(32, maxBatchLength+1), make(Bytes, (maxBatchLength+1)*wordLength)...), argument: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, ok := decodeUintArray(test.data, test.argument)
+			if ok != test.ok {
+				t.Fatalf("expected ok %t, got %t", test.ok, ok)
+			}
+			if fmt.Sprint(values) != fmt.Sprint(test.values) {
+				t.Fatalf("expected values %v, got %v", test.values, values)
+			}
+		})
+	}
+}
+
+func TestAddressTopic(t *testing.T) {
+	for _, address := range []Address{
+		"0x0000000000000000000000000000000000000000",
+		senderAddress,
+		"0xffffffffffffffffffffffffffffffffffffffff",
+	} {
+		topic := AddressTopic(address)
+		if _, err := ParseHash(topic.String()); err != nil {
+			t.Fatalf("topic %s of address %s is not a hash: %s", topic, address, err)
+		}
+		if parsed, ok := topicAddress(topic); !ok || parsed != address {
+			t.Fatalf("expected address %s of topic %s, got %s", address, topic, parsed)
+		}
+	}
+
+	for _, topic := range []Hash{
+		"0x000000000000000000000001" + Hash(strings.TrimPrefix(senderAddress.String(), "0x")),
+		Hash(senderAddress),
+		"0x",
+		AddressTopic(senderAddress) + "00",
+	} {
+		if address, ok := topicAddress(topic); ok {
+			t.Fatalf("expected topic %s to be rejected, got address %s", topic, address)
+		}
+	}
+}
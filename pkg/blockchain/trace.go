@@ -0,0 +1,113 @@
+package blockchain
+
+// CallType is the type of call frame of traced transaction
+type CallType string
+
+const (
+	CallCallType         = CallType("call")
+	CallCodeCallType     = CallType("callcode")
+	DelegateCallCallType = CallType("delegatecall")
+	StaticCallCallType   = CallType("staticcall")
+	CreateCallType       = CallType("create")
+	Create2CallType      = CallType("create2")
+	SelfDestructCallType = CallType("selfdestruct")
+)
+
+// TransfersValue checks if call of the type moves value from the caller to another account,
+// delegatecall and staticcall do not move value and callcode moves it back to the caller
+func (t CallType) TransfersValue() bool {
+	switch t {
+	case CallCallType, CreateCallType, Create2CallType, SelfDestructCallType:
+		return true
+	}
+	return false
+}
+
+// CallFrame is a call made during execution of transaction, Calls are calls made by it in order of execution.
+// Failed frame is reverted together with all its calls.
+type CallFrame struct {
+	Type  CallType  `json:"type"`
+	From  Address   `json:"from"`
+	To    Address   `json:"to"`
+	Value *Quantity `json:"value,omitempty"`
+	// Error is set when call failed, e.g. it reverted or ran out of gas
+	Error string       `json:"error,omitempty"`
+	Calls []*CallFrame `json:"calls,omitempty"`
+}
+
+// TransactionTrace is the call tree of the transaction, Call is the top level call of the transaction itself
+type TransactionTrace struct {
+	TransactionHash  Hash
+	TransactionIndex Quantity
+	BlockHash        Hash
+	BlockNumber      Quantity
+	Call             *CallFrame
+}
+
+// InternalTransfer is a value transfer made by a call inside of transaction, e.g. ETH sent by multisig or exchange hot wallet
+type InternalTransfer struct {
+	Type  CallType `json:"type"`
+	From  Address  `json:"from"`
+	To    Address  `json:"to"`
+	Value Quantity `json:"value"`
+	// TransactionHash is the hash of the parent transaction
+	TransactionHash  Hash     `json:"transactionHash"`
+	TransactionIndex Quantity `json:"transactionIndex"`
+	BlockHash        Hash     `json:"blockHash"`
+	BlockNumber      Quantity `json:"blockNumber"`
+	// TracePath is the path of the call frame in the call tree, indexes of calls from the top level call,
+	// TraceIndex is the index of the call frame in depth first order of the call tree, the top level call is 0
+	TracePath  []int `json:"tracePath"`
+	TraceIndex int64 `json:"traceIndex"`
+}
+
+// InternalTransfers walks call tree of the transaction and returns value transfers of its nested calls in order of execution.
+// The top level call is the transaction itself and frames of failed calls with their calls are skipped,
+// as their value transfers are reverted.
+func (t *TransactionTrace) InternalTransfers() []*InternalTransfer {
+	if t.Call == nil {
+		return nil
+	}
+	transfers := make([]*InternalTransfer, 0)
+	var traceIndex int64
+	var walk func(frame *CallFrame, path []int)
+	walk = func(frame *CallFrame, path []int) {
+		index := traceIndex
+		traceIndex++
+		if frame.Error != "" {
+			// frames of reverted calls are still counted, so trace index does not depend on their outcome
+			traceIndex += countCalls(frame.Calls)
+			return
+		}
+		if len(path) > 0 && frame.Type.TransfersValue() && frame.Value != nil && !frame.Value.IsZero() {
+			transfers = append(transfers, &InternalTransfer{
+				Type:             frame.Type,
+				From:             frame.From,
+				To:               frame.To,
+				Value:            *frame.Value,
+				TransactionHash:  t.TransactionHash,
+				TransactionIndex: t.TransactionIndex,
+				BlockHash:        t.BlockHash,
+				BlockNumber:      t.BlockNumber,
+				TracePath:        path,
+				TraceIndex:       index,
+			})
+		}
+		for i, call := range frame.Calls {
+			callPath := make([]int, len(path), len(path)+1)
+			copy(callPath, path)
+			walk(call, append(callPath, i))
+		}
+	}
+	walk(t.Call, []int{})
+	return transfers
+}
+
+// countCalls returns number of call frames in the call trees
+func countCalls(calls []*CallFrame) int64 {
+	count := int64(len(calls))
+	for _, call := range calls {
+		count += countCalls(call.Calls)
+	}
+	return count
+}
@@ -2,9 +2,6 @@ package blockchain
 
 import (
 	"fmt"
-	"log"
-	"strconv"
-	"strings"
 )
 
 const (
@@ -27,6 +24,18 @@ func (bn BlockNumber) Inc() BlockNumber {
 	return bn + 1
 }
 
+// ParseBlockNumber parses 0x prefixed hex block number
+func ParseBlockNumber(value string) (BlockNumber, error) {
+	quantity, err := ParseQuantity(value)
+	if err != nil {
+		return InvalidBlockNumber, err
+	}
+	if !quantity.Big().IsInt64() {
+		return InvalidBlockNumber, fmt.Errorf("%w: block number %s overflows", ErrInvalidQuantity, value)
+	}
+	return BlockNumber(quantity.Int64()), nil
+}
+
 // ---- builder methods ----
 
 type BlockNumberBuilder struct {
@@ -43,18 +52,9 @@ func (b *BlockNumberBuilder) FromInt64(i int64) *BlockNumberBuilder {
 	return b
 }
 
-// FromHexString parses a block number from a hex string.
-func (b *BlockNumberBuilder) FromHexString(hexStr string) *BlockNumberBuilder {
-	hexStr = strings.TrimPrefix(hexStr, "0x")
-
-	// Using ParseInt to convert the hex string to int64
-	decimal, err := strconv.ParseInt(hexStr, 16, 64)
-	if err != nil {
-		log.Println("Error parsing hex string to int64")
-	}
-	blockNumber := BlockNumber(decimal)
-	b.value = &blockNumber
-	return b
+// FromQuantity sets block number of the quantity
+func (b *BlockNumberBuilder) FromQuantity(quantity Quantity) *BlockNumberBuilder {
+	return b.FromInt64(quantity.Int64())
 }
 
 func (b *BlockNumberBuilder) Value() BlockNumber {
@@ -67,3 +67,12 @@ func (b *BlockNumberBuilder) Value() BlockNumber {
 func (b *BlockNumberBuilder) Pointer() *BlockNumber {
 	return b.value
 }
+
+// BlockTag is a named block of the chain, used instead of block number in jsonrpc requests
+type BlockTag string
+
+const (
+	LatestBlockTag    = BlockTag("latest")
+	SafeBlockTag      = BlockTag("safe")
+	FinalizedBlockTag = BlockTag("finalized")
+)
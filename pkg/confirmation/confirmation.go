@@ -0,0 +1,59 @@
+package confirmation
+
+import (
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// Status is a confirmation status of a transaction
+type Status string
+
+const (
+	// StatusPending transaction is included in a block which is not buried deep enough
+	StatusPending = Status("pending_confirmation")
+	// StatusConfirmed transaction is buried under configured number of blocks
+	StatusConfirmed = Status("confirmed")
+	// StatusFinalized transaction is included in a block that is finalized by the chain
+	StatusFinalized = Status("finalized")
+)
+
+// DefaultDepth is the default number of blocks after which transaction is considered confirmed
+const DefaultDepth = 12
+
+// Config is a configuration of transaction confirmation
+type Config struct {
+	// Depth is the number of blocks, including the transaction block,
+	// needed for a transaction to be confirmed
+	Depth int64
+	// FinalityTag is the block tag used to track finalized blocks, safe or finalized.
+	// If it is empty, finality is not tracked and transactions are at most confirmed.
+	FinalityTag blockchain.BlockTag
+}
+
+// NewConfig creates a new confirmation config
+func NewConfig(depth int64, finalityTag blockchain.BlockTag) Config {
+	return Config{
+		Depth:       depth,
+		FinalityTag: finalityTag,
+	}
+}
+
+// TracksFinality returns true if finalized blocks should be tracked
+func (c Config) TracksFinality() bool {
+	return c.FinalityTag != ""
+}
+
+// Status returns confirmation status of a transaction included in given block,
+// based on the last processed block number and the last known finalized block number.
+func (c Config) Status(
+	blockNumber blockchain.BlockNumber,
+	currentBlockNumber blockchain.BlockNumber,
+	finalizedBlockNumber blockchain.BlockNumber,
+) Status {
+	if c.TracksFinality() && finalizedBlockNumber > blockchain.EarliestBlockNumber && blockNumber <= finalizedBlockNumber {
+		return StatusFinalized
+	}
+	if currentBlockNumber-blockNumber+1 >= blockchain.BlockNumber(c.Depth) {
+		return StatusConfirmed
+	}
+	return StatusPending
+}
@@ -0,0 +1,154 @@
+package notification
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	"go.etcd.io/bbolt"
+)
+
+var (
+	webhooksBucket   = []byte("webhooks")
+	deliveriesBucket = []byte("webhook_deliveries")
+)
+
+var _ Storage = (*boltStorage)(nil)
+
+// boltStorage persists webhooks keyed by id, and deliveries in a nested bucket per webhook
+// keyed by creation time and delivery id, so the delivery log is ordered by creation time
+type boltStorage struct {
+	db *bbolt.DB
+}
+
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(webhooksBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(deliveriesBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStorage{
+		db: db,
+	}, nil
+}
+
+func (s *boltStorage) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	var webhook *Webhook
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket(webhooksBucket).Get([]byte(id))
+		if value == nil {
+			return ErrWebhookNotFound
+		}
+		webhook = &Webhook{}
+		return json.Unmarshal(value, webhook)
+	})
+	return webhook, err
+}
+
+func (s *boltStorage) GetWebhooks(ctx context.Context) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(webhooksBucket).ForEach(func(_, value []byte) error {
+			var webhook Webhook
+			if err := json.Unmarshal(value, &webhook); err != nil {
+				return err
+			}
+			webhooks = append(webhooks, &webhook)
+			return nil
+		})
+	})
+	return webhooks, err
+}
+
+func (s *boltStorage) GetDeliveries(ctx context.Context, webhookID string, status DeliveryStatus, limit int) ([]*Delivery, error) {
+	deliveries := make([]*Delivery, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(deliveriesBucket).Bucket([]byte(webhookID))
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for key, value := cursor.Last(); key != nil; key, value = cursor.Prev() {
+			var delivery Delivery
+			if err := json.Unmarshal(value, &delivery); err != nil {
+				return err
+			}
+			if status != "" && delivery.Status != status {
+				continue
+			}
+			deliveries = append(deliveries, &delivery)
+			if limit > 0 && len(deliveries) >= limit {
+				return nil
+			}
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+func (s *boltStorage) GetPendingDeliveries(ctx context.Context) ([]*Delivery, error) {
+	deliveries := make([]*Delivery, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(deliveriesBucket).ForEachBucket(func(webhookID []byte) error {
+			return tx.Bucket(deliveriesBucket).Bucket(webhookID).ForEach(func(_, value []byte) error {
+				var delivery Delivery
+				if err := json.Unmarshal(value, &delivery); err != nil {
+					return err
+				}
+				if delivery.Status == DeliveryPending {
+					deliveries = append(deliveries, &delivery)
+				}
+				return nil
+			})
+		})
+	})
+	return deliveries, err
+}
+
+func (s *boltStorage) SaveWebhook(ctx context.Context, webhook *Webhook) error {
+	value, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(webhooksBucket).Put([]byte(webhook.ID), value)
+	})
+}
+
+func (s *boltStorage) DeleteWebhook(ctx context.Context, id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		deliveries := tx.Bucket(deliveriesBucket)
+		if deliveries.Bucket([]byte(id)) != nil {
+			if err := deliveries.DeleteBucket([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(webhooksBucket).Delete([]byte(id))
+	})
+}
+
+func (s *boltStorage) SaveDelivery(ctx context.Context, delivery *Delivery) error {
+	value, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.Bucket(deliveriesBucket).CreateBucketIfNotExists([]byte(delivery.WebhookID))
+		if err != nil {
+			return err
+		}
+		return bucket.Put(deliveryKey(delivery), value)
+	})
+}
+
+// deliveryKey is big endian creation time in unix nanoseconds followed by delivery id
+func deliveryKey(delivery *Delivery) []byte {
+	key := make([]byte, 8, 8+len(delivery.ID))
+	binary.BigEndian.PutUint64(key, uint64(delivery.CreatedAt.UnixNano()))
+	return append(key, delivery.ID...)
+}
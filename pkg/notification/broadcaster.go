@@ -0,0 +1,266 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+const (
+	// listenerBufferSize is the number of events buffered per listener before it is dropped
+	listenerBufferSize = 256
+	// headHistorySize is the number of heights below the last head whose hashes are kept to detect reorgs
+	headHistorySize = 128
+)
+
+// Head is a new head of the chain processed by the parser
+type Head struct {
+	Number     int64           `json:"number"`
+	Hash       blockchain.Hash `json:"hash"`
+	ParentHash blockchain.Hash `json:"parentHash"`
+	Timestamp  int64           `json:"timestamp"`
+}
+
+// Broadcaster pushes matched transactions and new heads to in-process listeners, e.g. open streams of API clients.
+// Broadcaster never blocks the transaction filter, listener which does not keep up is dropped.
+type Broadcaster interface {
+	Notifier
+	// Listen returns listener of transactions of the addresses stored from now on
+	Listen(addresses []string) *Listener
+	// ListenHeads returns listener of new heads
+	ListenHeads() *HeadListener
+	// WatchHeads pushes processed blocks which are new heads of the chain to head listeners,
+	// block already pushed is skipped, block which is not higher than the last head is pushed when it is
+	// a different block, e.g. new head after reorg to the lower or the same height
+	WatchHeads(ctx context.Context, processedBlocks <-chan *blockchain.Block)
+}
+
+var _ Broadcaster = (*broadcaster)(nil)
+
+type broadcaster struct {
+	// listeners by lowercased address
+	listeners     map[string]map[*Listener]struct{}
+	headListeners map[*HeadListener]struct{}
+	lastHead      *Head
+	// headHashes are hashes of the latest processed blocks by number
+	headHashes map[int64]blockchain.Hash
+	mutex      sync.Mutex
+}
+
+func NewBroadcaster() Broadcaster {
+	return &broadcaster{
+		listeners:     make(map[string]map[*Listener]struct{}),
+		headListeners: make(map[*HeadListener]struct{}),
+		headHashes:    make(map[int64]blockchain.Hash),
+	}
+}
+
+// Listener receives transactions of its addresses
+type Listener struct {
+	broadcaster  *broadcaster
+	addresses    map[string]struct{}
+	transactions chan *transaction.AddressTransaction
+	closed       bool
+	// dropped is true if listener was closed because it did not keep up with transactions
+	dropped bool
+}
+
+// Transactions returns channel of transactions, it is closed when listener is closed or dropped
+func (l *Listener) Transactions() <-chan *transaction.AddressTransaction {
+	return l.transactions
+}
+
+// Add starts listening for transactions of the addresses
+func (l *Listener) Add(addresses []string) {
+	l.broadcaster.mutex.Lock()
+	defer l.broadcaster.mutex.Unlock()
+	if l.closed {
+		return
+	}
+	for _, address := range addresses {
+		address = strings.ToLower(address)
+		if _, ok := l.broadcaster.listeners[address]; !ok {
+			l.broadcaster.listeners[address] = make(map[*Listener]struct{})
+		}
+		l.broadcaster.listeners[address][l] = struct{}{}
+		l.addresses[address] = struct{}{}
+	}
+}
+
+// Remove stops listening for transactions of the addresses
+func (l *Listener) Remove(addresses []string) {
+	l.broadcaster.mutex.Lock()
+	defer l.broadcaster.mutex.Unlock()
+	for _, address := range addresses {
+		address = strings.ToLower(address)
+		l.broadcaster.removeAddress(l, address)
+		delete(l.addresses, address)
+	}
+}
+
+// Dropped returns true if listener was dropped because it did not keep up with transactions
+func (l *Listener) Dropped() bool {
+	l.broadcaster.mutex.Lock()
+	defer l.broadcaster.mutex.Unlock()
+	return l.dropped
+}
+
+// Close stops listening
+func (l *Listener) Close() {
+	l.broadcaster.mutex.Lock()
+	defer l.broadcaster.mutex.Unlock()
+	l.broadcaster.remove(l)
+}
+
+// HeadListener receives new heads
+type HeadListener struct {
+	broadcaster *broadcaster
+	heads       chan *Head
+	closed      bool
+	// dropped is true if listener was closed because it did not keep up with heads
+	dropped bool
+}
+
+// Heads returns channel of new heads, it is closed when listener is closed or dropped
+func (l *HeadListener) Heads() <-chan *Head {
+	return l.heads
+}
+
+// Dropped returns true if listener was dropped because it did not keep up with heads
+func (l *HeadListener) Dropped() bool {
+	l.broadcaster.mutex.Lock()
+	defer l.broadcaster.mutex.Unlock()
+	return l.dropped
+}
+
+// Close stops listening
+func (l *HeadListener) Close() {
+	l.broadcaster.mutex.Lock()
+	defer l.broadcaster.mutex.Unlock()
+	l.broadcaster.removeHeadListener(l)
+}
+
+func (b *broadcaster) Listen(addresses []string) *Listener {
+	listener := &Listener{
+		broadcaster:  b,
+		addresses:    make(map[string]struct{}),
+		transactions: make(chan *transaction.AddressTransaction, listenerBufferSize),
+	}
+	listener.Add(addresses)
+	return listener
+}
+
+func (b *broadcaster) ListenHeads() *HeadListener {
+	listener := &HeadListener{
+		broadcaster: b,
+		heads:       make(chan *Head, listenerBufferSize),
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.headListeners[listener] = struct{}{}
+	return listener
+}
+
+func (b *broadcaster) Notify(ctx context.Context, transactions []*transaction.AddressTransaction) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, tx := range transactions {
+		for listener := range b.listeners[tx.ID.String()] {
+			select {
+			case listener.transactions <- tx:
+			default:
+				listener.dropped = true
+				b.remove(listener)
+			}
+		}
+	}
+}
+
+func (b *broadcaster) WatchHeads(ctx context.Context, processedBlocks <-chan *blockchain.Block) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case block := <-processedBlocks:
+			if block == nil {
+				continue
+			}
+			b.notifyHead(newHead(block))
+		}
+	}
+}
+
+// notifyHead pushes head to head listeners if it is the new head of the chain.
+// Heads are deduplicated by hash, block which is not higher than the last head is pushed
+// only if a different block was processed at its height, so it replaces it after reorg.
+func (b *broadcaster) notifyHead(head *Head) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	hash, processed := b.headHashes[head.Number]
+	if processed && hash == head.Hash {
+		return
+	}
+	b.headHashes[head.Number] = head.Hash
+	// blocks are processed in parallel, lower block processed first at its height is only late, not reorged
+	if b.lastHead != nil && head.Number <= b.lastHead.Number && !processed {
+		return
+	}
+	if b.lastHead == nil || head.Number > b.lastHead.Number {
+		for number := range b.headHashes {
+			if number < head.Number-headHistorySize {
+				delete(b.headHashes, number)
+			}
+		}
+	}
+	b.lastHead = head
+	for listener := range b.headListeners {
+		select {
+		case listener.heads <- head:
+		default:
+			listener.dropped = true
+			b.removeHeadListener(listener)
+		}
+	}
+}
+
+// remove removes listener and closes its channel, it has to be called with locked mutex
+func (b *broadcaster) remove(listener *Listener) {
+	if listener.closed {
+		return
+	}
+	listener.closed = true
+	for address := range listener.addresses {
+		b.removeAddress(listener, address)
+	}
+	close(listener.transactions)
+}
+
+// removeAddress removes listener of the address, it has to be called with locked mutex
+func (b *broadcaster) removeAddress(listener *Listener, address string) {
+	delete(b.listeners[address], listener)
+	if len(b.listeners[address]) == 0 {
+		delete(b.listeners, address)
+	}
+}
+
+// removeHeadListener removes head listener and closes its channel, it has to be called with locked mutex
+func (b *broadcaster) removeHeadListener(listener *HeadListener) {
+	if listener.closed {
+		return
+	}
+	listener.closed = true
+	delete(b.headListeners, listener)
+	close(listener.heads)
+}
+
+func newHead(block *blockchain.Block) *Head {
+	return &Head{
+		Number:     block.Number.Int64(),
+		Hash:       block.Hash,
+		ParentHash: block.ParentHash,
+		Timestamp:  block.Timestamp.Int64(),
+	}
+}
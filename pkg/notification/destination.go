@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// errPrivateDestination is returned when webhook host is not reachable from the internet,
+// webhooks must not reach services of the internal network or cloud metadata endpoints
+var errPrivateDestination = errors.New("webhook host must be a public address")
+
+// sharedAddressSpace is the carrier grade NAT range, it is used for internal services by some cloud providers
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP returns true if the address is not loopback, private, link local, multicast or unspecified address
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// checkPublicHost returns error if the host is not public or any of its resolved addresses is not public
+func checkPublicHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicIP(ip) {
+			return fmt.Errorf("%w: %s", errPrivateDestination, host)
+		}
+		return nil
+	}
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("webhook host %s can not be resolved: %w", host, err)
+	}
+	for _, address := range addresses {
+		if !publicIP(address.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", errPrivateDestination, host, address.IP)
+		}
+	}
+	return nil
+}
+
+// newWebhookClient returns client of webhook deliveries. Unless private hosts are allowed, connection is refused
+// when the address it dials is not public, so host resolved to other address after registration or redirect
+// to internal service are refused too. Proxy is not used, dialed address is then the webhook address.
+func newWebhookClient(allowPrivateHosts bool) *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if !allowPrivateHosts {
+		dialer.Control = func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", errPrivateDestination, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: requestTimeout, Transport: transport}
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+)
+
+// Notifier is notified about matched transactions after they are stored
+type Notifier interface {
+	Notify(ctx context.Context, transactions []*transaction.AddressTransaction)
+}
+
+var _ Notifier = (Notifiers)(nil)
+
+// Notifiers notifies all notifiers in order
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(ctx context.Context, transactions []*transaction.AddressTransaction) {
+	for _, notifier := range n {
+		notifier.Notify(ctx, transactions)
+	}
+}
+
+// Matcher tests if transaction of the address is matched by subscription of the tenant
+type Matcher interface {
+	MatchTenant(ctx context.Context, tenantID string, address blockchain.Address, tx *blockchain.Transaction) (bool, error)
+}
+
+// Webhook is an endpoint notified about matched transactions of the address,
+// it is notified only about transactions matched by subscription of the tenant owning it
+type Webhook struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Address  string `json:"address"`
+	URL      string `json:"url"`
+	// Secret is the key of HMAC-SHA256 signature of the payload
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Redacted returns copy of the webhook without its secret
+func (w *Webhook) Redacted() *Webhook {
+	copied := *w
+	copied.Secret = ""
+	return &copied
+}
+
+// DeliveryStatus is a status of webhook delivery
+type DeliveryStatus string
+
+const (
+	// DeliveryPending delivery is queued or waiting for retry
+	DeliveryPending = DeliveryStatus("pending")
+	// DeliveryDelivered delivery is acknowledged by the endpoint with 2xx status
+	DeliveryDelivered = DeliveryStatus("delivered")
+	// DeliveryDead delivery failed all attempts and is in the dead letter queue of the endpoint
+	DeliveryDead = DeliveryStatus("dead")
+)
+
+// Delivery is a delivery of a single matched transaction to a webhook
+type Delivery struct {
+	ID              string          `json:"id"`
+	WebhookID       string          `json:"webhook_id"`
+	Address         string          `json:"address"`
+	TransactionHash string          `json:"transaction_hash"`
+	Payload         json.RawMessage `json:"payload"`
+	Status          DeliveryStatus  `json:"status"`
+	Attempts        int             `json:"attempts"`
+	LastError       string          `json:"last_error,omitempty"`
+	ResponseCode    int             `json:"response_code,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// Payload is the body posted to the webhook
+type Payload struct {
+	DeliveryID  string                          `json:"delivery_id"`
+	WebhookID   string                          `json:"webhook_id"`
+	Address     string                          `json:"address"`
+	Transaction *transaction.AddressTransaction `json:"transaction"`
+}
+
+// newID returns random hex id
+func newID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,173 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+)
+
+const deliveryColumns = `id, webhook_id, address, transaction_hash, payload, status, attempts, last_error, response_code, created_at, updated_at`
+
+var _ Storage = (*sqlStorage)(nil)
+
+// sqlStorage persists webhooks and their delivery log in sql database, times are stored as unix nanoseconds
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+func (s *sqlStorage) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	row := s.db.QueryRowContext(ctx, s.db.Rebind(`SELECT id, tenant_id, address, url, secret, created_at FROM webhooks WHERE id = ?`), id)
+	webhook, err := scanWebhook(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+func (s *sqlStorage) GetWebhooks(ctx context.Context) ([]*Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, tenant_id, address, url, secret, created_at FROM webhooks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]*Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (s *sqlStorage) GetDeliveries(ctx context.Context, webhookID string, status DeliveryStatus, limit int) ([]*Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = ?`
+	args := []any{webhookID}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, string(status))
+	}
+	query += ` ORDER BY created_at DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	return s.queryDeliveries(ctx, query, args...)
+}
+
+func (s *sqlStorage) GetPendingDeliveries(ctx context.Context) ([]*Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE status = ? ORDER BY created_at`
+	return s.queryDeliveries(ctx, query, string(DeliveryPending))
+}
+
+func (s *sqlStorage) SaveWebhook(ctx context.Context, webhook *Webhook) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`
+		INSERT INTO webhooks (id, tenant_id, address, url, secret, created_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			tenant_id = excluded.tenant_id, address = excluded.address, url = excluded.url, secret = excluded.secret`),
+		webhook.ID, webhook.TenantID, webhook.Address, webhook.URL, webhook.Secret, webhook.CreatedAt.UnixNano(),
+	)
+	return err
+}
+
+func (s *sqlStorage) DeleteWebhook(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, s.db.Rebind(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`), id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, s.db.Rebind(`DELETE FROM webhooks WHERE id = ?`), id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) SaveDelivery(ctx context.Context, delivery *Delivery) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`
+		INSERT INTO webhook_deliveries (`+deliveryColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			status = excluded.status,
+			attempts = excluded.attempts,
+			last_error = excluded.last_error,
+			response_code = excluded.response_code,
+			updated_at = excluded.updated_at`),
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.Address,
+		delivery.TransactionHash,
+		string(delivery.Payload),
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.LastError,
+		delivery.ResponseCode,
+		delivery.CreatedAt.UnixNano(),
+		delivery.UpdatedAt.UnixNano(),
+	)
+	return err
+}
+
+func (s *sqlStorage) queryDeliveries(ctx context.Context, query string, args ...any) ([]*Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*Delivery, 0)
+	for rows.Next() {
+		var delivery Delivery
+		var payload, status string
+		var createdAt, updatedAt int64
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Address,
+			&delivery.TransactionHash,
+			&payload,
+			&status,
+			&delivery.Attempts,
+			&delivery.LastError,
+			&delivery.ResponseCode,
+			&createdAt,
+			&updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = []byte(payload)
+		delivery.Status = DeliveryStatus(status)
+		delivery.CreatedAt = time.Unix(0, createdAt).UTC()
+		delivery.UpdatedAt = time.Unix(0, updatedAt).UTC()
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row scanner) (*Webhook, error) {
+	var webhook Webhook
+	var createdAt int64
+	if err := row.Scan(&webhook.ID, &webhook.TenantID, &webhook.Address, &webhook.URL, &webhook.Secret, &createdAt); err != nil {
+		return nil, err
+	}
+	webhook.CreatedAt = time.Unix(0, createdAt).UTC()
+	return &webhook, nil
+}
@@ -0,0 +1,139 @@
+package notification
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// ReadOnlyStorage is responsible for reading webhooks and deliveries
+type ReadOnlyStorage interface {
+	GetWebhook(ctx context.Context, id string) (*Webhook, error)
+	GetWebhooks(ctx context.Context) ([]*Webhook, error)
+	// GetDeliveries returns deliveries of the webhook from the newest one, status is optional
+	GetDeliveries(ctx context.Context, webhookID string, status DeliveryStatus, limit int) ([]*Delivery, error)
+	// GetPendingDeliveries returns deliveries of all webhooks which are not delivered yet
+	GetPendingDeliveries(ctx context.Context) ([]*Delivery, error)
+}
+
+// WriteStorage is responsible for writing webhooks and deliveries
+type WriteStorage interface {
+	SaveWebhook(ctx context.Context, webhook *Webhook) error
+	// DeleteWebhook deletes webhook with all its deliveries
+	DeleteWebhook(ctx context.Context, id string) error
+	// SaveDelivery inserts or updates delivery
+	SaveDelivery(ctx context.Context, delivery *Delivery) error
+}
+
+// Storage is responsible for reading and writing webhooks and deliveries
+type Storage interface {
+	ReadOnlyStorage
+	WriteStorage
+}
+
+var _ Storage = (*inMemoryStorage)(nil)
+
+type inMemoryStorage struct {
+	webhooks   map[string]*Webhook
+	deliveries map[string]map[string]*Delivery
+	mutex      sync.RWMutex
+}
+
+func NewStorage() Storage {
+	return &inMemoryStorage{
+		webhooks:   make(map[string]*Webhook),
+		deliveries: make(map[string]map[string]*Delivery),
+	}
+}
+
+func (s *inMemoryStorage) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	webhook, ok := s.webhooks[id]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	// return a copy, so callers can not change the stored webhook
+	copied := *webhook
+	return &copied, nil
+}
+
+func (s *inMemoryStorage) GetWebhooks(ctx context.Context) ([]*Webhook, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	webhooks := make([]*Webhook, 0, len(s.webhooks))
+	for _, webhook := range s.webhooks {
+		copied := *webhook
+		webhooks = append(webhooks, &copied)
+	}
+	return webhooks, nil
+}
+
+func (s *inMemoryStorage) GetDeliveries(ctx context.Context, webhookID string, status DeliveryStatus, limit int) ([]*Delivery, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	deliveries := make([]*Delivery, 0)
+	for _, delivery := range s.deliveries[webhookID] {
+		if status == "" || delivery.Status == status {
+			copied := *delivery
+			deliveries = append(deliveries, &copied)
+		}
+	}
+	sortNewestFirst(deliveries)
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (s *inMemoryStorage) GetPendingDeliveries(ctx context.Context) ([]*Delivery, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	deliveries := make([]*Delivery, 0)
+	for _, webhookDeliveries := range s.deliveries {
+		for _, delivery := range webhookDeliveries {
+			if delivery.Status == DeliveryPending {
+				copied := *delivery
+				deliveries = append(deliveries, &copied)
+			}
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+func (s *inMemoryStorage) SaveWebhook(ctx context.Context, webhook *Webhook) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	copied := *webhook
+	s.webhooks[webhook.ID] = &copied
+	return nil
+}
+
+func (s *inMemoryStorage) DeleteWebhook(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.webhooks, id)
+	delete(s.deliveries, id)
+	return nil
+}
+
+func (s *inMemoryStorage) SaveDelivery(ctx context.Context, delivery *Delivery) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.deliveries[delivery.WebhookID]; !ok {
+		s.deliveries[delivery.WebhookID] = make(map[string]*Delivery)
+	}
+	// store a copy, delivery is updated by the dispatcher while it is retried
+	copied := *delivery
+	s.deliveries[delivery.WebhookID][delivery.ID] = &copied
+	return nil
+}
+
+func sortNewestFirst(deliveries []*Delivery) {
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+}
@@ -0,0 +1,466 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+const (
+	// SignatureHeader contains hex encoded HMAC-SHA256 of "<timestamp>.<body>" signed with the webhook secret
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader contains unix timestamp of the delivery attempt
+	TimestampHeader = "X-Webhook-Timestamp"
+	// DeliveryHeader contains id of the delivery, it is the same for all attempts
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	endpointQueueSize = 1000
+	maxAttempts       = 8
+	initialBackoff    = time.Second
+	maxBackoff        = 5 * time.Minute
+	requestTimeout    = 10 * time.Second
+	maxErrorLength    = 512
+)
+
+// Dispatcher delivers matched transactions to registered webhooks.
+// Every webhook has its own queue, so slow or failing endpoints do not delay other endpoints.
+// Deliveries are retried with exponential backoff and moved to the dead letter queue of the
+// endpoint after the last attempt fails, failed delivery waits for its retry outside of the queue,
+// so it does not delay next deliveries of the endpoint.
+type Dispatcher interface {
+	Notifier
+	// Start queues deliveries which were pending when the service stopped
+	Start(ctx context.Context)
+	// RegisterWebhook registers webhook of the tenant to the address, secret is generated if it is empty
+	RegisterWebhook(ctx context.Context, tenantID string, address string, webhookURL string, secret string) (*Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	// GetDeliveries returns delivery log of the webhook from the newest delivery, status is optional
+	GetDeliveries(ctx context.Context, webhookID string, status DeliveryStatus, limit int) ([]*Delivery, error)
+	// RedeliverDeadLetters queues dead letters of the webhook again and returns their number
+	RedeliverDeadLetters(ctx context.Context, webhookID string) (int, error)
+	// Close closes the dispatcher
+	Close(ctx context.Context)
+}
+
+var _ Dispatcher = (*dispatcher)(nil)
+
+type dispatcher struct {
+	storage Storage
+	matcher Matcher
+	client  *http.Client
+	// allowPrivateHosts allows webhooks of loopback and private network hosts, e.g. for local development
+	allowPrivateHosts bool
+
+	// webhooks by lowercased address
+	webhooks map[string][]*Webhook
+	queues   map[string]*endpointQueue
+	mutex    sync.RWMutex
+	ctx      context.Context
+	wg       sync.WaitGroup
+}
+
+// endpointQueue is a queue of deliveries of a single webhook
+type endpointQueue struct {
+	webhook    *Webhook
+	deliveries chan *Delivery
+	cancel     context.CancelFunc
+}
+
+// NewDispatcher creates dispatcher with webhooks loaded from the storage,
+// matcher filters transactions delivered to webhooks of every tenant.
+// Webhooks of hosts which are not public are rejected and never dialed unless private hosts are allowed.
+func NewDispatcher(ctx context.Context, storage Storage, matcher Matcher, allowPrivateHosts bool) (Dispatcher, error) {
+	d := &dispatcher{
+		storage:           storage,
+		matcher:           matcher,
+		client:            newWebhookClient(allowPrivateHosts),
+		allowPrivateHosts: allowPrivateHosts,
+		webhooks:          make(map[string][]*Webhook),
+		queues:            make(map[string]*endpointQueue),
+		ctx:               ctx,
+	}
+	webhooks, err := storage.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		d.addWebhook(webhook)
+	}
+	return d, nil
+}
+
+func (d *dispatcher) Start(ctx context.Context) {
+	deliveries, err := d.storage.GetPendingDeliveries(ctx)
+	if err != nil {
+		log.Println("Error loading pending webhook deliveries:", err)
+		return
+	}
+	for _, delivery := range deliveries {
+		d.enqueue(ctx, delivery)
+	}
+}
+
+func (d *dispatcher) Notify(ctx context.Context, transactions []*transaction.AddressTransaction) {
+	for _, tx := range transactions {
+		d.mutex.RLock()
+		webhooks := d.webhooks[tx.ID.String()]
+		d.mutex.RUnlock()
+
+		for _, webhook := range webhooks {
+			// webhooks are registered by address of the transaction id
+			matched, err := d.matcher.MatchTenant(ctx, webhook.TenantID, tx.ID.Address(), tx.Transaction)
+			if err != nil || !matched {
+				continue
+			}
+			delivery, err := newDelivery(webhook, tx)
+			if err != nil {
+				log.Printf("Error creating delivery of transaction %s to webhook %s: %s.", tx.Transaction.Hash, webhook.ID, err)
+				continue
+			}
+			if err := d.storage.SaveDelivery(ctx, delivery); err != nil {
+				log.Printf("Error saving delivery of transaction %s to webhook %s: %s.", tx.Transaction.Hash, webhook.ID, err)
+				continue
+			}
+			d.enqueue(ctx, delivery)
+		}
+	}
+}
+
+func (d *dispatcher) RegisterWebhook(ctx context.Context, tenantID string, address string, webhookURL string, secret string) (*Webhook, error) {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWebhook, err)
+	}
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("%w: url must be absolute http or https url", ErrInvalidWebhook)
+	}
+	if !d.allowPrivateHosts {
+		if err := checkPublicHost(ctx, parsedURL.Hostname()); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidWebhook, err)
+		}
+	}
+	if secret == "" {
+		secret = newID()
+	}
+	webhook := &Webhook{
+		ID:        newID(),
+		TenantID:  tenantID,
+		Address:   parsedAddress.String(),
+		URL:       webhookURL,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := d.storage.SaveWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	d.addWebhook(webhook)
+	return webhook, nil
+}
+
+func (d *dispatcher) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	return d.storage.GetWebhook(ctx, id)
+}
+
+func (d *dispatcher) DeleteWebhook(ctx context.Context, id string) error {
+	webhook, err := d.storage.GetWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := d.storage.DeleteWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	webhooks := d.webhooks[webhook.Address]
+	remaining := make([]*Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		if w.ID != id {
+			remaining = append(remaining, w)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(d.webhooks, webhook.Address)
+	} else {
+		d.webhooks[webhook.Address] = remaining
+	}
+	if queue, ok := d.queues[id]; ok {
+		queue.cancel()
+		delete(d.queues, id)
+	}
+	return nil
+}
+
+func (d *dispatcher) GetDeliveries(ctx context.Context, webhookID string, status DeliveryStatus, limit int) ([]*Delivery, error) {
+	if _, err := d.storage.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	return d.storage.GetDeliveries(ctx, webhookID, status, limit)
+}
+
+func (d *dispatcher) RedeliverDeadLetters(ctx context.Context, webhookID string) (int, error) {
+	deadLetters, err := d.GetDeliveries(ctx, webhookID, DeliveryDead, 0)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range deadLetters {
+		delivery.Status = DeliveryPending
+		delivery.Attempts = 0
+		delivery.UpdatedAt = time.Now().UTC()
+		if err := d.storage.SaveDelivery(ctx, delivery); err != nil {
+			return 0, err
+		}
+		d.enqueue(ctx, delivery)
+	}
+	return len(deadLetters), nil
+}
+
+func (d *dispatcher) Close(ctx context.Context) {
+	d.mutex.Lock()
+	for id, queue := range d.queues {
+		queue.cancel()
+		delete(d.queues, id)
+	}
+	d.mutex.Unlock()
+	d.wg.Wait()
+}
+
+func (d *dispatcher) addWebhook(webhook *Webhook) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.webhooks[webhook.Address] = append(d.webhooks[webhook.Address], webhook)
+}
+
+// registered returns true if webhook of the delivery is registered
+func (d *dispatcher) registered(delivery *Delivery) bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	for _, webhook := range d.webhooks[delivery.Address] {
+		if webhook.ID == delivery.WebhookID {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue queues delivery to the queue of its webhook, the queue worker is started on the first delivery.
+// Webhook of a new queue is read from the storage without holding the lock, so storage does not block other queues.
+func (d *dispatcher) enqueue(ctx context.Context, delivery *Delivery) {
+	d.mutex.RLock()
+	queue, ok := d.queues[delivery.WebhookID]
+	d.mutex.RUnlock()
+	if !ok {
+		webhook, err := d.storage.GetWebhook(ctx, delivery.WebhookID)
+		if err != nil {
+			log.Printf("Error getting webhook %s of delivery %s: %s.", delivery.WebhookID, delivery.ID, err)
+			return
+		}
+		queue = d.startQueue(webhook)
+	}
+
+	select {
+	case queue.deliveries <- delivery:
+	default:
+		// endpoint can not keep up with deliveries
+		d.deadLetter(ctx, delivery, "endpoint queue is full")
+	}
+}
+
+// startQueue returns queue of the webhook, queue and its worker are created if the queue was not started meanwhile
+func (d *dispatcher) startQueue(webhook *Webhook) *endpointQueue {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if queue, ok := d.queues[webhook.ID]; ok {
+		return queue
+	}
+	queueCtx, cancel := context.WithCancel(d.ctx)
+	queue := &endpointQueue{
+		webhook:    webhook,
+		deliveries: make(chan *Delivery, endpointQueueSize),
+		cancel:     cancel,
+	}
+	d.queues[webhook.ID] = queue
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.work(queueCtx, queue)
+	}()
+	return queue
+}
+
+// work delivers deliveries of the queue one by one
+func (d *dispatcher) work(ctx context.Context, queue *endpointQueue) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case delivery := <-queue.deliveries:
+			d.deliver(ctx, queue, delivery)
+		}
+	}
+}
+
+// deliver posts delivery to the webhook, failed delivery is queued again after backoff
+// until it succeeds or all attempts fail
+func (d *dispatcher) deliver(ctx context.Context, queue *endpointQueue, delivery *Delivery) {
+	delivery.Attempts++
+	responseCode, err := d.post(ctx, queue.webhook, delivery)
+	delivery.ResponseCode = responseCode
+	delivery.UpdatedAt = time.Now().UTC()
+	if err == nil {
+		delivery.Status = DeliveryDelivered
+		delivery.LastError = ""
+		d.save(delivery)
+		return
+	}
+	delivery.LastError = truncate(err.Error())
+	if ctx.Err() != nil {
+		// dispatcher is closing or webhook is deleted, delivery stays pending and is queued again on start
+		return
+	}
+	if !retryable(responseCode) || delivery.Attempts >= maxAttempts {
+		d.deadLetter(ctx, delivery, delivery.LastError)
+		return
+	}
+	d.save(delivery)
+	backoff := retryBackoff(delivery.Attempts)
+	log.Printf("Delivery %s to webhook %s failed: %s. Attempt %d/%d, retrying in %v.", delivery.ID, queue.webhook.ID, err, delivery.Attempts, maxAttempts, backoff)
+	d.retryLater(ctx, queue, delivery, backoff)
+}
+
+// retryLater queues the delivery again after the backoff, meanwhile the queue delivers next deliveries.
+// Delivery stays pending when the queue is stopped before, it is queued again on start.
+func (d *dispatcher) retryLater(ctx context.Context, queue *endpointQueue, delivery *Delivery, backoff time.Duration) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		select {
+		case queue.deliveries <- delivery:
+		default:
+			// endpoint can not keep up with deliveries
+			d.deadLetter(ctx, delivery, "endpoint queue is full")
+		}
+	}()
+}
+
+// retryBackoff returns exponential backoff after the failed attempt, it is bounded by maxBackoff
+func retryBackoff(attempts int) time.Duration {
+	backoff := initialBackoff
+	for attempt := 1; attempt < attempts && backoff < maxBackoff; attempt++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// post sends signed delivery payload to the webhook
+func (d *dispatcher) post(ctx context.Context, webhook *Webhook, delivery *Delivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(DeliveryHeader, delivery.ID)
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("unexpected response status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+func (d *dispatcher) deadLetter(ctx context.Context, delivery *Delivery, reason string) {
+	log.Printf("Delivery %s to webhook %s moved to dead letter queue: %s.", delivery.ID, delivery.WebhookID, reason)
+	delivery.Status = DeliveryDead
+	delivery.LastError = truncate(reason)
+	delivery.UpdatedAt = time.Now().UTC()
+	d.save(delivery)
+}
+
+// save stores delivery state, it is stored even if the dispatcher is closing,
+// but not if its webhook was deleted meanwhile
+func (d *dispatcher) save(delivery *Delivery) {
+	if !d.registered(delivery) {
+		return
+	}
+	if err := d.storage.SaveDelivery(context.Background(), delivery); err != nil {
+		log.Printf("Error saving delivery %s: %s.", delivery.ID, err)
+	}
+}
+
+// Sign returns hex encoded HMAC-SHA256 signature of the payload sent at the timestamp
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDelivery(webhook *Webhook, tx *transaction.AddressTransaction) (*Delivery, error) {
+	now := time.Now().UTC()
+	delivery := &Delivery{
+		ID:              newID(),
+		WebhookID:       webhook.ID,
+		Address:         webhook.Address,
+		TransactionHash: tx.Transaction.Hash.String(),
+		Status:          DeliveryPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	payload, err := json.Marshal(&Payload{
+		DeliveryID:  delivery.ID,
+		WebhookID:   webhook.ID,
+		Address:     webhook.Address,
+		Transaction: tx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	return delivery, nil
+}
+
+// retryable returns true if the delivery failed because of network error or error of the endpoint
+func retryable(responseCode int) bool {
+	return responseCode == 0 ||
+		responseCode == http.StatusRequestTimeout ||
+		responseCode == http.StatusTooManyRequests ||
+		responseCode >= http.StatusInternalServerError
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
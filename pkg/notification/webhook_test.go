@@ -0,0 +1,172 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+const (
+	webhookAddress = "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5"
+	failingHash    = blockchain.Hash("0x0000000000000000000000000000000000000000000000000000000000000001")
+	nextHash       = blockchain.Hash("0x0000000000000000000000000000000000000000000000000000000000000002")
+)
+
+// matchAll matches every transaction of every tenant
+type matchAll struct{}
+
+func (matchAll) MatchTenant(ctx context.Context, tenantID string, address blockchain.Address, tx *blockchain.Transaction) (bool, error) {
+	return true, nil
+}
+
+func newTestDispatcher(t *testing.T, allowPrivateHosts bool) *dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d, err := NewDispatcher(ctx, NewStorage(), matchAll{}, allowPrivateHosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		d.Close(context.Background())
+	})
+	return d.(*dispatcher)
+}
+
+func TestRegisterWebhookValidation(t *testing.T) {
+	tests := []struct {
+		name              string
+		address           string
+		url               string
+		allowPrivateHosts bool
+		valid             bool
+	}{
+		{name: "public host", address: webhookAddress, url: "https://93.184.216.34/hook", valid: true},
+		{name: "checksummed address", address: "0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5", url: "https://93.184.216.34/hook", valid: true},
+		{name: "missing address", address: "", url: "https://93.184.216.34/hook"},
+		{name: "short address", address: "0x95222290dd7278aa3ddd", url: "https://93.184.216.34/hook"},
+		{name: "address without prefix", address: "95222290dd7278aa3ddd389cc1e1d165cc4bafe5", url: "https://93.184.216.34/hook"},
+		{name: "not http url", address: webhookAddress, url: "ftp://93.184.216.34/hook"},
+		{name: "relative url", address: webhookAddress, url: "/hook"},
+		{name: "loopback", address: webhookAddress, url: "http://127.0.0.1:8080/hook"},
+		{name: "loopback ipv6", address: webhookAddress, url: "http://[::1]/hook"},
+		{name: "localhost", address: webhookAddress, url: "http://localhost/hook"},
+		{name: "private network", address: webhookAddress, url: "http://10.0.0.1/hook"},
+		{name: "cloud metadata", address: webhookAddress, url: "http://169.254.169.254/latest/meta-data"},
+		{name: "shared address space", address: webhookAddress, url: "http://100.100.100.200/hook"},
+		{name: "unspecified", address: webhookAddress, url: "http://0.0.0.0/hook"},
+		{name: "allowed loopback", address: webhookAddress, url: "http://127.0.0.1:8080/hook", allowPrivateHosts: true, valid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newTestDispatcher(t, test.allowPrivateHosts)
+			webhook, err := d.RegisterWebhook(context.Background(), "tenant", test.address, test.url, "")
+			if !test.valid {
+				if !errors.Is(err, ErrInvalidWebhook) {
+					t.Fatalf("expected invalid webhook error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if webhook.Address != webhookAddress {
+				t.Fatalf("expected lowercased address %s, got %s", webhookAddress, webhook.Address)
+			}
+		})
+	}
+}
+
+func TestWebhookClientRefusesPrivateAddress(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(t, false)
+	// webhook host resolved to public address at registration can later resolve to private address
+	webhook := &Webhook{ID: "webhook", Address: webhookAddress, URL: server.URL}
+	_, err := d.post(context.Background(), webhook, &Delivery{ID: "delivery", Payload: json.RawMessage(`{}`)})
+	if !errors.Is(err, errPrivateDestination) {
+		t.Fatalf("expected private destination error, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no request to private address, got %d", requests)
+	}
+}
+
+func TestFailedDeliveryDoesNotDelayNextDeliveries(t *testing.T) {
+	var mutex sync.Mutex
+	attempts := make(map[blockchain.Hash]int)
+	delivered := make(chan blockchain.Hash, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		hash := payload.Transaction.Transaction.Hash
+		mutex.Lock()
+		attempts[hash]++
+		attempt := attempts[hash]
+		mutex.Unlock()
+		// the first transaction fails once
+		if hash == failingHash && attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		delivered <- hash
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(t, true)
+	if _, err := d.RegisterWebhook(context.Background(), "tenant", webhookAddress, server.URL, ""); err != nil {
+		t.Fatal(err)
+	}
+	d.Notify(context.Background(), []*transaction.AddressTransaction{
+		transaction.NewAddressTransaction(webhookAddress, &blockchain.Transaction{Hash: failingHash}, 0),
+		transaction.NewAddressTransaction(webhookAddress, &blockchain.Transaction{Hash: nextHash}, 0),
+	})
+
+	select {
+	case hash := <-delivered:
+		if hash != nextHash {
+			t.Fatalf("expected transaction %s delivered first, got %s", nextHash, hash)
+		}
+	case <-time.After(initialBackoff / 2):
+		t.Fatal("next delivery waited for retry of the failed delivery")
+	}
+	select {
+	case hash := <-delivered:
+		if hash != failingHash {
+			t.Fatalf("expected retried transaction %s, got %s", failingHash, hash)
+		}
+	case <-time.After(3 * initialBackoff):
+		t.Fatal("failed delivery was not retried")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		backoff  time.Duration
+	}{
+		{attempts: 1, backoff: initialBackoff},
+		{attempts: 2, backoff: 2 * initialBackoff},
+		{attempts: 4, backoff: 8 * initialBackoff},
+		{attempts: 20, backoff: maxBackoff},
+	}
+	for _, test := range tests {
+		if backoff := retryBackoff(test.attempts); backoff != test.backoff {
+			t.Errorf("expected backoff %v after %d attempts, got %v", test.backoff, test.attempts, backoff)
+		}
+	}
+}
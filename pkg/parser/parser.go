@@ -2,28 +2,153 @@ package parser
 
 import (
 	"context"
+	"errors"
 	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/confirmation"
 	"github.com/veljkomatic/be-homework/pkg/storage/block"
+	"github.com/veljkomatic/be-homework/pkg/storage/trace"
 	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+	"github.com/veljkomatic/be-homework/pkg/storage/transfer"
 	subscriberpkg "github.com/veljkomatic/be-homework/pkg/subscriber"
+	"github.com/veljkomatic/be-homework/pkg/tenant"
 	"log"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSubscriptionsLimit = 100
+	MaxSubscriptionsLimit     = 1000
+	// maxScannedTransactions is the number of stored transactions scanned for a single page of transactions,
+	// when they are not enough to fill the page, partial page is returned with the cursor of the last scanned one
+	maxScannedTransactions = 10 * transaction.MaxLimit
 )
 
 type Parser interface {
 	// GetCurrentBlock returns last processed block number
 	GetCurrentBlock(ctx context.Context) int
 
-	// Subscribe add address to observer
-	Subscribe(ctx context.Context, address string) bool
+	// Subscribe add address to observer, only transactions meeting the criteria are observed,
+	// nil criteria keep criteria of already subscribed address.
+	// blockchain.ErrInvalidAddress is returned when address is not a valid hex address,
+	// subscriber.ErrQuotaExceeded is returned when caller already subscribed addresses allowed by its quota.
+	Subscribe(ctx context.Context, address string, criteria *subscriberpkg.Criteria) error
+
+	// Unsubscribe remove address from observer, stored transactions, token transfers and internal transfers of the address
+	// are deleted if purge is set
+	Unsubscribe(ctx context.Context, address string, purge bool) bool
+
+	// GetSubscription returns subscription of an address with counts of its stored transactions matched by the subscription
+	GetSubscription(ctx context.Context, address string) (*Subscription, error)
+
+	// GetSubscriptions returns page of subscriptions ordered by address, starting after the cursor
+	GetSubscriptions(ctx context.Context, cursor string, limit int) (*SubscriptionsPage, error)
+
+	// GetTransactions page of inbound or outbound transactions for an address matching the query,
+	// page contains only transactions matched by subscription of the caller, it is empty if caller is not subscribed.
+	// Page can have less transactions than the limit and still have the next cursor, when too many stored
+	// transactions matched only by subscriptions of other tenants were skipped.
+	GetTransactions(ctx context.Context, address string, query transaction.Query) *TransactionsPage
+
+	// GetTokenTransfers returns page of token transfers sent or received by an address matching the query,
+	// criteria of subscription apply only to transactions, page is empty if caller is not subscribed
+	GetTokenTransfers(ctx context.Context, address string, query transfer.Query) *TokenTransfersPage
+
+	// GetInternalTransfers returns page of internal transfers sent or received by an address matching the query,
+	// criteria of subscription apply only to transactions, page is empty if caller is not subscribed
+	GetInternalTransfers(ctx context.Context, address string, query trace.Query) *InternalTransfersPage
+
+	// IsSubscribed checks if caller is subscribed to address
+	IsSubscribed(ctx context.Context, address string) bool
+
+	// Match checks if transaction of an address is matched by subscription of the caller
+	Match(ctx context.Context, address string, tx *blockchain.Transaction) bool
+
+	// ToTransactions returns stored transactions with their current confirmation status
+	ToTransactions(ctx context.Context, transactions []*transaction.AddressTransaction) []*Transaction
+}
+
+// Transaction is a transaction of an address with its confirmation status and receipt
+type Transaction struct {
+	*blockchain.Transaction
+	BlockTimestamp int64               `json:"blockTimestamp"`
+	Status         confirmation.Status `json:"status"`
+	// Direction is in, out or self relative to the address
+	Direction transaction.RecordDirection `json:"direction"`
+	// Execution is succeeded or failed status of the receipt, it is empty when receipt is missing
+	Execution transaction.Execution `json:"execution,omitempty"`
+	Receipt   *blockchain.Receipt   `json:"receipt,omitempty"`
+}
+
+// TransactionsPage is a page of transactions of an address
+type TransactionsPage struct {
+	Transactions []*Transaction `json:"transactions"`
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// TokenTransfer is a token transfer of an address with confirmation status of its block
+type TokenTransfer struct {
+	*blockchain.TokenTransfer
+	BlockTimestamp int64                       `json:"blockTimestamp"`
+	Status         confirmation.Status         `json:"status"`
+	Direction      transaction.RecordDirection `json:"direction"`
+}
+
+// TokenTransfersPage is a page of token transfers of an address
+type TokenTransfersPage struct {
+	Transfers []*TokenTransfer `json:"transfers"`
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// InternalTransfer is an internal transfer of an address with confirmation status of its block
+type InternalTransfer struct {
+	*blockchain.InternalTransfer
+	BlockTimestamp int64                       `json:"blockTimestamp"`
+	Status         confirmation.Status         `json:"status"`
+	Direction      transaction.RecordDirection `json:"direction"`
+}
+
+// InternalTransfersPage is a page of internal transfers of an address
+type InternalTransfersPage struct {
+	Transfers []*InternalTransfer `json:"transfers"`
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Subscription is a subscribed address
+type Subscription struct {
+	Address string `json:"address"`
+	subscriberpkg.Criteria
+	CreatedAt time.Time `json:"created_at"`
+	// Matches are counts of stored transactions of the address, they are set only for a single subscription
+	Matches *SubscriptionMatches `json:"matches,omitempty"`
+}
+
+// SubscriptionMatches are counts of stored transactions of subscribed address matched by the subscription
+type SubscriptionMatches struct {
+	Total    int `json:"total"`
+	Inbound  int `json:"inbound"`
+	Outbound int `json:"outbound"`
+	// Self transactions are sent from the address to itself, they are counted as inbound and outbound too
+	Self int `json:"self"`
+}
 
-	// GetTransactions list of inbound or outbound transactions for an address
-	GetTransactions(ctx context.Context, address string) []*blockchain.Transaction
+// SubscriptionsPage is a page of subscriptions
+type SubscriptionsPage struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type parser struct {
 	subscriber            subscriberpkg.Subscriber
 	transactionRepository transaction.Repository
+	transferRepository    transfer.Repository
+	traceRepository       trace.Repository
 	blockRepository       block.Repository
+	confirmationConfig    confirmation.Config
 }
 
 var _ Parser = (*parser)(nil)
@@ -31,12 +156,18 @@ var _ Parser = (*parser)(nil)
 func NewParser(
 	subscriber subscriberpkg.Subscriber,
 	transactionRepository transaction.Repository,
+	transferRepository transfer.Repository,
+	traceRepository trace.Repository,
 	blockRepository block.Repository,
+	confirmationConfig confirmation.Config,
 ) Parser {
 	return &parser{
 		subscriber:            subscriber,
 		transactionRepository: transactionRepository,
+		transferRepository:    transferRepository,
+		traceRepository:       traceRepository,
 		blockRepository:       blockRepository,
+		confirmationConfig:    confirmationConfig,
 	}
 }
 
@@ -49,20 +180,346 @@ func (p *parser) GetCurrentBlock(ctx context.Context) int {
 	return int(currentBlockNumber)
 }
 
-func (p *parser) Subscribe(ctx context.Context, address string) bool {
-	err := p.subscriber.Subscribe(ctx, address)
+func (p *parser) Subscribe(ctx context.Context, address string, criteria *subscriberpkg.Criteria) error {
+	parsedAddress, err := blockchain.ParseAddress(address)
 	if err != nil {
+		return err
+	}
+	var maxSubscriptions int
+	if caller := tenant.FromContext(ctx); caller != nil {
+		maxSubscriptions = caller.MaxSubscriptions
+	}
+	err = p.subscriber.Subscribe(ctx, tenant.IDFromContext(ctx), parsedAddress, criteria, maxSubscriptions)
+	if err != nil && !errors.Is(err, subscriberpkg.ErrQuotaExceeded) {
 		log.Println("error subscribing to address", address, err)
+	}
+	return err
+}
+
+func (p *parser) Unsubscribe(ctx context.Context, address string, purge bool) bool {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil {
+		return false
+	}
+	err = p.subscriber.UnSubscribe(ctx, tenant.IDFromContext(ctx), parsedAddress)
+	if err != nil {
+		log.Println("error unsubscribing from address", address, err)
+		return false
+	}
+	if !purge {
+		return true
+	}
+	// transactions and transfers are stored once per address, they are kept while other tenants are subscribed to it
+	subscribed, err := p.subscriber.Test(ctx, parsedAddress)
+	if err != nil {
+		log.Println("error testing subscription of address", address, err)
+		return false
+	}
+	if subscribed {
+		return true
+	}
+	if err := p.transactionRepository.DeleteTransactions(ctx, parsedAddress); err != nil {
+		log.Println("error purging transactions of address", address, err)
+		return false
+	}
+	if err := p.transferRepository.DeleteTransfers(ctx, parsedAddress); err != nil {
+		log.Println("error purging token transfers of address", address, err)
+		return false
+	}
+	if err := p.traceRepository.DeleteInternalTransfers(ctx, parsedAddress); err != nil {
+		log.Println("error purging internal transfers of address", address, err)
 		return false
 	}
 	return true
 }
 
-func (p *parser) GetTransactions(ctx context.Context, address string) []*blockchain.Transaction {
-	txs, err := p.transactionRepository.GetTransactions(ctx, address)
+func (p *parser) GetSubscription(ctx context.Context, address string) (*Subscription, error) {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil {
+		// invalid address can not be subscribed
+		return nil, subscriberpkg.ErrNotSubscribed
+	}
+	subscription, err := p.subscriber.Get(ctx, tenant.IDFromContext(ctx), parsedAddress)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := p.countMatches(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		Address:   subscription.Address.String(),
+		Criteria:  subscription.Criteria,
+		CreatedAt: subscription.CreatedAt,
+		Matches:   matches,
+	}, nil
+}
+
+// countMatches counts stored transactions of the address matched by the subscription,
+// transactions are stored once for all tenants, so they are counted by the storage only
+// if the subscription matches every transaction, otherwise they are scanned and matched one by one
+func (p *parser) countMatches(ctx context.Context, subscription *subscriberpkg.Subscription) (*SubscriptionMatches, error) {
+	matches := &SubscriptionMatches{}
+	if subscription.Criteria.MatchesAll() {
+		// empty direction counts all transactions of the address
+		counts := map[transaction.Direction]*int{
+			"":                            &matches.Total,
+			transaction.InboundDirection:  &matches.Inbound,
+			transaction.OutboundDirection: &matches.Outbound,
+			transaction.SelfDirection:     &matches.Self,
+		}
+		for direction, count := range counts {
+			var err error
+			*count, err = p.transactionRepository.CountTransactions(ctx, subscription.Address, transaction.Query{Direction: direction})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return matches, nil
+	}
+
+	query := transaction.Query{Limit: transaction.MaxLimit}
+	for {
+		page, err := p.transactionRepository.GetTransactions(ctx, subscription.Address, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page.Transactions {
+			if !subscription.Match(subscription.Address, tx.Transaction) {
+				continue
+			}
+			matches.Total++
+			direction := tx.RecordDirection()
+			if direction.Inbound() {
+				matches.Inbound++
+			}
+			if direction.Outbound() {
+				matches.Outbound++
+			}
+			if direction == transaction.SelfRecordDirection {
+				matches.Self++
+			}
+		}
+		if page.NextCursor == "" {
+			return matches, nil
+		}
+		if query.After, err = transaction.ParseCursor(page.NextCursor); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) GetSubscriptions(ctx context.Context, cursor string, limit int) (*SubscriptionsPage, error) {
+	if limit <= 0 {
+		limit = DefaultSubscriptionsLimit
+	}
+	if limit > MaxSubscriptionsLimit {
+		limit = MaxSubscriptionsLimit
+	}
+	// cursor is the last address of the previous page, it is only compared with subscribed addresses
+	after := blockchain.Address(strings.ToLower(cursor))
+	// one more subscription is fetched to know if there is a next page
+	subscriptions, err := p.subscriber.List(ctx, tenant.IDFromContext(ctx), after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &SubscriptionsPage{
+		Subscriptions: make([]*Subscription, 0, len(subscriptions)),
+	}
+	if len(subscriptions) > limit {
+		subscriptions = subscriptions[:limit]
+		page.NextCursor = subscriptions[limit-1].Address.String()
+	}
+	for _, subscription := range subscriptions {
+		page.Subscriptions = append(page.Subscriptions, &Subscription{
+			Address:   subscription.Address.String(),
+			Criteria:  subscription.Criteria,
+			CreatedAt: subscription.CreatedAt,
+		})
+	}
+	return page, nil
+}
+
+func (p *parser) GetTransactions(ctx context.Context, address string, query transaction.Query) *TransactionsPage {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil || !p.isSubscribed(ctx, parsedAddress) {
+		return &TransactionsPage{Transactions: make([]*Transaction, 0)}
+	}
+	// transactions of the address are stored once for all tenants, transactions matched only by
+	// subscriptions of other tenants are skipped and the page is filled from the next stored pages,
+	// up to the limit of scanned transactions
+	limit := query.PageLimit()
+	matched := make([]*transaction.AddressTransaction, 0, limit)
+	var nextCursor string
+	scanned := 0
+	for {
+		page, err := p.transactionRepository.GetTransactions(ctx, parsedAddress, query)
+		if err != nil {
+			log.Println("error getting transactions for address", address, err)
+			return nil
+		}
+		scanned += len(page.Transactions)
+		for i, tx := range page.Transactions {
+			if !p.match(ctx, parsedAddress, tx.Transaction) {
+				continue
+			}
+			matched = append(matched, tx)
+			if len(matched) == limit {
+				if i < len(page.Transactions)-1 || page.NextCursor != "" {
+					nextCursor = tx.Position().Cursor()
+				}
+				break
+			}
+		}
+		if len(matched) == limit || page.NextCursor == "" {
+			break
+		}
+		if scanned >= maxScannedTransactions {
+			// page is partial, next page continues after the last scanned transaction
+			nextCursor = page.NextCursor
+			break
+		}
+		query.After, err = transaction.ParseCursor(page.NextCursor)
+		if err != nil {
+			log.Println("error parsing cursor of transactions for address", address, err)
+			return nil
+		}
+	}
+
+	transactions := p.ToTransactions(ctx, matched)
+	if transactions == nil {
+		return nil
+	}
+	return &TransactionsPage{
+		Transactions: transactions,
+		NextCursor:   nextCursor,
+	}
+}
+
+func (p *parser) GetTokenTransfers(ctx context.Context, address string, query transfer.Query) *TokenTransfersPage {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil || !p.isSubscribed(ctx, parsedAddress) {
+		return &TokenTransfersPage{Transfers: make([]*TokenTransfer, 0)}
+	}
+	page, err := p.transferRepository.GetTransfers(ctx, parsedAddress, query)
+	if err != nil {
+		log.Println("error getting token transfers for address", address, err)
+		return nil
+	}
+	currentBlockNumber, finalizedBlockNumber, ok := p.confirmationBlockNumbers(ctx)
+	if !ok {
+		return nil
+	}
+
+	transfers := make([]*TokenTransfer, 0, len(page.Transfers))
+	for _, addressTransfer := range page.Transfers {
+		blockNumber := blockchain.BlockNumber(addressTransfer.Position().BlockNumber)
+		transfers = append(transfers, &TokenTransfer{
+			TokenTransfer:  addressTransfer.Transfer,
+			BlockTimestamp: addressTransfer.BlockTimestamp,
+			Status:         p.confirmationConfig.Status(blockNumber, currentBlockNumber, finalizedBlockNumber),
+			Direction:      addressTransfer.RecordDirection(),
+		})
+	}
+	return &TokenTransfersPage{
+		Transfers:  transfers,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func (p *parser) GetInternalTransfers(ctx context.Context, address string, query trace.Query) *InternalTransfersPage {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil || !p.isSubscribed(ctx, parsedAddress) {
+		return &InternalTransfersPage{Transfers: make([]*InternalTransfer, 0)}
+	}
+	page, err := p.traceRepository.GetInternalTransfers(ctx, parsedAddress, query)
 	if err != nil {
-		log.Println("error getting transactions for address", address, err)
+		log.Println("error getting internal transfers for address", address, err)
+		return nil
+	}
+	currentBlockNumber, finalizedBlockNumber, ok := p.confirmationBlockNumbers(ctx)
+	if !ok {
 		return nil
 	}
-	return txs
+
+	transfers := make([]*InternalTransfer, 0, len(page.Transfers))
+	for _, addressTransfer := range page.Transfers {
+		blockNumber := blockchain.BlockNumber(addressTransfer.Position().BlockNumber)
+		transfers = append(transfers, &InternalTransfer{
+			InternalTransfer: addressTransfer.Transfer,
+			BlockTimestamp:   addressTransfer.BlockTimestamp,
+			Status:           p.confirmationConfig.Status(blockNumber, currentBlockNumber, finalizedBlockNumber),
+			Direction:        addressTransfer.RecordDirection(),
+		})
+	}
+	return &InternalTransfersPage{
+		Transfers:  transfers,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// confirmationBlockNumbers returns current and finalized block numbers used to calculate confirmation status
+func (p *parser) confirmationBlockNumbers(ctx context.Context) (blockchain.BlockNumber, blockchain.BlockNumber, bool) {
+	currentBlockNumber, err := p.blockRepository.GetCurrentBlockNumber(ctx)
+	if err != nil {
+		log.Println("error getting current block number", err)
+		return 0, 0, false
+	}
+	finalizedBlockNumber, err := p.blockRepository.GetFinalizedBlockNumber(ctx)
+	if err != nil {
+		log.Println("error getting finalized block number", err)
+		return 0, 0, false
+	}
+	return currentBlockNumber, finalizedBlockNumber, true
+}
+
+func (p *parser) IsSubscribed(ctx context.Context, address string) bool {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil {
+		return false
+	}
+	return p.isSubscribed(ctx, parsedAddress)
+}
+
+func (p *parser) isSubscribed(ctx context.Context, address blockchain.Address) bool {
+	_, err := p.subscriber.Get(ctx, tenant.IDFromContext(ctx), address)
+	return err == nil
+}
+
+func (p *parser) Match(ctx context.Context, address string, tx *blockchain.Transaction) bool {
+	parsedAddress, err := blockchain.ParseAddress(address)
+	if err != nil {
+		return false
+	}
+	return p.match(ctx, parsedAddress, tx)
+}
+
+func (p *parser) match(ctx context.Context, address blockchain.Address, tx *blockchain.Transaction) bool {
+	matched, err := p.subscriber.MatchTenant(ctx, tenant.IDFromContext(ctx), address, tx)
+	if err != nil {
+		log.Println("error matching transaction of address", address, err)
+		return false
+	}
+	return matched
+}
+
+func (p *parser) ToTransactions(ctx context.Context, addressTransactions []*transaction.AddressTransaction) []*Transaction {
+	currentBlockNumber, finalizedBlockNumber, ok := p.confirmationBlockNumbers(ctx)
+	if !ok {
+		return nil
+	}
+
+	transactions := make([]*Transaction, 0, len(addressTransactions))
+	for _, tx := range addressTransactions {
+		blockNumber := blockchain.BlockNumber(tx.Position().BlockNumber)
+		transactions = append(transactions, &Transaction{
+			Transaction:    tx.Transaction,
+			BlockTimestamp: tx.BlockTimestamp,
+			Status:         p.confirmationConfig.Status(blockNumber, currentBlockNumber, finalizedBlockNumber),
+			Direction:      tx.RecordDirection(),
+			Execution:      tx.Execution(),
+			Receipt:        tx.Receipt,
+		})
+	}
+	return transactions
 }
@@ -1,3 +1,34 @@
 package provider
 
+import "time"
+
 const cloudFlareRpcURL = "https://cloudflare-eth.com"
+
+const (
+	// healthDecay is the weight of the latest call in latency and error rate moving averages
+	healthDecay = 0.2
+	// failureCooldown is the period after failure in which endpoint is deprioritized
+	failureCooldown = 30 * time.Second
+)
+
+// Config is a configuration of multi endpoint provider
+type Config struct {
+	// Endpoints are rpc endpoints of the provider
+	Endpoints []string
+	// Quorum is the number of endpoints that have to agree on block number and block hash,
+	// it is at least 1 and at most the number of endpoints.
+	// If it is 1, calls are routed to the healthiest endpoint only.
+	Quorum int
+}
+
+func NewConfig(endpoints []string, quorum int) Config {
+	return Config{
+		Endpoints: endpoints,
+		Quorum:    quorum,
+	}
+}
+
+// DefaultConfig returns config with cloudflare-eth endpoint only
+func DefaultConfig() Config {
+	return NewConfig([]string{cloudFlareRpcURL}, 1)
+}
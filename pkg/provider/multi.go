@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+var (
+	ErrNoEndpoints   = errors.New("no rpc endpoints configured")
+	ErrNoQuorum      = errors.New("rpc endpoints did not reach quorum")
+	ErrInvalidQuorum = errors.New("invalid rpc quorum")
+)
+
+// endpointHealth tracks latency and error rate of an endpoint
+type endpointHealth struct {
+	latency     time.Duration
+	errorRate   float64
+	lastFailure time.Time
+	mutex       sync.RWMutex
+}
+
+// record updates moving averages of latency and error rate with the result of a call
+func (h *endpointHealth) record(latency time.Duration, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	failure := 0.0
+	if err != nil {
+		failure = 1.0
+		h.lastFailure = time.Now()
+	}
+	h.errorRate = (1-healthDecay)*h.errorRate + healthDecay*failure
+	if h.latency == 0 {
+		h.latency = latency
+		return
+	}
+	h.latency = time.Duration((1-healthDecay)*float64(h.latency) + healthDecay*float64(latency))
+}
+
+// score returns score of the endpoint, lower score is healthier endpoint
+func (h *endpointHealth) score() float64 {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	score := float64(h.latency.Milliseconds()+1) * (1 + 10*h.errorRate)
+	if time.Since(h.lastFailure) < failureCooldown {
+		score *= 10
+	}
+	return score
+}
+
+type endpoint struct {
+	url      string
+	provider Provider
+	health   *endpointHealth
+}
+
+var _ Provider = (*multiProvider)(nil)
+
+// multiProvider routes calls to the healthiest of multiple rpc endpoints,
+// fails over to the next endpoint on error, and optionally requires quorum
+// of endpoints to agree on block numbers and block hashes.
+type multiProvider struct {
+	endpoints []*endpoint
+	quorum    int
+}
+
+// NewMultiProvider creates provider of the configured endpoints,
+// it fails when no endpoint is configured or quorum can not be reached by the endpoints
+func NewMultiProvider(config Config) (Provider, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if config.Quorum < 1 || config.Quorum > len(config.Endpoints) {
+		return nil, fmt.Errorf("%w: %d of %d endpoints", ErrInvalidQuorum, config.Quorum, len(config.Endpoints))
+	}
+	endpoints := make([]*endpoint, 0, len(config.Endpoints))
+	for _, url := range config.Endpoints {
+		endpoints = append(endpoints, &endpoint{
+			url:      url,
+			provider: NewProvider(url),
+			health:   &endpointHealth{},
+		})
+	}
+	return &multiProvider{
+		endpoints: endpoints,
+		quorum:    config.Quorum,
+	}, nil
+}
+
+func (m *multiProvider) GetLatestBlockNumber(ctx context.Context) (blockchain.BlockNumber, error) {
+	getBlockNumber := func(ctx context.Context, p Provider) (blockchain.BlockNumber, error) {
+		return p.GetLatestBlockNumber(ctx)
+	}
+	if m.quorum <= 1 {
+		return withFailover(ctx, m, getBlockNumber)
+	}
+	return m.quorumBlockNumber(ctx, getBlockNumber)
+}
+
+func (m *multiProvider) GetBlockNumberByTag(ctx context.Context, tag blockchain.BlockTag) (blockchain.BlockNumber, error) {
+	getBlockNumber := func(ctx context.Context, p Provider) (blockchain.BlockNumber, error) {
+		return p.GetBlockNumberByTag(ctx, tag)
+	}
+	if m.quorum <= 1 {
+		return withFailover(ctx, m, getBlockNumber)
+	}
+	return m.quorumBlockNumber(ctx, getBlockNumber)
+}
+
+func (m *multiProvider) GetBlockByNumber(ctx context.Context, blockNumber blockchain.BlockNumber) (*blockchain.Block, error) {
+	getBlock := func(ctx context.Context, p Provider) (*blockchain.Block, error) {
+		return p.GetBlockByNumber(ctx, blockNumber)
+	}
+	if m.quorum <= 1 {
+		return withFailover(ctx, m, getBlock)
+	}
+
+	blocks := callAll(ctx, m, getBlock)
+	blocksByHash := make(map[blockchain.Hash][]*blockchain.Block)
+	for _, block := range blocks {
+		blocksByHash[block.Hash] = append(blocksByHash[block.Hash], block)
+		if len(blocksByHash[block.Hash]) >= m.quorum {
+			return block, nil
+		}
+	}
+	return nil, fmt.Errorf("block %d: %w", blockNumber, ErrNoQuorum)
+}
+
+func (m *multiProvider) GetBlocksByRange(ctx context.Context, from blockchain.BlockNumber, to blockchain.BlockNumber) ([]*blockchain.Block, error) {
+	if m.quorum <= 1 {
+		var partialBlocks []*blockchain.Block
+		var partialErr error
+		blocks, err := withFailover(ctx, m, func(ctx context.Context, p Provider) ([]*blockchain.Block, error) {
+			blocks, err := p.GetBlocksByRange(ctx, from, to)
+			if err != nil && len(blocks) > len(partialBlocks) {
+				// keep the most complete partial result in case all endpoints fail
+				partialBlocks, partialErr = blocks, err
+			}
+			return blocks, err
+		})
+		if err != nil && partialBlocks != nil {
+			return partialBlocks, partialErr
+		}
+		return blocks, err
+	}
+
+	// every endpoint returns fetched blocks even when the range is fetched partially
+	ranges := callAll(ctx, m, func(ctx context.Context, p Provider) ([]*blockchain.Block, error) {
+		blocks, _ := p.GetBlocksByRange(ctx, from, to)
+		return blocks, nil
+	})
+	blocksByNumberAndHash := make(map[string][]*blockchain.Block)
+	agreedBlocks := make(map[string]*blockchain.Block)
+	for _, blocks := range ranges {
+		for _, block := range blocks {
+			key := block.Number.String() + block.Hash.String()
+			blocksByNumberAndHash[key] = append(blocksByNumberAndHash[key], block)
+			if len(blocksByNumberAndHash[key]) >= m.quorum {
+				agreedBlocks[block.Number.String()] = block
+			}
+		}
+	}
+
+	blocks := make([]*blockchain.Block, 0, to-from+1)
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		if block, ok := agreedBlocks[blockNumber.ToHex()]; ok {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) < int(to-from+1) {
+		return blocks, fmt.Errorf("blocks %d-%d: %w", from, to, ErrNoQuorum)
+	}
+	return blocks, nil
+}
+
+// GetTransactionReceipt is not verified by quorum, receipt belongs to block which was already agreed on
+func (m *multiProvider) GetTransactionReceipt(ctx context.Context, hash blockchain.Hash) (*blockchain.Receipt, error) {
+	return withFailover(ctx, m, func(ctx context.Context, p Provider) (*blockchain.Receipt, error) {
+		return p.GetTransactionReceipt(ctx, hash)
+	})
+}
+
+// GetBlockReceipts is not verified by quorum, receipts are requested by hash of block which was already agreed on
+func (m *multiProvider) GetBlockReceipts(ctx context.Context, blockHash blockchain.Hash) ([]*blockchain.Receipt, error) {
+	return withFailover(ctx, m, func(ctx context.Context, p Provider) ([]*blockchain.Receipt, error) {
+		return p.GetBlockReceipts(ctx, blockHash)
+	})
+}
+
+// GetLogs is not verified by quorum, logs are requested by hash or range of blocks which were already agreed on
+func (m *multiProvider) GetLogs(ctx context.Context, filter LogFilter) ([]*blockchain.Log, error) {
+	return withFailover(ctx, m, func(ctx context.Context, p Provider) ([]*blockchain.Log, error) {
+		return p.GetLogs(ctx, filter)
+	})
+}
+
+// quorumBlockNumber returns the highest block number reached by at least quorum of endpoints
+func (m *multiProvider) quorumBlockNumber(
+	ctx context.Context,
+	getBlockNumber func(ctx context.Context, p Provider) (blockchain.BlockNumber, error),
+) (blockchain.BlockNumber, error) {
+	blockNumbers := callAll(ctx, m, getBlockNumber)
+	if len(blockNumbers) < m.quorum {
+		return blockchain.InvalidBlockNumber, ErrNoQuorum
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool {
+		return blockNumbers[i] > blockNumbers[j]
+	})
+	return blockNumbers[m.quorum-1], nil
+}
+
+// healthiestEndpoints returns endpoints sorted by their health score
+func (m *multiProvider) healthiestEndpoints() []*endpoint {
+	endpoints := make([]*endpoint, len(m.endpoints))
+	copy(endpoints, m.endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].health.score() < endpoints[j].health.score()
+	})
+	return endpoints
+}
+
+// withFailover calls endpoints from the healthiest one until the call succeeds
+func withFailover[T any](ctx context.Context, m *multiProvider, call func(ctx context.Context, p Provider) (T, error)) (T, error) {
+	var result T
+	err := ErrNoEndpoints
+	for _, e := range m.healthiestEndpoints() {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		start := time.Now()
+		result, err = call(ctx, e.provider)
+		e.health.record(time.Since(start), err)
+		if err == nil {
+			return result, nil
+		}
+		log.Printf("Endpoint %s failed: %s, failing over.", e.url, err)
+	}
+	return result, err
+}
+
+// callAll calls all endpoints concurrently and returns successful results
+func callAll[T any](ctx context.Context, m *multiProvider, call func(ctx context.Context, p Provider) (T, error)) []T {
+	var mutex sync.Mutex
+	results := make([]T, 0, len(m.endpoints))
+
+	wg := sync.WaitGroup{}
+	for _, e := range m.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			start := time.Now()
+			result, err := call(ctx, e.provider)
+			e.health.record(time.Since(start), err)
+			if err != nil {
+				log.Printf("Endpoint %s failed: %s.", e.url, err)
+				return
+			}
+			mutex.Lock()
+			results = append(results, result)
+			mutex.Unlock()
+		}(e)
+	}
+	wg.Wait()
+	return results
+}
@@ -22,71 +22,211 @@ const (
 type Provider interface {
 	// GetLatestBlockNumber returns the latest block number
 	GetLatestBlockNumber(ctx context.Context) (blockchain.BlockNumber, error)
+	// GetBlockNumberByTag returns the number of the block with given tag, e.g. safe or finalized
+	GetBlockNumberByTag(ctx context.Context, tag blockchain.BlockTag) (blockchain.BlockNumber, error)
 	// GetBlockByNumber returns the block by number with transactions
 	GetBlockByNumber(ctx context.Context, blockNumber blockchain.BlockNumber) (*blockchain.Block, error)
+	// GetBlocksByRange returns blocks with transactions from the inclusive range in a single round trip.
+	// Blocks are ordered by number, if some blocks fail to be fetched,
+	// fetched blocks are returned together with the error.
+	GetBlocksByRange(ctx context.Context, from blockchain.BlockNumber, to blockchain.BlockNumber) ([]*blockchain.Block, error)
+	// GetTransactionReceipt returns receipt of the mined transaction
+	GetTransactionReceipt(ctx context.Context, hash blockchain.Hash) (*blockchain.Receipt, error)
+	// GetBlockReceipts returns receipts of all transactions of the block in a single call, ordered by transaction index.
+	// Block is identified by hash, so receipts of a block which was reorged out are not returned for its replacement.
+	GetBlockReceipts(ctx context.Context, blockHash blockchain.Hash) ([]*blockchain.Receipt, error)
+	// GetLogs returns logs matching the filter ordered by block number and log index
+	GetLogs(ctx context.Context, filter LogFilter) ([]*blockchain.Log, error)
+}
+
+// LogFilter selects logs of a single block by its hash or of a range of blocks.
+// Log matches if it is emitted by one of addresses and every topic matches one of topics at its position,
+// empty addresses or topics at a position match any.
+type LogFilter struct {
+	BlockHash blockchain.Hash
+	FromBlock *blockchain.BlockNumber
+	ToBlock   *blockchain.BlockNumber
+	Addresses []blockchain.Address
+	Topics    [][]blockchain.Hash
+}
+
+// MarshalJSON encodes filter as eth_getLogs filter object
+func (f LogFilter) MarshalJSON() ([]byte, error) {
+	filter := make(map[string]any)
+	if !f.BlockHash.IsZero() {
+		filter["blockHash"] = f.BlockHash
+	}
+	if f.FromBlock != nil {
+		filter["fromBlock"] = f.FromBlock.ToHex()
+	}
+	if f.ToBlock != nil {
+		filter["toBlock"] = f.ToBlock.ToHex()
+	}
+	if len(f.Addresses) > 0 {
+		filter["address"] = f.Addresses
+	}
+	if len(f.Topics) > 0 {
+		topics := make([]any, 0, len(f.Topics))
+		for _, position := range f.Topics {
+			// null topic matches any
+			if len(position) == 0 {
+				topics = append(topics, nil)
+				continue
+			}
+			topics = append(topics, position)
+		}
+		filter["topics"] = topics
+	}
+	return json.Marshal(filter)
 }
 
 var _ Provider = (*provider)(nil)
 
-type provider struct{}
+// provider is a provider of a single rpc endpoint
+type provider struct {
+	rpcURL string
+}
 
-func NewProvider() Provider {
-	return &provider{}
+func NewProvider(rpcURL string) Provider {
+	return &provider{
+		rpcURL: rpcURL,
+	}
 }
 
 func (p *provider) GetLatestBlockNumber(ctx context.Context) (blockchain.BlockNumber, error) {
-	req, err := getLatestBlockNumberHTTPRequest(ctx)
+	var resultHexStr string
+	if err := p.call(ctx, "eth_blockNumber", json.RawMessage("[]"), &resultHexStr); err != nil {
+		return blockchain.InvalidBlockNumber, err
+	}
+	return blockchain.ParseBlockNumber(resultHexStr)
+}
+
+func (p *provider) GetBlockNumberByTag(ctx context.Context, tag blockchain.BlockTag) (blockchain.BlockNumber, error) {
+	paramsStr := fmt.Sprintf(`["%s", false]`, tag)
+	var block blockHeader
+	if err := p.call(ctx, "eth_getBlockByNumber", json.RawMessage(paramsStr), &block); err != nil {
+		return blockchain.InvalidBlockNumber, err
+	}
+	// null result of unknown tag leaves the block empty
+	if block.Hash.IsZero() {
+		return blockchain.InvalidBlockNumber, fmt.Errorf("block with tag %s not found", tag)
+	}
+	return blockchain.NewBlockNumberBuilder().FromQuantity(block.Number).Value(), nil
+}
+
+// blockHeader is a block requested without full transactions, only its number and hash are decoded,
+// transactions of such block are hashes, so it can not be decoded into blockchain.Block
+type blockHeader struct {
+	Number blockchain.Quantity `json:"number"`
+	Hash   blockchain.Hash     `json:"hash"`
+}
+
+func (p *provider) GetBlockByNumber(ctx context.Context, blockNumber blockchain.BlockNumber) (*blockchain.Block, error) {
+	paramsStr := fmt.Sprintf(`["%s", true]`, blockNumber.ToHex())
+	var block blockchain.Block
+	if err := p.call(ctx, "eth_getBlockByNumber", json.RawMessage(paramsStr), &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (p *provider) GetBlocksByRange(ctx context.Context, from blockchain.BlockNumber, to blockchain.BlockNumber) ([]*blockchain.Block, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	batch := make(jsonrpc.BatchRequest, 0, to-from+1)
+	blockNumbers := make(map[jsonrpc.ID]blockchain.BlockNumber, to-from+1)
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		paramsStr := fmt.Sprintf(`["%s", true]`, blockNumber.ToHex())
+		request := jsonrpc.NewRequest("eth_getBlockByNumber", json.RawMessage(paramsStr))
+		batch = append(batch, request)
+		blockNumbers[request.ID] = blockNumber
+	}
+
+	responses, err := p.batchCall(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	responsesByID := responses.ByID()
+	blocks := make([]*blockchain.Block, 0, len(batch))
+	var failed []blockchain.BlockNumber
+	for _, request := range batch {
+		var block blockchain.Block
+		if err := unmarshalResult(request.Method, responsesByID[request.ID], &block); err != nil {
+			failed = append(failed, blockNumbers[request.ID])
+			continue
+		}
+		blocks = append(blocks, &block)
+	}
+	if len(failed) > 0 {
+		return blocks, fmt.Errorf("failed to fetch %d blocks of range %d-%d: %v", len(failed), from, to, failed)
+	}
+	return blocks, nil
+}
+
+func (p *provider) GetTransactionReceipt(ctx context.Context, hash blockchain.Hash) (*blockchain.Receipt, error) {
+	paramsStr := fmt.Sprintf(`["%s"]`, hash)
+	var receipt blockchain.Receipt
+	if err := p.call(ctx, "eth_getTransactionReceipt", json.RawMessage(paramsStr), &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (p *provider) GetBlockReceipts(ctx context.Context, blockHash blockchain.Hash) ([]*blockchain.Receipt, error) {
+	paramsStr := fmt.Sprintf(`["%s"]`, blockHash)
+	var receipts []*blockchain.Receipt
+	if err := p.call(ctx, "eth_getBlockReceipts", json.RawMessage(paramsStr), &receipts); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (p *provider) GetLogs(ctx context.Context, filter LogFilter) ([]*blockchain.Log, error) {
+	params, err := json.Marshal([]LogFilter{filter})
+	if err != nil {
+		return nil, err
+	}
+	var logs []*blockchain.Log
+	if err := p.call(ctx, "eth_getLogs", params, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// call sends jsonrpc request with given method and params and unmarshals the result into result
+func (p *provider) call(ctx context.Context, method string, params json.RawMessage, result any) error {
+	req, err := p.newHTTPRequest(ctx, jsonrpc.NewRequest(method, params))
 	if err != nil {
 		log.Println("Error creating request:", err)
-		return blockchain.InvalidBlockNumber, err
+		return err
 	}
 	httpClient := getHttpClient()
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		log.Println("Error sending request:", err)
-		return blockchain.InvalidBlockNumber, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return blockchain.InvalidBlockNumber, err
+		return err
 	}
 
 	var rpcResponse jsonrpc.Response
 	err = json.Unmarshal(body, &rpcResponse)
 	if err != nil {
 		log.Println("Error unmarshalling response:", err)
-		return blockchain.InvalidBlockNumber, err
-	}
-	if rpcResponse.Error != nil {
-		log.Println("Error response:", rpcResponse.Error)
-		return blockchain.InvalidBlockNumber, err
-	}
-
-	var resultHexStr string
-	err = json.Unmarshal(rpcResponse.Result, &resultHexStr)
-	if err != nil {
-		log.Println("Error unmarshalling result:", err)
-		return blockchain.InvalidBlockNumber, err
-	}
-	blockNumber := blockchain.NewBlockNumberBuilder().FromHexString(resultHexStr).Value()
-	return blockNumber, nil
-}
-
-func getLatestBlockNumberHTTPRequest(ctx context.Context) (*http.Request, error) {
-	request := jsonrpc.NewRequest("eth_blockNumber", json.RawMessage("[]"))
-	payload, err := json.Marshal(request)
-	if err != nil {
-		log.Println("Error marshaling request:", err)
-		return nil, err
+		return err
 	}
-
-	return http.NewRequestWithContext(ctx, http.MethodPost, cloudFlareRpcURL, bytes.NewBuffer(payload))
+	return unmarshalResult(method, &rpcResponse, result)
 }
 
-func (p *provider) GetBlockByNumber(ctx context.Context, blockNumber blockchain.BlockNumber) (*blockchain.Block, error) {
-	req, err := getBlockByNumberHTTPRequest(ctx, blockNumber)
+// batchCall sends batch of jsonrpc requests in a single http request
+func (p *provider) batchCall(ctx context.Context, batch jsonrpc.BatchRequest) (jsonrpc.BatchResponse, error) {
+	req, err := p.newHTTPRequest(ctx, batch)
 	if err != nil {
 		log.Println("Error creating request:", err)
 		return nil, err
@@ -104,34 +244,47 @@ func (p *provider) GetBlockByNumber(ctx context.Context, blockNumber blockchain.
 		return nil, err
 	}
 
-	var rpcResponse jsonrpc.Response
-	err = json.Unmarshal(body, &rpcResponse)
-	if err != nil {
-		log.Println("Error unmarshalling response:", err)
+	var rpcResponses jsonrpc.BatchResponse
+	if err := json.Unmarshal(body, &rpcResponses); err != nil {
+		// whole batch can be rejected with a single error response
+		var rpcResponse jsonrpc.Response
+		if json.Unmarshal(body, &rpcResponse) == nil && rpcResponse.Error != nil {
+			return nil, fmt.Errorf("batch: %w", rpcResponse.Error)
+		}
+		log.Println("Error unmarshalling batch response:", err)
 		return nil, err
 	}
+	return rpcResponses, nil
+}
+
+// unmarshalResult unmarshals the result of jsonrpc response into result
+func unmarshalResult(method string, rpcResponse *jsonrpc.Response, result any) error {
+	if rpcResponse == nil {
+		return fmt.Errorf("%s: missing response", method)
+	}
 	if rpcResponse.Error != nil {
 		log.Println("Error response:", rpcResponse.Error)
-		return nil, err
+		return fmt.Errorf("%s: %w", method, rpcResponse.Error)
+	}
+	if len(rpcResponse.Result) == 0 || string(rpcResponse.Result) == "null" {
+		return fmt.Errorf("%s: empty result", method)
 	}
 
-	var block blockchain.Block
-	if err := json.Unmarshal(rpcResponse.Result, &block); err != nil {
-		log.Println("Error unmarshalling block:", err)
-		return nil, err
+	if err := json.Unmarshal(rpcResponse.Result, result); err != nil {
+		log.Println("Error unmarshalling result:", err)
+		return err
 	}
-	return &block, nil
+	return nil
 }
 
-func getBlockByNumberHTTPRequest(ctx context.Context, blockNumber blockchain.BlockNumber) (*http.Request, error) {
-	paramsStr := fmt.Sprintf(`["0x%x", true]`, blockNumber)
-	request := jsonrpc.NewRequest("eth_getBlockByNumber", json.RawMessage(paramsStr))
+// newHTTPRequest creates http request with jsonrpc request or batch request as payload
+func (p *provider) newHTTPRequest(ctx context.Context, request any) (*http.Request, error) {
 	payload, err := json.Marshal(request)
 	if err != nil {
 		log.Println("Error marshaling request:", err)
 		return nil, err
 	}
-	return http.NewRequestWithContext(ctx, http.MethodPost, cloudFlareRpcURL, bytes.NewBuffer(payload))
+	return http.NewRequestWithContext(ctx, http.MethodPost, p.rpcURL, bytes.NewBuffer(payload))
 }
 
 func getHttpClient() *http.Client {
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// newStandInHTTPNode is a local node which replies to every eth_getBlockByNumber request with the block,
+// transactions of the block are hashes as they are when full transactions are not requested
+func newStandInHTTPNode(t *testing.T, block map[string]any) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("invalid request: %s", err)
+			return
+		}
+		if request.Method != "eth_getBlockByNumber" || len(request.Params) != 2 || string(request.Params[1]) != "false" {
+			t.Errorf("unexpected request %s %s", request.Method, request.Params)
+		}
+		var result any
+		if block != nil {
+			result = block
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetBlockNumberByTagWithTransactionHashes(t *testing.T) {
+	node := newStandInHTTPNode(t, map[string]any{
+		"number":     "0x12d687",
+		"hash":       "0x" + "ab00000000000000000000000000000000000000000000000000000000000000",
+		"parentHash": "0x" + "cd00000000000000000000000000000000000000000000000000000000000000",
+		"timestamp":  "0x65a0b8c0",
+		"transactions": []string{
+			"0x" + "0100000000000000000000000000000000000000000000000000000000000000",
+			"0x" + "0200000000000000000000000000000000000000000000000000000000000000",
+		},
+	})
+
+	blockNumber, err := NewProvider(node.URL).GetBlockNumberByTag(context.Background(), blockchain.FinalizedBlockTag)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if blockNumber != blockchain.BlockNumber(1234567) {
+		t.Fatalf("expected block number 1234567, got %d", blockNumber)
+	}
+}
+
+func TestGetBlockNumberByTagNotFound(t *testing.T) {
+	node := newStandInHTTPNode(t, nil)
+
+	if _, err := NewProvider(node.URL).GetBlockNumberByTag(context.Background(), blockchain.SafeBlockTag); err == nil {
+		t.Fatal("expected error of unknown tag")
+	}
+}
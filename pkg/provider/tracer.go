@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// TraceMethod is the rpc method family used to trace transactions
+type TraceMethod string
+
+const (
+	// DebugTraceMethod traces block with debug_traceBlockByHash and callTracer (geth, reth, erigon)
+	DebugTraceMethod = TraceMethod("debug")
+	// TraceTraceMethod traces block with trace_block (erigon, nethermind, reth)
+	TraceTraceMethod = TraceMethod("trace")
+)
+
+var ErrUnknownTraceMethod = errors.New("unknown trace method")
+
+// Tracer is an optional provider capability to trace execution of transactions,
+// tracing is expensive and supported only by archive or full nodes with debug or trace api enabled
+type Tracer interface {
+	// TraceBlock returns call trees of transactions of the block ordered by transaction index
+	TraceBlock(ctx context.Context, block *blockchain.Block) ([]*blockchain.TransactionTrace, error)
+}
+
+var _ Tracer = (*tracer)(nil)
+
+// tracer traces blocks on a single rpc endpoint
+type tracer struct {
+	provider *provider
+	method   TraceMethod
+}
+
+func NewTracer(rpcURL string, method TraceMethod) (Tracer, error) {
+	switch method {
+	case DebugTraceMethod, TraceTraceMethod:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTraceMethod, method)
+	}
+	return &tracer{
+		provider: &provider{rpcURL: rpcURL},
+		method:   method,
+	}, nil
+}
+
+func (t *tracer) TraceBlock(ctx context.Context, block *blockchain.Block) ([]*blockchain.TransactionTrace, error) {
+	if t.method == TraceTraceMethod {
+		return t.traceBlock(ctx, block)
+	}
+	return t.debugTraceBlock(ctx, block)
+}
+
+// callTracerFrame is a call frame of geth callTracer, call type is upper case
+type callTracerFrame struct {
+	Type  string               `json:"type"`
+	From  blockchain.Address   `json:"from"`
+	To    blockchain.Address   `json:"to"`
+	Value *blockchain.Quantity `json:"value"`
+	Error string               `json:"error"`
+	Calls []*callTracerFrame   `json:"calls"`
+}
+
+func (f *callTracerFrame) callFrame() *blockchain.CallFrame {
+	frame := &blockchain.CallFrame{
+		Type:  blockchain.CallType(strings.ToLower(f.Type)),
+		From:  f.From,
+		To:    f.To,
+		Value: f.Value,
+		Error: f.Error,
+	}
+	for _, call := range f.Calls {
+		frame.Calls = append(frame.Calls, call.callFrame())
+	}
+	return frame
+}
+
+// debugTraceBlock traces block by hash, so traces of a block which was reorged out are not returned for its replacement
+func (t *tracer) debugTraceBlock(ctx context.Context, block *blockchain.Block) ([]*blockchain.TransactionTrace, error) {
+	params, err := json.Marshal([]any{block.Hash, map[string]string{"tracer": "callTracer"}})
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		TxHash blockchain.Hash  `json:"txHash"`
+		Result *callTracerFrame `json:"result"`
+		Error  string           `json:"error"`
+	}
+	if err := t.provider.call(ctx, "debug_traceBlockByHash", params, &results); err != nil {
+		return nil, err
+	}
+	if len(results) != len(block.Transactions) {
+		return nil, fmt.Errorf("traced %d of %d transactions of block %s", len(results), len(block.Transactions), block.Number)
+	}
+	traces := make([]*blockchain.TransactionTrace, 0, len(results))
+	for i, result := range results {
+		tx := block.Transactions[i]
+		if result.Error != "" || result.Result == nil {
+			return nil, fmt.Errorf("failed to trace transaction %s: %s", tx.Hash, result.Error)
+		}
+		// older nodes do not return transaction hash, results are in order of transactions
+		if !result.TxHash.IsZero() && result.TxHash != tx.Hash {
+			return nil, fmt.Errorf("trace of transaction %s does not match transaction %s", result.TxHash, tx.Hash)
+		}
+		traces = append(traces, &blockchain.TransactionTrace{
+			TransactionHash:  tx.Hash,
+			TransactionIndex: tx.TransactionIndex,
+			BlockHash:        block.Hash,
+			BlockNumber:      block.Number,
+			Call:             result.Result.callFrame(),
+		})
+	}
+	return traces, nil
+}
+
+// parityTrace is a flat trace of trace_block, traces of a transaction are in depth first order
+// and trace address is the path of the call in the call tree
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType      string               `json:"callType"`
+		From          blockchain.Address   `json:"from"`
+		To            blockchain.Address   `json:"to"`
+		Value         *blockchain.Quantity `json:"value"`
+		Address       blockchain.Address   `json:"address"`
+		RefundAddress blockchain.Address   `json:"refundAddress"`
+		Balance       *blockchain.Quantity `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		Address blockchain.Address `json:"address"`
+	} `json:"result"`
+	Error           string          `json:"error"`
+	TraceAddress    []int           `json:"traceAddress"`
+	TransactionHash blockchain.Hash `json:"transactionHash"`
+	BlockHash       blockchain.Hash `json:"blockHash"`
+}
+
+func (t *parityTrace) callFrame() *blockchain.CallFrame {
+	frame := &blockchain.CallFrame{
+		From:  t.Action.From,
+		To:    t.Action.To,
+		Value: t.Action.Value,
+		Error: t.Error,
+	}
+	switch t.Type {
+	case "create":
+		frame.Type = blockchain.CreateCallType
+		// address of created contract is missing when creation failed
+		if t.Result != nil {
+			frame.To = t.Result.Address
+		}
+	case "suicide":
+		frame.Type = blockchain.SelfDestructCallType
+		frame.From = t.Action.Address
+		frame.To = t.Action.RefundAddress
+		frame.Value = t.Action.Balance
+	default:
+		frame.Type = blockchain.CallType(t.Action.CallType)
+	}
+	return frame
+}
+
+// traceBlock traces block by number, traces of another block at the same height are rejected
+func (t *tracer) traceBlock(ctx context.Context, block *blockchain.Block) ([]*blockchain.TransactionTrace, error) {
+	params, err := json.Marshal([]string{block.Number.String()})
+	if err != nil {
+		return nil, err
+	}
+	var results []*parityTrace
+	if err := t.provider.call(ctx, "trace_block", params, &results); err != nil {
+		return nil, err
+	}
+
+	transactions := make(map[blockchain.Hash]*blockchain.Transaction, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		transactions[tx.Hash] = tx
+	}
+	traces := make([]*blockchain.TransactionTrace, 0, len(block.Transactions))
+	var trace *blockchain.TransactionTrace
+	// frames of the current transaction by their trace address
+	var frames map[string]*blockchain.CallFrame
+	for _, result := range results {
+		// block and uncle rewards are not part of any transaction
+		if result.Type == "reward" || result.TransactionHash.IsZero() {
+			continue
+		}
+		if result.BlockHash != block.Hash {
+			return nil, fmt.Errorf("trace of block %s does not match block %s", result.BlockHash, block.Hash)
+		}
+		frame := result.callFrame()
+		if len(result.TraceAddress) == 0 {
+			tx, ok := transactions[result.TransactionHash]
+			if !ok {
+				return nil, fmt.Errorf("traced transaction %s is not in block %s", result.TransactionHash, block.Number)
+			}
+			trace = &blockchain.TransactionTrace{
+				TransactionHash:  tx.Hash,
+				TransactionIndex: tx.TransactionIndex,
+				BlockHash:        block.Hash,
+				BlockNumber:      block.Number,
+				Call:             frame,
+			}
+			traces = append(traces, trace)
+			frames = map[string]*blockchain.CallFrame{traceAddressKey(nil): frame}
+			continue
+		}
+		if trace == nil || result.TransactionHash != trace.TransactionHash {
+			return nil, fmt.Errorf("trace of transaction %s is out of order", result.TransactionHash)
+		}
+		parent, ok := frames[traceAddressKey(result.TraceAddress[:len(result.TraceAddress)-1])]
+		if !ok {
+			return nil, fmt.Errorf("trace of transaction %s is out of order", result.TransactionHash)
+		}
+		parent.Calls = append(parent.Calls, frame)
+		frames[traceAddressKey(result.TraceAddress)] = frame
+	}
+	return traces, nil
+}
+
+// traceAddressKey returns map key of trace address
+func traceAddressKey(traceAddress []int) string {
+	return fmt.Sprint(traceAddress)
+}
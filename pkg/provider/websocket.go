@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/veljkomatic/be-homework/common/jsonrpc"
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+const (
+	headsBufferSize       = 16
+	minReconnectDelay     = time.Second
+	maxReconnectDelay     = time.Minute
+	websocketPingInterval = 30 * time.Second
+	websocketReadTimeout  = 2 * time.Minute
+	websocketWriteTimeout = 10 * time.Second
+)
+
+// HeadSubscriber is a provider capability to push new chain heads
+type HeadSubscriber interface {
+	// SubscribeNewHeads subscribes to new chain heads, subscription is kept alive until context is done
+	SubscribeNewHeads(ctx context.Context) HeadSubscription
+}
+
+// HeadSubscription is a subscription to new chain heads
+type HeadSubscription interface {
+	// Heads returns channel of new heads, heads are blocks without transactions.
+	// Channel is closed when the subscription context is done.
+	Heads() <-chan *blockchain.Block
+	// Connected returns true if the subscription is currently live
+	Connected() bool
+}
+
+var (
+	_ Provider       = (*webSocketProvider)(nil)
+	_ HeadSubscriber = (*webSocketProvider)(nil)
+)
+
+// webSocketProvider subscribes to new heads via websocket eth_subscribe,
+// all other calls are delegated to the wrapped provider.
+type webSocketProvider struct {
+	Provider
+	wsURL string
+}
+
+func NewWebSocketProvider(wsURL string, rpcProvider Provider) Provider {
+	return &webSocketProvider{
+		Provider: rpcProvider,
+		wsURL:    wsURL,
+	}
+}
+
+func (p *webSocketProvider) SubscribeNewHeads(ctx context.Context) HeadSubscription {
+	subscription := &headSubscription{
+		wsURL: p.wsURL,
+		heads: make(chan *blockchain.Block, headsBufferSize),
+	}
+	go subscription.run(ctx)
+	return subscription
+}
+
+var _ HeadSubscription = (*headSubscription)(nil)
+
+type headSubscription struct {
+	wsURL     string
+	heads     chan *blockchain.Block
+	connected atomic.Bool
+}
+
+func (s *headSubscription) Heads() <-chan *blockchain.Block {
+	return s.heads
+}
+
+func (s *headSubscription) Connected() bool {
+	return s.connected.Load()
+}
+
+// run keeps subscription alive, it reconnects and resubscribes with exponential backoff
+func (s *headSubscription) run(ctx context.Context) {
+	defer close(s.heads)
+
+	reconnectDelay := minReconnectDelay
+	for {
+		err := s.subscribe(ctx, func() { reconnectDelay = minReconnectDelay })
+		s.connected.Store(false)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("New heads subscription failed: %s. Reconnecting in %v...", err, reconnectDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+		reconnectDelay *= 2
+		if reconnectDelay > maxReconnectDelay {
+			reconnectDelay = maxReconnectDelay
+		}
+	}
+}
+
+// subscribe connects to the node, subscribes to new heads and pushes them to the heads channel
+// until the connection fails or context is done.
+func (s *headSubscription) subscribe(ctx context.Context, onSubscribed func()) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.wsURL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// pong handler runs in the reading goroutine, so it is set before reading starts,
+	// setting it from keepAlive would race with reading
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(websocketReadTimeout))
+	})
+
+	subscriptionID, err := s.sendSubscribe(conn)
+	if err != nil {
+		return err
+	}
+	s.connected.Store(true)
+	onSubscribed()
+	log.Printf("Subscribed to new heads, subscription %s.", subscriptionID)
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.keepAlive(ctx, conn, subscriptionID, done)
+
+	for {
+		var notification jsonrpc.Notification
+		if err := readJSON(conn, &notification); err != nil {
+			return err
+		}
+		if notification.Method != "eth_subscription" {
+			continue
+		}
+		var params jsonrpc.SubscriptionParams
+		if err := json.Unmarshal(notification.Params, &params); err != nil || params.Subscription != subscriptionID {
+			continue
+		}
+		var head blockchain.Block
+		if err := json.Unmarshal(params.Result, &head); err != nil {
+			log.Println("Error unmarshalling new head:", err)
+			continue
+		}
+		select {
+		case s.heads <- &head:
+		default:
+			// consumer is behind, it will catch up with the next head
+		}
+	}
+}
+
+// sendSubscribe sends eth_subscribe request and waits for the subscription id
+func (s *headSubscription) sendSubscribe(conn *websocket.Conn) (string, error) {
+	request := jsonrpc.NewRequest("eth_subscribe", json.RawMessage(`["newHeads"]`))
+	conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+	if err := conn.WriteJSON(request); err != nil {
+		return "", err
+	}
+	for {
+		var response jsonrpc.Response
+		if err := readJSON(conn, &response); err != nil {
+			return "", err
+		}
+		if response.ID != request.ID {
+			continue
+		}
+		if response.Error != nil {
+			return "", fmt.Errorf("eth_subscribe: rpc error %d: %s", response.Error.Code, response.Error.Message)
+		}
+		var subscriptionID string
+		if err := json.Unmarshal(response.Result, &subscriptionID); err != nil {
+			return "", err
+		}
+		if subscriptionID == "" {
+			return "", errors.New("eth_subscribe: empty subscription id")
+		}
+		return subscriptionID, nil
+	}
+}
+
+// keepAlive pings the node until the subscription is done, pongs extend the read deadline in the pong handler set by subscribe,
+// when the context is done it unsubscribes and closes the connection.
+func (s *headSubscription) keepAlive(ctx context.Context, conn *websocket.Conn, subscriptionID string, done <-chan struct{}) {
+	ticker := time.NewTicker(websocketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			// keepAlive is the only writer after subscribing, so it is safe to write here
+			params := fmt.Sprintf(`["%s"]`, subscriptionID)
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+			if err := conn.WriteJSON(jsonrpc.NewRequest("eth_unsubscribe", json.RawMessage(params))); err != nil {
+				log.Println("Error unsubscribing from new heads:", err)
+			}
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(websocketWriteTimeout))
+			conn.Close()
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout)); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// readJSON reads the next message and extends the read deadline
+func readJSON(conn *websocket.Conn, v any) error {
+	conn.SetReadDeadline(time.Now().Add(websocketReadTimeout))
+	return conn.ReadJSON(v)
+}
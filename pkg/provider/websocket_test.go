@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// standInNode is a local node which accepts eth_subscribe of new heads over websocket,
+// every connection gets the next batch of heads, connection without heads is kept open
+// and connection is closed after its heads were pushed, so client has to reconnect.
+type standInNode struct {
+	server      *httptest.Server
+	heads       [][]int64
+	connections atomic.Int32
+	mutex       sync.Mutex
+}
+
+func newStandInNode(t *testing.T, heads ...[]int64) *standInNode {
+	node := &standInNode{heads: heads}
+	upgrader := websocket.Upgrader{}
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connection := int(node.connections.Add(1)) - 1
+
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []string        `json:"params"`
+		}
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+		if request.Method != "eth_subscribe" || len(request.Params) != 1 || request.Params[0] != "newHeads" {
+			t.Errorf("unexpected request %s %v", request.Method, request.Params)
+			return
+		}
+		subscriptionID := fmt.Sprintf("0x%x", connection+1)
+		conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": subscriptionID})
+
+		node.mutex.Lock()
+		var heads []int64
+		if connection < len(node.heads) {
+			heads = node.heads[connection]
+		}
+		node.mutex.Unlock()
+		if heads == nil {
+			// keep connection open until client closes it
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+		for _, number := range heads {
+			conn.WriteJSON(map[string]any{
+				"jsonrpc": "2.0",
+				"method":  "eth_subscription",
+				"params": map[string]any{
+					"subscription": subscriptionID,
+					"result": map[string]any{
+						"number": fmt.Sprintf("0x%x", number),
+						"hash":   fmt.Sprintf("0x%064x", number),
+					},
+				},
+			})
+		}
+	}))
+	return node
+}
+
+func (n *standInNode) url() string {
+	return "ws" + strings.TrimPrefix(n.server.URL, "http")
+}
+
+// fallbackProvider is the wrapped provider used for polling
+type fallbackProvider struct {
+	Provider
+	calls atomic.Int32
+}
+
+func (p *fallbackProvider) GetLatestBlockNumber(ctx context.Context) (blockchain.BlockNumber, error) {
+	p.calls.Add(1)
+	return blockchain.BlockNumber(7), nil
+}
+
+func receiveHead(t *testing.T, heads <-chan *blockchain.Block) *blockchain.Block {
+	t.Helper()
+	select {
+	case head, ok := <-heads:
+		if !ok {
+			t.Fatal("heads channel closed")
+		}
+		return head
+	case <-time.After(5 * time.Second):
+		t.Fatal("head not received")
+	}
+	return nil
+}
+
+func TestWebSocketProviderNewHeads(t *testing.T) {
+	node := newStandInNode(t, []int64{1, 2, 3}, nil)
+	defer node.server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subscription := NewWebSocketProvider(node.url(), &fallbackProvider{}).(HeadSubscriber).SubscribeNewHeads(ctx)
+
+	for _, expected := range []int64{1, 2, 3} {
+		head := receiveHead(t, subscription.Heads())
+		if head.Number.Int64() != expected {
+			t.Fatalf("expected head %d, got %d", expected, head.Number.Int64())
+		}
+		if head.Hash != blockchain.Hash(fmt.Sprintf("0x%064x", expected)) {
+			t.Fatalf("unexpected hash %s of head %d", head.Hash, expected)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-subscription.Heads():
+		if ok {
+			t.Fatal("unexpected head after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("heads channel not closed after cancel")
+	}
+	if subscription.Connected() {
+		t.Fatal("subscription connected after cancel")
+	}
+}
+
+func TestWebSocketProviderReconnect(t *testing.T) {
+	node := newStandInNode(t, []int64{1}, []int64{2}, nil)
+	defer node.server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	subscription := NewWebSocketProvider(node.url(), &fallbackProvider{}).(HeadSubscriber).SubscribeNewHeads(ctx)
+
+	if head := receiveHead(t, subscription.Heads()); head.Number.Int64() != 1 {
+		t.Fatalf("expected head 1, got %d", head.Number.Int64())
+	}
+	// node closes the first connection, head of the second connection is received after reconnect
+	if head := receiveHead(t, subscription.Heads()); head.Number.Int64() != 2 {
+		t.Fatalf("expected head 2, got %d", head.Number.Int64())
+	}
+	if connections := node.connections.Load(); connections < 2 {
+		t.Fatalf("expected reconnect, got %d connections", connections)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !subscription.Connected() {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not connected after reconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketProviderFallbackToPolling(t *testing.T) {
+	node := newStandInNode(t)
+	// node is down, subscription is not live and block numbers are polled from the wrapped provider
+	node.server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fallback := &fallbackProvider{}
+	webSocketProvider := NewWebSocketProvider(node.url(), fallback)
+	subscription := webSocketProvider.(HeadSubscriber).SubscribeNewHeads(ctx)
+
+	time.Sleep(100 * time.Millisecond)
+	if subscription.Connected() {
+		t.Fatal("subscription connected to node which is down")
+	}
+	blockNumber, err := webSocketProvider.GetLatestBlockNumber(ctx)
+	if err != nil || blockNumber != 7 {
+		t.Fatalf("unexpected block number %d: %v", blockNumber, err)
+	}
+	if fallback.calls.Load() != 1 {
+		t.Fatalf("expected call of wrapped provider, got %d", fallback.calls.Load())
+	}
+}
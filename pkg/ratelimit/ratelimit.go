@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// cleanupInterval is the interval in which buckets refilled to their burst are dropped
+const cleanupInterval = time.Minute
+
+// Rate is the number of requests per second with the number of requests allowed at once
+type Rate struct {
+	PerSecond float64 `json:"per_second"`
+	Burst     int     `json:"burst"`
+}
+
+// Enabled checks if rate limits requests, rate without requests per second is unlimited
+func (r Rate) Enabled() bool {
+	return r.PerSecond > 0
+}
+
+// burst returns size of the bucket, bucket holds at least one token
+func (r Rate) burst() float64 {
+	return math.Max(float64(r.Burst), 1)
+}
+
+// Limiter limits rate of requests by key with token buckets.
+// Every key has its own bucket refilled with the rate, request takes a token from the bucket
+// and it is rejected when bucket is empty. Buckets which are full again are dropped,
+// so keys seen only once do not accumulate.
+type Limiter struct {
+	buckets     map[string]*bucket
+	lastCleanup time.Time
+	mutex       sync.Mutex
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// rate is the rate of the last request, rate of a key can change when its limits are updated
+	rate Rate
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets:     make(map[string]*bucket),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket of the key, when bucket is empty request is not allowed
+// and the duration after which the next token is available is returned
+func (l *Limiter) Allow(key string, rate Rate) (bool, time.Duration) {
+	if !rate.Enabled() {
+		return true, 0
+	}
+	now := time.Now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.cleanup(now)
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: rate.burst(), updated: now}
+		l.buckets[key] = b
+	}
+	b.rate = rate
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / rate.PerSecond * float64(time.Second))
+	return false, wait
+}
+
+// refill adds tokens for the time elapsed since the last update, up to the burst of the rate
+func (b *bucket) refill(now time.Time) {
+	b.tokens = b.available(now)
+	b.updated = now
+}
+
+// available returns number of tokens in the bucket at the given time
+func (b *bucket) available(now time.Time) float64 {
+	elapsed := now.Sub(b.updated).Seconds()
+	return math.Min(b.rate.burst(), b.tokens+elapsed*b.rate.PerSecond)
+}
+
+// cleanup drops buckets which are full, caller holds the lock
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < cleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+	for key, b := range l.buckets {
+		if b.available(now) >= b.rate.burst() {
+			delete(l.buckets, key)
+		}
+	}
+}
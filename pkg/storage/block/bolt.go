@@ -0,0 +1,74 @@
+package block
+
+import (
+	"context"
+	"encoding/binary"
+
+	"go.etcd.io/bbolt"
+)
+
+var (
+	blocksBucket              = []byte("blocks")
+	lastProcessedBlockNumberK = []byte("last_processed")
+	finalizedBlockNumberK     = []byte("finalized")
+)
+
+var _ Storage = (*boltStorage)(nil)
+
+// boltStorage is a storage that persists last processed and finalized block numbers in bolt database
+type boltStorage struct {
+	db *bbolt.DB
+}
+
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(blocksBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStorage{
+		db: db,
+	}, nil
+}
+
+// Get returns last processed block number
+func (s *boltStorage) Get(ctx context.Context) (int64, error) {
+	return s.get(lastProcessedBlockNumberK)
+}
+
+// Save saves last processed block number
+func (s *boltStorage) Save(ctx context.Context, lastProcessedBlockNumber int64) error {
+	return s.save(lastProcessedBlockNumberK, lastProcessedBlockNumber)
+}
+
+// GetFinalized returns last known finalized block number
+func (s *boltStorage) GetFinalized(ctx context.Context) (int64, error) {
+	return s.get(finalizedBlockNumberK)
+}
+
+// SaveFinalized saves last known finalized block number
+func (s *boltStorage) SaveFinalized(ctx context.Context, finalizedBlockNumber int64) error {
+	return s.save(finalizedBlockNumberK, finalizedBlockNumber)
+}
+
+func (s *boltStorage) get(key []byte) (int64, error) {
+	var blockNumber int64
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket(blocksBucket).Get(key)
+		if len(value) == 8 {
+			blockNumber = int64(binary.BigEndian.Uint64(value))
+		}
+		return nil
+	})
+	return blockNumber, err
+}
+
+func (s *boltStorage) save(key []byte, blockNumber int64) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, uint64(blockNumber))
+		return tx.Bucket(blocksBucket).Put(key, value)
+	})
+}
@@ -9,11 +9,15 @@ import (
 // ReadOnlyBlockRepository is responsible for reading block number
 type ReadOnlyBlockRepository interface {
 	GetCurrentBlockNumber(ctx context.Context) (blockchain.BlockNumber, error)
+	// GetFinalizedBlockNumber returns last known finalized block number
+	GetFinalizedBlockNumber(ctx context.Context) (blockchain.BlockNumber, error)
 }
 
 // WriteBlockRepository is responsible for writing block number
 type WriteBlockRepository interface {
 	SaveBlockNumber(ctx context.Context, blockNumber blockchain.BlockNumber) error
+	// SaveFinalizedBlockNumber saves last known finalized block number
+	SaveFinalizedBlockNumber(ctx context.Context, blockNumber blockchain.BlockNumber) error
 }
 
 // Repository is responsible for reading and writing block number
@@ -45,3 +49,15 @@ func (r repository) GetCurrentBlockNumber(ctx context.Context) (blockchain.Block
 func (r repository) SaveBlockNumber(ctx context.Context, blockNumber blockchain.BlockNumber) error {
 	return r.storage.Save(ctx, blockNumber.ToInt64())
 }
+
+func (r repository) GetFinalizedBlockNumber(ctx context.Context) (blockchain.BlockNumber, error) {
+	finalizedBlockNumber, err := r.storage.GetFinalized(ctx)
+	if err != nil {
+		return blockchain.InvalidBlockNumber, err
+	}
+	return blockchain.BlockNumber(finalizedBlockNumber), nil
+}
+
+func (r repository) SaveFinalizedBlockNumber(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	return r.storage.SaveFinalized(ctx, blockNumber.ToInt64())
+}
@@ -0,0 +1,65 @@
+package block
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+)
+
+const (
+	lastProcessedCheckpoint = "last_processed"
+	finalizedCheckpoint     = "finalized"
+)
+
+var _ Storage = (*sqlStorage)(nil)
+
+// sqlStorage is a storage that persists last processed and finalized block numbers in sql database
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+// Get returns last processed block number
+func (s *sqlStorage) Get(ctx context.Context) (int64, error) {
+	return s.get(ctx, lastProcessedCheckpoint)
+}
+
+// Save saves last processed block number
+func (s *sqlStorage) Save(ctx context.Context, lastProcessedBlockNumber int64) error {
+	return s.save(ctx, lastProcessedCheckpoint, lastProcessedBlockNumber)
+}
+
+// GetFinalized returns last known finalized block number
+func (s *sqlStorage) GetFinalized(ctx context.Context) (int64, error) {
+	return s.get(ctx, finalizedCheckpoint)
+}
+
+// SaveFinalized saves last known finalized block number
+func (s *sqlStorage) SaveFinalized(ctx context.Context, finalizedBlockNumber int64) error {
+	return s.save(ctx, finalizedCheckpoint, finalizedBlockNumber)
+}
+
+func (s *sqlStorage) get(ctx context.Context, checkpoint string) (int64, error) {
+	var blockNumber int64
+	err := s.db.QueryRowContext(ctx, s.db.Rebind(`SELECT block_number FROM block_checkpoints WHERE name = ?`), checkpoint).Scan(&blockNumber)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return blockNumber, err
+}
+
+func (s *sqlStorage) save(ctx context.Context, checkpoint string, blockNumber int64) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`
+		INSERT INTO block_checkpoints (name, block_number) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET block_number = excluded.block_number`),
+		checkpoint, blockNumber,
+	)
+	return err
+}
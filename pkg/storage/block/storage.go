@@ -8,11 +8,13 @@ import (
 // ReadOnlyStorage is a storage that can only be read from
 type ReadOnlyStorage interface {
 	Get(ctx context.Context) (int64, error)
+	GetFinalized(ctx context.Context) (int64, error)
 }
 
 // WriteStorage is a storage that can be written to
 type WriteStorage interface {
 	Save(ctx context.Context, lastProcessedBlockNumber int64) error
+	SaveFinalized(ctx context.Context, finalizedBlockNumber int64) error
 }
 
 // Storage is a storage that can be read from and written to
@@ -23,14 +25,16 @@ type Storage interface {
 
 var _ Storage = (*inMemoryStorage)(nil)
 
-// inMemoryStorage is a storage that stores last processed block number in memory
+// inMemoryStorage is a storage that stores last processed and finalized block numbers in memory
 type inMemoryStorage struct {
 	lastProcessedBlockNumber int64
+	finalizedBlockNumber     int64
 }
 
 func NewStorage() Storage {
 	return &inMemoryStorage{
 		lastProcessedBlockNumber: 0,
+		finalizedBlockNumber:     0,
 	}
 }
 
@@ -44,3 +48,14 @@ func (s *inMemoryStorage) Save(ctx context.Context, lastProcessedBlockNumber int
 	atomic.StoreInt64(&s.lastProcessedBlockNumber, lastProcessedBlockNumber)
 	return nil
 }
+
+// GetFinalized returns last known finalized block number
+func (s *inMemoryStorage) GetFinalized(ctx context.Context) (int64, error) {
+	return atomic.LoadInt64(&s.finalizedBlockNumber), nil
+}
+
+// SaveFinalized saves last known finalized block number
+func (s *inMemoryStorage) SaveFinalized(ctx context.Context, finalizedBlockNumber int64) error {
+	atomic.StoreInt64(&s.finalizedBlockNumber, finalizedBlockNumber)
+	return nil
+}
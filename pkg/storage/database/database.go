@@ -0,0 +1,80 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	// sql drivers
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Dialect is the sql dialect of the database
+type Dialect string
+
+const (
+	SQLiteDialect   = Dialect("sqlite")
+	PostgresDialect = Dialect("postgres")
+)
+
+// DB is a sql database of the given dialect.
+// Queries are written with ? placeholders and rebound to the dialect.
+type DB struct {
+	*sql.DB
+	dialect Dialect
+}
+
+// Open opens database of the given dialect, for sqlite dsn is the path of the database file
+func Open(dialect Dialect, dsn string) (*DB, error) {
+	var db *sql.DB
+	var err error
+	switch dialect {
+	case SQLiteDialect:
+		db, err = sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, err
+		}
+		// sqlite allows a single writer, serialize access instead of failing with busy errors
+		db.SetMaxOpenConns(1)
+	case PostgresDialect:
+		db, err = sql.Open("postgres", dsn)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown sql dialect %s", dialect)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DB{
+		DB:      db,
+		dialect: dialect,
+	}, nil
+}
+
+// Dialect returns dialect of the database
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
+// Rebind replaces ? placeholders with placeholders of the database dialect
+func (db *DB) Rebind(query string) string {
+	if db.dialect != PostgresDialect {
+		return query
+	}
+	var rebound strings.Builder
+	position := 0
+	for _, char := range query {
+		if char == '?' {
+			position++
+			rebound.WriteString("$" + strconv.Itoa(position))
+			continue
+		}
+		rebound.WriteRune(char)
+	}
+	return rebound.String()
+}
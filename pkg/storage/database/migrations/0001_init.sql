@@ -0,0 +1,30 @@
+CREATE TABLE block_checkpoints (
+    name TEXT PRIMARY KEY,
+    block_number BIGINT NOT NULL
+);
+
+CREATE TABLE address_transactions (
+    address TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    transaction_index BIGINT NOT NULL,
+    hash TEXT NOT NULL,
+    block_hash TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    value TEXT NOT NULL,
+    gas_price TEXT NOT NULL,
+    gas TEXT NOT NULL,
+    input TEXT NOT NULL,
+    PRIMARY KEY (address, block_number, transaction_index)
+);
+
+CREATE INDEX address_transactions_block_number_idx ON address_transactions (block_number);
+
+CREATE INDEX address_transactions_hash_idx ON address_transactions (hash);
+
+CREATE TABLE subscriptions (
+    address TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    event TEXT NOT NULL
+);
@@ -0,0 +1,7 @@
+-- block timestamp and zero padded decimal value are used to filter transactions by time and value range,
+-- transactions stored before this migration have zero timestamp and empty value
+ALTER TABLE address_transactions ADD COLUMN block_timestamp BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE address_transactions ADD COLUMN value_wei TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX address_transactions_address_block_timestamp_idx ON address_transactions (address, block_timestamp);
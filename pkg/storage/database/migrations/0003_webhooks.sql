@@ -0,0 +1,27 @@
+-- webhooks notified about matched transactions and the delivery log of every webhook,
+-- dead letters are deliveries with status dead
+CREATE TABLE webhooks (
+    id         TEXT PRIMARY KEY,
+    address    TEXT NOT NULL,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id               TEXT PRIMARY KEY,
+    webhook_id       TEXT NOT NULL,
+    address          TEXT NOT NULL,
+    transaction_hash TEXT NOT NULL,
+    payload          TEXT NOT NULL,
+    status           TEXT NOT NULL,
+    attempts         INTEGER NOT NULL,
+    last_error       TEXT NOT NULL,
+    response_code    INTEGER NOT NULL,
+    created_at       BIGINT NOT NULL,
+    updated_at       BIGINT NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_id, created_at);
+
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries (status);
@@ -0,0 +1,2 @@
+-- subscriptions created before this migration have zero creation time
+ALTER TABLE subscriptions ADD COLUMN created_at BIGINT NOT NULL DEFAULT 0;
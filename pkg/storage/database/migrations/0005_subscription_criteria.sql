@@ -0,0 +1,13 @@
+-- subscription criteria replace unused type and event of the subscription,
+-- kinds and method selectors are comma separated lists, min value is decimal wei
+ALTER TABLE subscriptions ADD COLUMN direction TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE subscriptions ADD COLUMN kinds TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE subscriptions ADD COLUMN min_value TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE subscriptions ADD COLUMN method_selectors TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE subscriptions DROP COLUMN type;
+
+ALTER TABLE subscriptions DROP COLUMN event;
@@ -0,0 +1,24 @@
+-- subscriptions are owned by tenants, the same address can be subscribed by more tenants,
+-- existing subscriptions belong to the default tenant with empty id
+CREATE TABLE tenant_subscriptions (
+    tenant_id        TEXT NOT NULL DEFAULT '',
+    address          TEXT NOT NULL,
+    direction        TEXT NOT NULL DEFAULT '',
+    kinds            TEXT NOT NULL DEFAULT '',
+    min_value        TEXT NOT NULL DEFAULT '',
+    method_selectors TEXT NOT NULL DEFAULT '',
+    created_at       BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, address)
+);
+
+INSERT INTO tenant_subscriptions (tenant_id, address, direction, kinds, min_value, method_selectors, created_at)
+SELECT '', address, direction, kinds, min_value, method_selectors, created_at FROM subscriptions;
+
+DROP TABLE subscriptions;
+
+ALTER TABLE tenant_subscriptions RENAME TO subscriptions;
+
+CREATE INDEX subscriptions_address_idx ON subscriptions (address);
+
+-- webhooks are owned by tenants, existing webhooks belong to the default tenant with empty id
+ALTER TABLE webhooks ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- api keys of tenants created via admin endpoint, keys configured at startup are not stored,
+-- rate_limit and burst of 0 use default rate limit, max_subscriptions of 0 uses default quota
+CREATE TABLE api_keys (
+    id                 TEXT PRIMARY KEY,
+    tenant_id          TEXT NOT NULL,
+    secret             TEXT NOT NULL UNIQUE,
+    rate_limit         DOUBLE PRECISION NOT NULL,
+    burst              INTEGER NOT NULL,
+    max_subscriptions  INTEGER NOT NULL,
+    signature_required INTEGER NOT NULL,
+    created_at         BIGINT NOT NULL
+);
@@ -0,0 +1,24 @@
+-- typed transaction fields, quantities are hex encoded, missing optional quantity is empty,
+-- access list is json and blob versioned hashes are comma separated list.
+-- transactions stored before this migration are read as legacy transactions without signature
+ALTER TABLE address_transactions ADD COLUMN tx_type TEXT NOT NULL DEFAULT '0x0';
+
+ALTER TABLE address_transactions ADD COLUMN chain_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN max_fee_per_gas TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN max_priority_fee_per_gas TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN access_list TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN max_fee_per_blob_gas TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN blob_versioned_hashes TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN v TEXT NOT NULL DEFAULT '0x0';
+
+ALTER TABLE address_transactions ADD COLUMN r TEXT NOT NULL DEFAULT '0x0';
+
+ALTER TABLE address_transactions ADD COLUMN s TEXT NOT NULL DEFAULT '0x0';
+
+ALTER TABLE address_transactions ADD COLUMN y_parity TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- receipt is json encoded, execution is succeeded or failed status of the receipt,
+-- transactions stored before this migration do not have receipt and their execution is empty
+ALTER TABLE address_transactions ADD COLUMN receipt TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address_transactions ADD COLUMN execution TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX address_transactions_address_execution_idx ON address_transactions (address, execution);
@@ -0,0 +1,24 @@
+-- token transfers are stored once per subscribed sender or recipient (address),
+-- token_id is empty for ERC-20 transfers and operator is empty for ERC-20 and ERC-721 transfers
+CREATE TABLE token_transfers (
+    address TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index BIGINT NOT NULL,
+    batch_index BIGINT NOT NULL,
+    standard TEXT NOT NULL,
+    token TEXT NOT NULL,
+    operator TEXT NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    value TEXT NOT NULL,
+    token_id TEXT NOT NULL,
+    transaction_hash TEXT NOT NULL,
+    transaction_index BIGINT NOT NULL,
+    block_hash TEXT NOT NULL,
+    block_timestamp BIGINT NOT NULL,
+    PRIMARY KEY (address, block_number, log_index, batch_index)
+);
+
+CREATE INDEX token_transfers_block_number_idx ON token_transfers (block_number);
+
+CREATE INDEX token_transfers_address_token_idx ON token_transfers (address, token);
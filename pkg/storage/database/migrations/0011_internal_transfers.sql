@@ -0,0 +1,22 @@
+-- internal transfers are value transfers of calls inside of transactions found by tracing,
+-- they are stored once per subscribed sender or recipient (address),
+-- trace_index is the index of the call frame in depth first order and trace_path is comma separated path of the call
+CREATE TABLE internal_transfers (
+    address TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    transaction_index BIGINT NOT NULL,
+    trace_index BIGINT NOT NULL,
+    trace_path TEXT NOT NULL,
+    call_type TEXT NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    value TEXT NOT NULL,
+    transaction_hash TEXT NOT NULL,
+    block_hash TEXT NOT NULL,
+    block_timestamp BIGINT NOT NULL,
+    PRIMARY KEY (address, block_number, transaction_index, trace_index)
+);
+
+CREATE INDEX internal_transfers_block_number_idx ON internal_transfers (block_number);
+
+CREATE INDEX internal_transfers_transaction_hash_idx ON internal_transfers (transaction_hash);
@@ -0,0 +1,26 @@
+-- direction is in, out or self relative to the address the record is stored for,
+-- direction of records stored before this migration is derived from their addresses,
+-- record which is not sent by the address is received by it, e.g. contract created at the address
+ALTER TABLE address_transactions ADD COLUMN direction TEXT NOT NULL DEFAULT '';
+
+UPDATE address_transactions SET direction = CASE
+    WHEN from_address = address AND to_address = address THEN 'self'
+    WHEN from_address = address THEN 'out'
+    ELSE 'in'
+END;
+
+ALTER TABLE token_transfers ADD COLUMN direction TEXT NOT NULL DEFAULT '';
+
+UPDATE token_transfers SET direction = CASE
+    WHEN from_address = address AND to_address = address THEN 'self'
+    WHEN from_address = address THEN 'out'
+    ELSE 'in'
+END;
+
+ALTER TABLE internal_transfers ADD COLUMN direction TEXT NOT NULL DEFAULT '';
+
+UPDATE internal_transfers SET direction = CASE
+    WHEN from_address = address AND to_address = address THEN 'self'
+    WHEN from_address = address THEN 'out'
+    ELSE 'in'
+END;
@@ -0,0 +1,162 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"go.etcd.io/bbolt"
+)
+
+// BoltStorage persists records in a bucket of bolt database.
+// Every key has its own nested bucket, records in it are keyed by their position,
+// so they are iterated in chain order and the same record is stored only once.
+type BoltStorage[K ~string, R Record] struct {
+	db     *bbolt.DB
+	bucket []byte
+}
+
+func NewBoltStorage[K ~string, R Record](db *bbolt.DB, bucket string) (*BoltStorage[K, R], error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltStorage[K, R]{
+		db:     db,
+		bucket: []byte(bucket),
+	}, nil
+}
+
+// Find returns records stored under the key after the cursor of the query which are accepted by match,
+// they are in query order and there is one record more than the page limit if there is a next page
+func (s *BoltStorage[K, R]) Find(ctx context.Context, key K, query Query, match func(R) bool) ([]R, error) {
+	matched := make([]R, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		keyBucket := tx.Bucket(s.bucket).Bucket([]byte(key))
+		if keyBucket == nil {
+			return nil
+		}
+		cursor := keyBucket.Cursor()
+		k, v := seekFirst(cursor, query)
+		for ; k != nil && len(matched) <= query.PageLimit(); k, v = nextInOrder(cursor, query) {
+			position := keyPosition(k)
+			if query.Descending() && query.FromBlock != nil && position.BlockNumber < *query.FromBlock {
+				break
+			}
+			if !query.Descending() && query.ToBlock != nil && position.BlockNumber > *query.ToBlock {
+				break
+			}
+			if !query.IsAfterCursor(position) {
+				continue
+			}
+			var record R
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			if match(record) {
+				matched = append(matched, record)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
+
+func (s *BoltStorage[K, R]) InsertBatch(ctx context.Context, data map[K][]R) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		for key, records := range data {
+			keyBucket, err := tx.Bucket(s.bucket).CreateBucketIfNotExists([]byte(key))
+			if err != nil {
+				return err
+			}
+			for _, record := range records {
+				value, err := json.Marshal(record)
+				if err != nil {
+					return err
+				}
+				if err := keyBucket.Put(positionKey(record.Position()), value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStorage[K, R]) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		return bucket.ForEachBucket(func(key []byte) error {
+			keyBucket := bucket.Bucket(key)
+			// collect keys first, deleting while iterating with cursor skips keys
+			var orphanedKeys [][]byte
+			cursor := keyBucket.Cursor()
+			for k, _ := cursor.Seek(positionKey(Position{BlockNumber: blockNumber})); k != nil; k, _ = cursor.Next() {
+				orphanedKeys = append(orphanedKeys, append([]byte(nil), k...))
+			}
+			for _, orphanedKey := range orphanedKeys {
+				if err := keyBucket.Delete(orphanedKey); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (s *BoltStorage[K, R]) Delete(ctx context.Context, key K) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket.Bucket([]byte(key)) == nil {
+			return nil
+		}
+		return bucket.DeleteBucket([]byte(key))
+	})
+}
+
+// seekFirst moves cursor to the first record in query order that can match the query
+func seekFirst(cursor *bbolt.Cursor, query Query) ([]byte, []byte) {
+	if query.Descending() {
+		var endKey []byte
+		if query.ToBlock != nil {
+			endKey = positionKey(Position{BlockNumber: *query.ToBlock + 1})
+		}
+		if query.After != nil && (endKey == nil || bytes.Compare(positionKey(*query.After), endKey) < 0) {
+			endKey = positionKey(*query.After)
+		}
+		if endKey == nil {
+			return cursor.Last()
+		}
+		// move to the last key before the end key
+		if k, _ := cursor.Seek(endKey); k == nil {
+			return cursor.Last()
+		}
+		return cursor.Prev()
+	}
+
+	var startKey []byte
+	if query.FromBlock != nil {
+		startKey = positionKey(Position{BlockNumber: *query.FromBlock})
+	}
+	if query.After != nil && bytes.Compare(positionKey(*query.After), startKey) > 0 {
+		startKey = positionKey(*query.After)
+	}
+	if startKey == nil {
+		return cursor.First()
+	}
+	return cursor.Seek(startKey)
+}
+
+// nextInOrder moves cursor to the next record in query order
+func nextInOrder(cursor *bbolt.Cursor, query Query) ([]byte, []byte) {
+	if query.Descending() {
+		return cursor.Prev()
+	}
+	return cursor.Next()
+}
@@ -0,0 +1,87 @@
+package record
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Position is the position of a record in the chain, records of a block are ordered by index and then by sub index.
+// Meaning of the indexes depends on the record, e.g. log index and index in the batch of a token transfer.
+type Position struct {
+	BlockNumber int64
+	Index       int64
+	SubIndex    int64
+}
+
+// Less returns true if position is before the other position in the chain
+func (p Position) Less(other Position) bool {
+	if p.BlockNumber != other.BlockNumber {
+		return p.BlockNumber < other.BlockNumber
+	}
+	if p.Index != other.Index {
+		return p.Index < other.Index
+	}
+	return p.SubIndex < other.SubIndex
+}
+
+// Cursor is an opaque representation of the position of the last record on a page
+func (p Position) Cursor() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%d", p.BlockNumber, p.Index, p.SubIndex)))
+}
+
+// ParseCursor parses position from the cursor
+func ParseCursor(cursor string) (*Position, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	var position Position
+	for i, number := range []*int64{&position.BlockNumber, &position.Index, &position.SubIndex} {
+		var ok bool
+		if *number, ok = parseCursorNumber(parts[i]); !ok {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &position, nil
+}
+
+// parseCursorNumber parses number of the cursor, negative numbers would overflow position key,
+// only numbers formatted by the cursor are accepted
+func parseCursorNumber(value string) (int64, bool) {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 || strconv.FormatInt(number, 10) != value {
+		return 0, false
+	}
+	return number, true
+}
+
+// positionKey returns key of the record ordered by block number, index and sub index
+func positionKey(position Position) []byte {
+	key := make([]byte, 24)
+	binary.BigEndian.PutUint64(key[:8], uint64(position.BlockNumber))
+	binary.BigEndian.PutUint64(key[8:16], uint64(position.Index))
+	binary.BigEndian.PutUint64(key[16:], uint64(position.SubIndex))
+	return key
+}
+
+// keyPosition returns position of the record from its key
+func keyPosition(key []byte) Position {
+	if len(key) < 24 {
+		return Position{}
+	}
+	return Position{
+		BlockNumber: int64(binary.BigEndian.Uint64(key[:8])),
+		Index:       int64(binary.BigEndian.Uint64(key[8:16])),
+		SubIndex:    int64(binary.BigEndian.Uint64(key[16:24])),
+	}
+}
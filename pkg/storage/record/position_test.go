@@ -0,0 +1,73 @@
+package record
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	for _, position := range []Position{
+		{},
+		{BlockNumber: 19000000, Index: 42, SubIndex: 3},
+		{BlockNumber: 1<<63 - 1, Index: 1<<63 - 1, SubIndex: 1<<63 - 1},
+	} {
+		cursor := position.Cursor()
+		parsed, err := ParseCursor(cursor)
+		if err != nil {
+			t.Fatalf("expected cursor %s of position %+v to be parsed, got %s", cursor, position, err)
+		}
+		if *parsed != position {
+			t.Fatalf("expected position %+v, got %+v", position, *parsed)
+		}
+	}
+}
+
+func TestParseInvalidCursor(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "10:2:1"},
+		{name: "transaction cursor", cursor: encode("10:2")},
+		{name: "trailing data", cursor: encode("10:2:1x")},
+		{name: "more numbers", cursor: encode("10:2:1:0")},
+		{name: "negative block number", cursor: encode("-1:2:1")},
+		{name: "negative sub index", cursor: encode("10:2:-1")},
+		{name: "leading zeros", cursor: encode("10:02:1")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if position, err := ParseCursor(test.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected invalid cursor error, got position %+v and error %v", position, err)
+			}
+		})
+	}
+}
+
+func TestPositionKeyOrder(t *testing.T) {
+	positions := []Position{
+		{BlockNumber: 1, Index: 0, SubIndex: 0},
+		{BlockNumber: 1, Index: 0, SubIndex: 1},
+		{BlockNumber: 1, Index: 1, SubIndex: 0},
+		{BlockNumber: 256, Index: 0, SubIndex: 0},
+	}
+	for i := 1; i < len(positions); i++ {
+		if !positions[i-1].Less(positions[i]) {
+			t.Fatalf("expected position %+v before %+v", positions[i-1], positions[i])
+		}
+		if bytes.Compare(positionKey(positions[i-1]), positionKey(positions[i])) >= 0 {
+			t.Fatalf("expected key of position %+v before key of %+v", positions[i-1], positions[i])
+		}
+	}
+	for _, position := range positions {
+		if parsed := keyPosition(positionKey(position)); parsed != position {
+			t.Fatalf("expected position %+v of its key, got %+v", position, parsed)
+		}
+	}
+}
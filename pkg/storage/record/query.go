@@ -0,0 +1,82 @@
+package record
+
+import (
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+// Record is a record of an address ordered by its position in the chain, e.g. token transfer or internal transfer
+type Record interface {
+	// Position returns position of the record in the chain
+	Position() Position
+	// RecordDirection returns direction of the record relative to the address
+	RecordDirection() transaction.RecordDirection
+}
+
+// Query is the part of a query of records which is common to all records, all filters are optional.
+// Limit, order and direction have the same meaning as in query of transactions.
+type Query struct {
+	// Limit is the maximum number of records on a page
+	Limit int
+	// After returns records after the position in query order, it is parsed from the cursor
+	After *Position
+	// Order is the order of records, ascending by default
+	Order transaction.Order
+	// Direction filters records received (inbound) or sent (outbound) by the address
+	Direction transaction.Direction
+	// FromBlock and ToBlock filter records by inclusive block range
+	FromBlock *int64
+	ToBlock   *int64
+}
+
+// PageLimit returns limit of the page, bounded by transaction.MaxLimit
+func (q Query) PageLimit() int {
+	if q.Limit <= 0 {
+		return transaction.DefaultLimit
+	}
+	if q.Limit > transaction.MaxLimit {
+		return transaction.MaxLimit
+	}
+	return q.Limit
+}
+
+// Descending returns true if records are ordered from the newest one
+func (q Query) Descending() bool {
+	return q.Order == transaction.DescendingOrder
+}
+
+// IsAfterCursor returns true if the position is after the cursor in query order
+func (q Query) IsAfterCursor(position Position) bool {
+	if q.After == nil {
+		return true
+	}
+	if q.Descending() {
+		return position.Less(*q.After)
+	}
+	return q.After.Less(position)
+}
+
+// Match returns true if the record matches direction and block range of the query
+func (q Query) Match(record Record) bool {
+	if !q.Direction.Match(record.RecordDirection()) {
+		return false
+	}
+	blockNumber := record.Position().BlockNumber
+	if q.FromBlock != nil && blockNumber < *q.FromBlock {
+		return false
+	}
+	if q.ToBlock != nil && blockNumber > *q.ToBlock {
+		return false
+	}
+	return true
+}
+
+// Paginate returns records of the page and cursor of the next page, which is empty on the last page.
+// Records have to be in query order and contain one record more than the limit if there is a next page.
+func Paginate[R Record](records []R, query Query) ([]R, string) {
+	limit := query.PageLimit()
+	if len(records) <= limit {
+		return records, ""
+	}
+	records = records[:limit]
+	return records, records[limit-1].Position().Cursor()
+}
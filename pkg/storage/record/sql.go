@@ -0,0 +1,54 @@
+package record
+
+import (
+	"fmt"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+// PositionColumns are columns of block number, index and sub index of the record position
+type PositionColumns [3]string
+
+// OrderBy returns order by clause of the columns in query order
+func (c PositionColumns) OrderBy(query Query) string {
+	order := "ASC"
+	if query.Descending() {
+		order = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s, %s %s", c[0], order, c[1], order, c[2], order)
+}
+
+// QueryConditions returns where conditions and their arguments of direction, cursor and block range of the query
+func QueryConditions(query Query, columns PositionColumns) ([]string, []any) {
+	var conditions []string
+	var args []any
+
+	if condition, directionArgs := transaction.DirectionCondition(query.Direction); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, directionArgs...)
+	}
+	if query.After != nil {
+		comparison := ">"
+		if query.Descending() {
+			comparison = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf(
+			"(%[1]s %[4]s ? OR (%[1]s = ? AND (%[2]s %[4]s ? OR (%[2]s = ? AND %[3]s %[4]s ?))))",
+			columns[0], columns[1], columns[2], comparison,
+		))
+		args = append(args,
+			query.After.BlockNumber,
+			query.After.BlockNumber, query.After.Index,
+			query.After.Index, query.After.SubIndex,
+		)
+	}
+	if query.FromBlock != nil {
+		conditions = append(conditions, columns[0]+" >= ?")
+		args = append(args, *query.FromBlock)
+	}
+	if query.ToBlock != nil {
+		conditions = append(conditions, columns[0]+" <= ?")
+		args = append(args, *query.ToBlock)
+	}
+	return conditions, args
+}
@@ -0,0 +1,80 @@
+package record
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStorage stores records of keys in memory, record is stored under a key once at its position
+type MemoryStorage[K ~string, R Record] struct {
+	records map[K]map[Position]R
+	mutex   sync.RWMutex
+}
+
+func NewMemoryStorage[K ~string, R Record]() *MemoryStorage[K, R] {
+	return &MemoryStorage[K, R]{
+		records: make(map[K]map[Position]R),
+	}
+}
+
+// Find returns records stored under the key after the cursor of the query which are accepted by match,
+// they are in query order and there is one record more than the page limit if there is a next page
+func (s *MemoryStorage[K, R]) Find(ctx context.Context, key K, query Query, match func(R) bool) ([]R, error) {
+	s.mutex.RLock()
+	matched := make([]R, 0)
+	for position, record := range s.records[key] {
+		if query.IsAfterCursor(position) && match(record) {
+			matched = append(matched, record)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if query.Descending() {
+			return matched[j].Position().Less(matched[i].Position())
+		}
+		return matched[i].Position().Less(matched[j].Position())
+	})
+	if len(matched) > query.PageLimit()+1 {
+		matched = matched[:query.PageLimit()+1]
+	}
+	return matched, nil
+}
+
+func (s *MemoryStorage[K, R]) InsertBatch(ctx context.Context, data map[K][]R) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, records := range data {
+		if _, ok := s.records[key]; !ok {
+			s.records[key] = make(map[Position]R)
+		}
+		for _, record := range records {
+			s.records[key][record.Position()] = record
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStorage[K, R]) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, records := range s.records {
+		for position := range records {
+			if position.BlockNumber >= blockNumber {
+				delete(records, position)
+			}
+		}
+		if len(records) == 0 {
+			delete(s.records, key)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStorage[K, R]) Delete(ctx context.Context, key K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.records, key)
+	return nil
+}
@@ -0,0 +1,21 @@
+package trace
+
+import (
+	"go.etcd.io/bbolt"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+)
+
+const transfersBucket = "internal_transfers"
+
+// NewBoltStorage creates storage which persists internal transfers in bolt database,
+// every key has its own nested bucket in which internal transfers are keyed by their position
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	boltStorage, err := record.NewBoltStorage[AddressTransferID, *AddressTransfer](db, transfersBucket)
+	if err != nil {
+		return nil, err
+	}
+	return &storage{
+		recordStorage: boltStorage,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package trace
+
+import (
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+)
+
+// Position is the position of an internal transfer in the chain, index is the transaction index and sub index is the trace index,
+// internal transfers of a transaction are ordered by index of their call frame in depth first order
+type Position = record.Position
+
+// Query is a query of internal transfers of an address, all filters are optional
+type Query struct {
+	record.Query
+}
+
+// Match returns true if internal transfer of the address matches all filters of the query except cursor
+func (q Query) Match(address blockchain.Address, addressTransfer *AddressTransfer) bool {
+	if addressTransfer.Transfer == nil {
+		return false
+	}
+	return q.Query.Match(addressTransfer)
+}
+
+// Page is a page of internal transfers
+type Page struct {
+	Transfers []*AddressTransfer
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string
+}
+
+// NewPage creates a page from internal transfers matching the query,
+// internal transfers have to be in query order and contain one internal transfer more than the limit if there is a next page
+func NewPage(transfers []*AddressTransfer, query Query) *Page {
+	transfers, nextCursor := record.Paginate(transfers, query.Query)
+	return &Page{
+		Transfers:  transfers,
+		NextCursor: nextCursor,
+	}
+}
@@ -0,0 +1,118 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+// ReadOnlyRepository is responsible for reading internal transfers
+type ReadOnlyRepository interface {
+	// GetInternalTransfers returns page of inbound or outbound internal transfers for an address matching the query
+	GetInternalTransfers(ctx context.Context, address blockchain.Address, query Query) (*Page, error)
+}
+
+// WriteRepository is responsible for writing internal transfers
+type WriteRepository interface {
+	InsertInternalTransfers(ctx context.Context, transfers []*AddressTransfer) error
+	// DeleteInternalTransfersFromBlock deletes internal transfers of given block or any block after it,
+	// it is used to roll back internal transfers from orphaned blocks after reorg
+	DeleteInternalTransfersFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error
+	// DeleteInternalTransfers deletes all internal transfers for an address
+	DeleteInternalTransfers(ctx context.Context, address blockchain.Address) error
+}
+
+// Repository is responsible for reading and writing internal transfers
+type Repository interface {
+	ReadOnlyRepository
+	WriteRepository
+}
+
+var _ Repository = (*repository)(nil)
+
+type repository struct {
+	storage Storage
+}
+
+func NewRepository(storage Storage) Repository {
+	return &repository{
+		storage: storage,
+	}
+}
+
+func (r *repository) GetInternalTransfers(ctx context.Context, address blockchain.Address, query Query) (*Page, error) {
+	return r.storage.Get(ctx, NewAddressTransferID(address), query)
+}
+
+func (r *repository) InsertInternalTransfers(ctx context.Context, addressTransfers []*AddressTransfer) error {
+	data := make(map[AddressTransferID][]*AddressTransfer)
+	for _, addressTransfer := range addressTransfers {
+		data[addressTransfer.ID] = append(data[addressTransfer.ID], addressTransfer)
+	}
+	return r.storage.InsertBatch(ctx, data)
+}
+
+func (r *repository) DeleteInternalTransfersFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	return r.storage.DeleteFromBlock(ctx, blockNumber.ToInt64())
+}
+
+func (r *repository) DeleteInternalTransfers(ctx context.Context, address blockchain.Address) error {
+	return r.storage.Delete(ctx, NewAddressTransferID(address))
+}
+
+// AddressTransferID is a unique identifier for internal transfers of an address
+type AddressTransferID string
+
+func NewAddressTransferID(
+	address blockchain.Address,
+) AddressTransferID {
+	return AddressTransferID(address)
+}
+
+func (a AddressTransferID) String() string {
+	return string(a)
+}
+
+// Address returns address of the internal transfer
+func (a AddressTransferID) Address() blockchain.Address {
+	return blockchain.Address(a)
+}
+
+// AddressTransfer is an internal transfer sent from or to an address
+type AddressTransfer struct {
+	ID       AddressTransferID            `json:"id"`
+	Transfer *blockchain.InternalTransfer `json:"transfer"`
+	// Direction is the direction of the internal transfer relative to the address
+	Direction transaction.RecordDirection `json:"direction"`
+	// BlockTimestamp is unix timestamp of the block that includes the parent transaction
+	BlockTimestamp int64 `json:"blockTimestamp"`
+}
+
+// NewAddressTransfer creates internal transfer of the address with its direction relative to the address
+func NewAddressTransfer(address blockchain.Address, transfer *blockchain.InternalTransfer, blockTimestamp int64) *AddressTransfer {
+	return &AddressTransfer{
+		ID:             NewAddressTransferID(address),
+		Transfer:       transfer,
+		Direction:      transaction.NewRecordDirection(address, transfer.From, transfer.To),
+		BlockTimestamp: blockTimestamp,
+	}
+}
+
+// RecordDirection returns direction of the internal transfer relative to the address,
+// it is derived from addresses of the internal transfer when it is not set
+func (a *AddressTransfer) RecordDirection() transaction.RecordDirection {
+	if a.Direction != "" {
+		return a.Direction
+	}
+	return transaction.NewRecordDirection(a.ID.Address(), a.Transfer.From, a.Transfer.To)
+}
+
+// Position returns position of the internal transfer in the chain
+func (a *AddressTransfer) Position() Position {
+	return Position{
+		BlockNumber: a.Transfer.BlockNumber.Int64(),
+		Index:       a.Transfer.TransactionIndex.Int64(),
+		SubIndex:    a.Transfer.TraceIndex,
+	}
+}
@@ -0,0 +1,207 @@
+package trace
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+var _ Storage = (*sqlStorage)(nil)
+
+// positionColumns are columns of the position, transaction_index is its index and trace_index its sub index
+var positionColumns = record.PositionColumns{"block_number", "transaction_index", "trace_index"}
+
+// sqlStorage stores internal transfers as normalized rows,
+// indexed by address (key), block number and parent transaction hash
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+func (s *sqlStorage) Get(ctx context.Context, key AddressTransferID, query Query) (*Page, error) {
+	conditions, args := queryConditions(key, query)
+	statement := fmt.Sprintf(`
+		SELECT block_number, transaction_index, trace_index, trace_path, call_type, from_address, to_address, value,
+			transaction_hash, block_hash, block_timestamp, direction
+		FROM internal_transfers
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?`,
+		strings.Join(conditions, " AND "), positionColumns.OrderBy(query.Query),
+	)
+	args = append(args, query.PageLimit()+1)
+
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(statement), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]*AddressTransfer, 0)
+	for rows.Next() {
+		var row transferRow
+		var blockNumber, transactionIndex, traceIndex, blockTimestamp int64
+		var direction string
+		err := rows.Scan(
+			&blockNumber,
+			&transactionIndex,
+			&traceIndex,
+			&row.tracePath,
+			&row.callType,
+			&row.from,
+			&row.to,
+			&row.value,
+			&row.transactionHash,
+			&row.blockHash,
+			&blockTimestamp,
+			&direction,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transfer, err := row.transfer()
+		if err != nil {
+			return nil, err
+		}
+		transfer.BlockNumber = blockchain.QuantityFromInt64(blockNumber)
+		transfer.TransactionIndex = blockchain.QuantityFromInt64(transactionIndex)
+		transfer.TraceIndex = traceIndex
+		transfers = append(transfers, &AddressTransfer{
+			ID:             key,
+			Transfer:       transfer,
+			Direction:      transaction.RecordDirection(direction),
+			BlockTimestamp: blockTimestamp,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return NewPage(transfers, query), nil
+}
+
+// queryConditions returns where conditions and their arguments of the query
+func queryConditions(key AddressTransferID, query Query) ([]string, []any) {
+	conditions := []string{"address = ?"}
+	args := []any{key.String()}
+
+	positionConditions, positionArgs := record.QueryConditions(query.Query, positionColumns)
+	conditions = append(conditions, positionConditions...)
+	args = append(args, positionArgs...)
+	return conditions, args
+}
+
+func (s *sqlStorage) InsertBatch(ctx context.Context, data map[AddressTransferID][]*AddressTransfer) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.PrepareContext(ctx, s.db.Rebind(`
+		INSERT INTO internal_transfers (
+			address, block_number, transaction_index, trace_index, trace_path, call_type, from_address, to_address, value,
+			transaction_hash, block_hash, block_timestamp, direction
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (address, block_number, transaction_index, trace_index) DO NOTHING`),
+	)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for key, addressTransfers := range data {
+		for _, addressTransfer := range addressTransfers {
+			transfer := addressTransfer.Transfer
+			position := addressTransfer.Position()
+			_, err = statement.ExecContext(ctx,
+				key.String(),
+				position.BlockNumber,
+				position.Index,
+				position.SubIndex,
+				joinTracePath(transfer.TracePath),
+				string(transfer.Type),
+				transfer.From.String(),
+				transfer.To.String(),
+				transfer.Value.String(),
+				transfer.TransactionHash.String(),
+				transfer.BlockHash.String(),
+				addressTransfer.BlockTimestamp,
+				string(addressTransfer.RecordDirection()),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM internal_transfers WHERE block_number >= ?`), blockNumber)
+	return err
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, key AddressTransferID) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM internal_transfers WHERE address = ?`), key.String())
+	return err
+}
+
+// transferRow are hex encoded columns of stored internal transfer
+type transferRow struct {
+	callType, from, to, value, transactionHash, blockHash string
+	// tracePath is comma separated list of call indexes, it is empty for the top level call
+	tracePath string
+}
+
+// transfer parses columns of the row, it fails on invalid hex values
+func (r *transferRow) transfer() (*blockchain.InternalTransfer, error) {
+	transfer := blockchain.InternalTransfer{
+		Type:      blockchain.CallType(r.callType),
+		TracePath: make([]int, 0),
+	}
+	for _, column := range []struct {
+		value  string
+		target encoding.TextUnmarshaler
+	}{
+		{r.from, &transfer.From},
+		{r.to, &transfer.To},
+		{r.value, &transfer.Value},
+		{r.transactionHash, &transfer.TransactionHash},
+		{r.blockHash, &transfer.BlockHash},
+	} {
+		if err := column.target.UnmarshalText([]byte(column.value)); err != nil {
+			return nil, err
+		}
+	}
+	if r.tracePath != "" {
+		for _, value := range strings.Split(r.tracePath, ",") {
+			index, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, err
+			}
+			transfer.TracePath = append(transfer.TracePath, index)
+		}
+	}
+	return &transfer, nil
+}
+
+// joinTracePath joins trace path to comma separated list
+func joinTracePath(tracePath []int) string {
+	values := make([]string, 0, len(tracePath))
+	for _, index := range tracePath {
+		values = append(values, strconv.Itoa(index))
+	}
+	return strings.Join(values, ",")
+}
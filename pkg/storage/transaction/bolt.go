@@ -0,0 +1,227 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+var transactionsBucket = []byte("transactions")
+
+var _ Storage = (*boltStorage)(nil)
+
+// boltStorage persists transactions in bolt database.
+// Every key has its own nested bucket, transactions in it are keyed by block number and transaction index,
+// so they are iterated in chain order and the same transaction is stored only once.
+type boltStorage struct {
+	db *bbolt.DB
+}
+
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(transactionsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStorage{
+		db: db,
+	}, nil
+}
+
+func (s *boltStorage) Get(ctx context.Context, key AddressTransactionID, query Query) (*Page, error) {
+	matched := make([]*AddressTransaction, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		keyBucket := tx.Bucket(transactionsBucket).Bucket([]byte(key))
+		if keyBucket == nil {
+			return nil
+		}
+		cursor := keyBucket.Cursor()
+		k, v := seekFirst(cursor, query)
+		for ; k != nil && len(matched) <= query.PageLimit(); k, v = nextInOrder(cursor, query) {
+			position := keyPosition(k)
+			if query.Descending() && query.FromBlock != nil && position.BlockNumber < *query.FromBlock {
+				break
+			}
+			if !query.Descending() && query.ToBlock != nil && position.BlockNumber > *query.ToBlock {
+				break
+			}
+			if !query.IsAfterCursor(position) {
+				continue
+			}
+			transaction, err := decodeTransaction(key, v)
+			if err != nil {
+				return err
+			}
+			if query.Match(key.Address(), transaction) {
+				matched = append(matched, transaction)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewPage(matched, query), nil
+}
+
+func (s *boltStorage) Count(ctx context.Context, key AddressTransactionID, query Query) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		keyBucket := tx.Bucket(transactionsBucket).Bucket([]byte(key))
+		if keyBucket == nil {
+			return nil
+		}
+		return keyBucket.ForEach(func(_, v []byte) error {
+			transaction, err := decodeTransaction(key, v)
+			if err != nil {
+				return err
+			}
+			if query.Match(key.Address(), transaction) {
+				count++
+			}
+			return nil
+		})
+	})
+	return count, err
+}
+
+func (s *boltStorage) InsertBatch(ctx context.Context, data map[AddressTransactionID][]*AddressTransaction) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		for key, transactions := range data {
+			keyBucket, err := tx.Bucket(transactionsBucket).CreateBucketIfNotExists([]byte(key))
+			if err != nil {
+				return err
+			}
+			for _, transaction := range transactions {
+				value, err := json.Marshal(transaction)
+				if err != nil {
+					return err
+				}
+				if err := keyBucket.Put(positionKey(transaction.Position()), value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStorage) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(transactionsBucket)
+		return bucket.ForEachBucket(func(key []byte) error {
+			keyBucket := bucket.Bucket(key)
+			// collect keys first, deleting while iterating with cursor skips keys
+			var orphanedKeys [][]byte
+			cursor := keyBucket.Cursor()
+			for k, _ := cursor.Seek(positionKey(Position{BlockNumber: blockNumber})); k != nil; k, _ = cursor.Next() {
+				orphanedKeys = append(orphanedKeys, append([]byte(nil), k...))
+			}
+			for _, orphanedKey := range orphanedKeys {
+				if err := keyBucket.Delete(orphanedKey); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (s *boltStorage) Delete(ctx context.Context, key AddressTransactionID) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(transactionsBucket)
+		if bucket.Bucket([]byte(key)) == nil {
+			return nil
+		}
+		return bucket.DeleteBucket([]byte(key))
+	})
+}
+
+// seekFirst moves cursor to the first transaction in query order that can match the query
+func seekFirst(cursor *bbolt.Cursor, query Query) ([]byte, []byte) {
+	if query.Descending() {
+		var endKey []byte
+		if query.ToBlock != nil {
+			endKey = positionKey(Position{BlockNumber: *query.ToBlock + 1})
+		}
+		if query.After != nil && (endKey == nil || bytes.Compare(positionKey(*query.After), endKey) < 0) {
+			endKey = positionKey(*query.After)
+		}
+		if endKey == nil {
+			return cursor.Last()
+		}
+		// move to the last key before the end key
+		if k, _ := cursor.Seek(endKey); k == nil {
+			return cursor.Last()
+		}
+		return cursor.Prev()
+	}
+
+	var startKey []byte
+	if query.FromBlock != nil {
+		startKey = positionKey(Position{BlockNumber: *query.FromBlock})
+	}
+	if query.After != nil && bytes.Compare(positionKey(*query.After), startKey) > 0 {
+		startKey = positionKey(*query.After)
+	}
+	if startKey == nil {
+		return cursor.First()
+	}
+	return cursor.Seek(startKey)
+}
+
+// nextInOrder moves cursor to the next transaction in query order
+func nextInOrder(cursor *bbolt.Cursor, query Query) ([]byte, []byte) {
+	if query.Descending() {
+		return cursor.Prev()
+	}
+	return cursor.Next()
+}
+
+// decodeTransaction decodes stored transaction,
+// transactions stored before block timestamp was added are stored as plain blockchain transaction
+// and direction of transactions stored before it was added is derived from their addresses
+func decodeTransaction(key AddressTransactionID, value []byte) (*AddressTransaction, error) {
+	var transaction AddressTransaction
+	if err := json.Unmarshal(value, &transaction); err != nil {
+		return nil, err
+	}
+	if transaction.Transaction == nil {
+		var legacyTransaction blockchain.Transaction
+		if err := json.Unmarshal(value, &legacyTransaction); err != nil {
+			return nil, err
+		}
+		transaction.ID = key
+		transaction.Transaction = &legacyTransaction
+	}
+	if transaction.Direction == "" {
+		transaction.Direction = NewRecordDirection(key.Address(), transaction.Transaction.From, transaction.Transaction.To)
+	}
+	return &transaction, nil
+}
+
+// positionKey returns key of the transaction ordered by block number and transaction index
+func positionKey(position Position) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(position.BlockNumber))
+	binary.BigEndian.PutUint64(key[8:], uint64(position.TransactionIndex))
+	return key
+}
+
+// keyPosition returns position of the transaction from its key
+func keyPosition(key []byte) Position {
+	if len(key) < 16 {
+		return Position{}
+	}
+	return Position{
+		BlockNumber:      int64(binary.BigEndian.Uint64(key[:8])),
+		TransactionIndex: int64(binary.BigEndian.Uint64(key[8:16])),
+	}
+}
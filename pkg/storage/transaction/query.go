@@ -0,0 +1,238 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+const (
+	// DefaultLimit is the number of transactions returned when limit is not set
+	DefaultLimit = 100
+	// MaxLimit is the maximum number of transactions returned in a single page
+	MaxLimit = 1000
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Order is the order of transactions by block number and transaction index
+type Order string
+
+const (
+	AscendingOrder  = Order("asc")
+	DescendingOrder = Order("desc")
+)
+
+// Direction is the direction of transaction relative to the address
+type Direction string
+
+const (
+	// InboundDirection transaction is sent to the address
+	InboundDirection = Direction("inbound")
+	// OutboundDirection transaction is sent from the address
+	OutboundDirection = Direction("outbound")
+	// SelfDirection transaction is sent from the address to itself, it is also inbound and outbound
+	SelfDirection = Direction("self")
+)
+
+// Match returns true if record of the direction is in query direction, every record matches empty direction
+func (d Direction) Match(direction RecordDirection) bool {
+	switch d {
+	case InboundDirection:
+		return direction.Inbound()
+	case OutboundDirection:
+		return direction.Outbound()
+	case SelfDirection:
+		return direction == SelfRecordDirection
+	}
+	return true
+}
+
+// Execution is the execution status of transaction from its receipt
+type Execution string
+
+const (
+	// SucceededExecution transaction was executed successfully
+	SucceededExecution = Execution("succeeded")
+	// FailedExecution transaction was reverted, it paid for gas but its state changes were discarded
+	FailedExecution = Execution("failed")
+)
+
+// Position is the position of a transaction in the chain
+type Position struct {
+	BlockNumber      int64
+	TransactionIndex int64
+}
+
+// Less returns true if position is before the other position in the chain
+func (p Position) Less(other Position) bool {
+	if p.BlockNumber != other.BlockNumber {
+		return p.BlockNumber < other.BlockNumber
+	}
+	return p.TransactionIndex < other.TransactionIndex
+}
+
+// Cursor is an opaque representation of the position of the last transaction on a page
+func (p Position) Cursor() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", p.BlockNumber, p.TransactionIndex)))
+}
+
+// ParseCursor parses position from the cursor
+func ParseCursor(cursor string) (*Position, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return ParsePosition(string(decoded))
+}
+
+// ParsePosition parses position from its decoded cursor "blockNumber:transactionIndex"
+func ParsePosition(value string) (*Position, error) {
+	blockNumber, transactionIndex, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	var position Position
+	if position.BlockNumber, ok = parseCursorNumber(blockNumber); !ok {
+		return nil, ErrInvalidCursor
+	}
+	if position.TransactionIndex, ok = parseCursorNumber(transactionIndex); !ok {
+		return nil, ErrInvalidCursor
+	}
+	return &position, nil
+}
+
+// parseCursorNumber parses number of the cursor, only numbers formatted by the cursor are accepted,
+// so cursor with sign, leading zeros or trailing data is rejected
+func parseCursorNumber(value string) (int64, bool) {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 || strconv.FormatInt(number, 10) != value {
+		return 0, false
+	}
+	return number, true
+}
+
+// Query is a query of transactions of an address, all filters are optional
+type Query struct {
+	// Limit is the maximum number of transactions on a page
+	Limit int
+	// After returns transactions after the position in query order, it is parsed from the cursor
+	After *Position
+	// Order is the order of transactions, ascending by default
+	Order Order
+	// Direction filters inbound, outbound or self transactions, self transactions are also inbound and outbound
+	Direction Direction
+	// FromBlock and ToBlock filter transactions by inclusive block range
+	FromBlock *int64
+	ToBlock   *int64
+	// FromTime and ToTime filter transactions by inclusive range of block unix timestamps
+	FromTime *int64
+	ToTime   *int64
+	// MinValue and MaxValue filter transactions by inclusive range of value in wei
+	MinValue *big.Int
+	MaxValue *big.Int
+	// Counterparty filters transactions sent to or received from the address
+	Counterparty blockchain.Address
+	// Execution filters succeeded or failed transactions, transactions without receipt do not match it
+	Execution Execution
+}
+
+// PageLimit returns limit of the page, bounded by MaxLimit
+func (q Query) PageLimit() int {
+	if q.Limit <= 0 {
+		return DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		return MaxLimit
+	}
+	return q.Limit
+}
+
+// Descending returns true if transactions are ordered from the newest one
+func (q Query) Descending() bool {
+	return q.Order == DescendingOrder
+}
+
+// IsAfterCursor returns true if the position is after the cursor in query order
+func (q Query) IsAfterCursor(position Position) bool {
+	if q.After == nil {
+		return true
+	}
+	if q.Descending() {
+		return position.Less(*q.After)
+	}
+	return q.After.Less(position)
+}
+
+// Match returns true if transaction of the address matches all filters of the query except cursor
+func (q Query) Match(address blockchain.Address, transaction *AddressTransaction) bool {
+	if transaction.Transaction == nil {
+		return false
+	}
+	tx := transaction.Transaction
+	position := transaction.Position()
+	direction := transaction.RecordDirection()
+
+	if !q.Direction.Match(direction) {
+		return false
+	}
+	if q.FromBlock != nil && position.BlockNumber < *q.FromBlock {
+		return false
+	}
+	if q.ToBlock != nil && position.BlockNumber > *q.ToBlock {
+		return false
+	}
+	if q.FromTime != nil && transaction.BlockTimestamp < *q.FromTime {
+		return false
+	}
+	if q.ToTime != nil && transaction.BlockTimestamp > *q.ToTime {
+		return false
+	}
+	if q.MinValue != nil || q.MaxValue != nil {
+		value := transaction.ValueWei()
+		if q.MinValue != nil && value.Cmp(q.MinValue) < 0 {
+			return false
+		}
+		if q.MaxValue != nil && value.Cmp(q.MaxValue) > 0 {
+			return false
+		}
+	}
+	if !q.Counterparty.IsZero() {
+		counterparty := q.Counterparty
+		if !(direction.Outbound() && tx.To == counterparty) && !(direction.Inbound() && tx.From == counterparty) {
+			return false
+		}
+	}
+	if q.Execution != "" && transaction.Execution() != q.Execution {
+		return false
+	}
+	return true
+}
+
+// Page is a page of transactions
+type Page struct {
+	Transactions []*AddressTransaction
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string
+}
+
+// NewPage creates a page from transactions matching the query,
+// transactions have to be in query order and contain one transaction more than the limit if there is a next page
+func NewPage(transactions []*AddressTransaction, query Query) *Page {
+	limit := query.PageLimit()
+	if len(transactions) <= limit {
+		return &Page{
+			Transactions: transactions,
+		}
+	}
+	transactions = transactions[:limit]
+	return &Page{
+		Transactions: transactions,
+		NextCursor:   transactions[limit-1].Position().Cursor(),
+	}
+}
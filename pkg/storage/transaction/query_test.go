@@ -0,0 +1,55 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	for _, position := range []Position{
+		{BlockNumber: 0, TransactionIndex: 0},
+		{BlockNumber: 19000000, TransactionIndex: 42},
+		{BlockNumber: 1<<63 - 1, TransactionIndex: 1<<63 - 1},
+	} {
+		cursor := position.Cursor()
+		parsed, err := ParseCursor(cursor)
+		if err != nil {
+			t.Fatalf("expected cursor %s of position %+v to be parsed, got %s", cursor, position, err)
+		}
+		if *parsed != position {
+			t.Fatalf("expected position %+v, got %+v", position, *parsed)
+		}
+	}
+}
+
+func TestParseInvalidCursor(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "10:2"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte("10:2"))},
+		{name: "missing index", cursor: encode("10")},
+		{name: "empty index", cursor: encode("10:")},
+		{name: "trailing data", cursor: encode("10:2x")},
+		{name: "stream cursor", cursor: encode("10:2:0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")},
+		{name: "negative block number", cursor: encode("-10:2")},
+		{name: "negative index", cursor: encode("10:-2")},
+		{name: "signed block number", cursor: encode("+10:2")},
+		{name: "leading zeros", cursor: encode("010:2")},
+		{name: "spaces", cursor: encode("10: 2")},
+		{name: "overflow", cursor: encode("9223372036854775808:2")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if position, err := ParseCursor(test.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected invalid cursor error, got position %+v and error %v", position, err)
+			}
+		})
+	}
+}
@@ -2,22 +2,27 @@ package transaction
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"math/big"
 
 	"github.com/veljkomatic/be-homework/pkg/blockchain"
 )
 
 // ReadOnlyRepository is responsible for reading transactions
 type ReadOnlyRepository interface {
-	// GetTransactions list of inbound or outbound transactions for an address
-	// TODO Add pagination and filtering in the future
-	GetTransactions(ctx context.Context, address string) ([]*blockchain.Transaction, error)
+	// GetTransactions returns page of inbound or outbound transactions for an address matching the query
+	GetTransactions(ctx context.Context, address blockchain.Address, query Query) (*Page, error)
+	// CountTransactions returns number of transactions for an address matching the query, cursor and limit are ignored
+	CountTransactions(ctx context.Context, address blockchain.Address, query Query) (int, error)
 }
 
 // WriteRepository is responsible for writing transactions
 type WriteRepository interface {
 	InsertTransactions(ctx context.Context, transactions []*AddressTransaction) error
+	// DeleteTransactionsFromBlock deletes transactions included in given block or any block after it,
+	// it is used to roll back transactions from orphaned blocks after reorg
+	DeleteTransactionsFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error
+	// DeleteTransactions deletes all transactions for an address
+	DeleteTransactions(ctx context.Context, address blockchain.Address) error
 }
 
 // Repository is responsible for reading and writing transactions
@@ -38,37 +43,138 @@ func NewRepository(storage Storage) Repository {
 	}
 }
 
-func (r *repository) GetTransactions(ctx context.Context, address string) ([]*blockchain.Transaction, error) {
-	return r.storage.Get(ctx, NewAddressTransactionID(address).String())
+func (r *repository) GetTransactions(ctx context.Context, address blockchain.Address, query Query) (*Page, error) {
+	return r.storage.Get(ctx, NewAddressTransactionID(address), query)
+}
+
+func (r *repository) CountTransactions(ctx context.Context, address blockchain.Address, query Query) (int, error) {
+	return r.storage.Count(ctx, NewAddressTransactionID(address), query)
 }
 
 func (r *repository) InsertTransactions(ctx context.Context, addressTransactions []*AddressTransaction) error {
-	data := make(map[string][]*blockchain.Transaction)
+	data := make(map[AddressTransactionID][]*AddressTransaction)
 	for _, addressTransaction := range addressTransactions {
-		data[addressTransaction.ID.String()] = append(data[addressTransaction.ID.String()], addressTransaction.Transaction)
+		data[addressTransaction.ID] = append(data[addressTransaction.ID], addressTransaction)
 	}
 	return r.storage.InsertBatch(ctx, data)
 }
 
+func (r *repository) DeleteTransactionsFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	return r.storage.DeleteFromBlock(ctx, blockNumber.ToInt64())
+}
+
+func (r *repository) DeleteTransactions(ctx context.Context, address blockchain.Address) error {
+	return r.storage.Delete(ctx, NewAddressTransactionID(address))
+}
+
 // AddressTransactionID is a unique identifier for address transaction
 // it can be more complex, but for the sake of simplicity, I will use only address
 type AddressTransactionID string
 
 func NewAddressTransactionID(
-	address string,
+	address blockchain.Address,
 ) AddressTransactionID {
-	return AddressTransactionID(
-		fmt.Sprintf("%s", strings.ToLower(address)),
-	)
+	return AddressTransactionID(address)
 }
 
 func (a AddressTransactionID) String() string {
 	return string(a)
 }
 
+// Address returns address of the transaction
+func (a AddressTransactionID) Address() blockchain.Address {
+	return blockchain.Address(a)
+}
+
+// RecordDirection is the direction of stored record relative to the address it is stored for
+type RecordDirection string
+
+const (
+	// InRecordDirection record is received by the address, including contract created at the address
+	InRecordDirection = RecordDirection("in")
+	// OutRecordDirection record is sent by the address
+	OutRecordDirection = RecordDirection("out")
+	// SelfRecordDirection record is sent by the address to itself, it is stored once
+	SelfRecordDirection = RecordDirection("self")
+)
+
+// NewRecordDirection returns direction of record sent from one address to another relative to the address,
+// record which is not sent by the address is received by it, e.g. contract created at the address has no recipient
+func NewRecordDirection(address, from, to blockchain.Address) RecordDirection {
+	switch {
+	case from == address && to == address:
+		return SelfRecordDirection
+	case from == address:
+		return OutRecordDirection
+	}
+	return InRecordDirection
+}
+
+// Inbound returns true if the record is received by the address
+func (d RecordDirection) Inbound() bool {
+	return d == InRecordDirection || d == SelfRecordDirection
+}
+
+// Outbound returns true if the record is sent by the address
+func (d RecordDirection) Outbound() bool {
+	return d == OutRecordDirection || d == SelfRecordDirection
+}
+
 // AddressTransaction is a representation of address transaction
 // It will be converted to some model representation of transaction and stored in storage
 type AddressTransaction struct {
 	ID          AddressTransactionID    `json:"id"`
 	Transaction *blockchain.Transaction `json:"transaction"`
+	// Direction is the direction of the transaction relative to the address
+	Direction RecordDirection `json:"direction"`
+	// BlockTimestamp is unix timestamp of the block that includes transaction
+	BlockTimestamp int64 `json:"blockTimestamp"`
+	// Receipt is missing when it could not be fetched or transaction was stored before receipts were fetched
+	Receipt *blockchain.Receipt `json:"receipt,omitempty"`
+}
+
+// NewAddressTransaction creates transaction of the address with its direction relative to the address
+func NewAddressTransaction(address blockchain.Address, tx *blockchain.Transaction, blockTimestamp int64) *AddressTransaction {
+	return &AddressTransaction{
+		ID:             NewAddressTransactionID(address),
+		Transaction:    tx,
+		Direction:      NewRecordDirection(address, tx.From, tx.To),
+		BlockTimestamp: blockTimestamp,
+	}
+}
+
+// Position returns position of the transaction in the chain
+func (a *AddressTransaction) Position() Position {
+	return Position{
+		BlockNumber:      a.Transaction.BlockNumber.Int64(),
+		TransactionIndex: a.Transaction.TransactionIndex.Int64(),
+	}
+}
+
+// RecordDirection returns direction of the transaction relative to the address,
+// it is derived from addresses of the transaction when it is not set
+func (a *AddressTransaction) RecordDirection() RecordDirection {
+	if a.Direction != "" {
+		return a.Direction
+	}
+	return NewRecordDirection(a.ID.Address(), a.Transaction.From, a.Transaction.To)
+}
+
+// Execution returns execution status of the transaction from its receipt,
+// it is empty when receipt is missing or does not have status
+func (a *AddressTransaction) Execution() Execution {
+	switch {
+	case a.Receipt == nil:
+		return ""
+	case a.Receipt.Succeeded():
+		return SucceededExecution
+	case a.Receipt.Failed():
+		return FailedExecution
+	}
+	return ""
+}
+
+// ValueWei returns value of the transaction in wei
+func (a *AddressTransaction) ValueWei() *big.Int {
+	return a.Transaction.ValueWei()
 }
@@ -0,0 +1,409 @@
+package transaction
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+)
+
+// valueWeiDigits is the number of decimal digits of the largest uint256 value,
+// values are zero padded to it so they can be compared as text
+const valueWeiDigits = 78
+
+var _ Storage = (*sqlStorage)(nil)
+
+// sqlStorage stores transactions as normalized rows,
+// indexed by address (key), block number and transaction hash
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+func (s *sqlStorage) Get(ctx context.Context, key AddressTransactionID, query Query) (*Page, error) {
+	conditions, args := queryConditions(key, query)
+	order := "ASC"
+	if query.Descending() {
+		order = "DESC"
+	}
+	statement := fmt.Sprintf(`
+		SELECT hash, block_hash, block_number, transaction_index, nonce, from_address, to_address, value, gas_price, gas, input, block_timestamp,
+			tx_type, chain_id, max_fee_per_gas, max_priority_fee_per_gas, access_list, max_fee_per_blob_gas, blob_versioned_hashes, v, r, s, y_parity,
+			receipt, direction
+		FROM address_transactions
+		WHERE %s
+		ORDER BY block_number %s, transaction_index %s
+		LIMIT ?`,
+		strings.Join(conditions, " AND "), order, order,
+	)
+	args = append(args, query.PageLimit()+1)
+
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(statement), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := make([]*AddressTransaction, 0)
+	for rows.Next() {
+		var row transactionRow
+		var blockNumber, transactionIndex, blockTimestamp int64
+		var direction string
+		err := rows.Scan(
+			&row.hash,
+			&row.blockHash,
+			&blockNumber,
+			&transactionIndex,
+			&row.nonce,
+			&row.from,
+			&row.to,
+			&row.value,
+			&row.gasPrice,
+			&row.gas,
+			&row.input,
+			&blockTimestamp,
+			&row.txType,
+			&row.chainID,
+			&row.maxFeePerGas,
+			&row.maxPriorityFeePerGas,
+			&row.accessList,
+			&row.maxFeePerBlobGas,
+			&row.blobVersionedHashes,
+			&row.v,
+			&row.r,
+			&row.s,
+			&row.yParity,
+			&row.receipt,
+			&direction,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transaction, err := row.transaction()
+		if err != nil {
+			return nil, err
+		}
+		receipt, err := row.decodeReceipt()
+		if err != nil {
+			return nil, err
+		}
+		transaction.BlockNumber = blockchain.QuantityFromInt64(blockNumber)
+		transaction.TransactionIndex = blockchain.QuantityFromInt64(transactionIndex)
+		transactions = append(transactions, &AddressTransaction{
+			ID:             key,
+			Transaction:    transaction,
+			Direction:      RecordDirection(direction),
+			BlockTimestamp: blockTimestamp,
+			Receipt:        receipt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return NewPage(transactions, query), nil
+}
+
+func (s *sqlStorage) Count(ctx context.Context, key AddressTransactionID, query Query) (int, error) {
+	// cursor is ignored
+	query.After = nil
+	conditions, args := queryConditions(key, query)
+	statement := fmt.Sprintf(`SELECT COUNT(*) FROM address_transactions WHERE %s`, strings.Join(conditions, " AND "))
+	var count int
+	err := s.db.QueryRowContext(ctx, s.db.Rebind(statement), args...).Scan(&count)
+	return count, err
+}
+
+// queryConditions returns where conditions and their arguments of the query
+func queryConditions(key AddressTransactionID, query Query) ([]string, []any) {
+	conditions := []string{"address = ?"}
+	args := []any{key.String()}
+
+	if condition, directionArgs := DirectionCondition(query.Direction); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, directionArgs...)
+	}
+	if query.After != nil {
+		comparison := ">"
+		if query.Descending() {
+			comparison = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("(block_number %s ? OR (block_number = ? AND transaction_index %s ?))", comparison, comparison))
+		args = append(args, query.After.BlockNumber, query.After.BlockNumber, query.After.TransactionIndex)
+	}
+	if query.FromBlock != nil {
+		conditions = append(conditions, "block_number >= ?")
+		args = append(args, *query.FromBlock)
+	}
+	if query.ToBlock != nil {
+		conditions = append(conditions, "block_number <= ?")
+		args = append(args, *query.ToBlock)
+	}
+	if query.FromTime != nil {
+		conditions = append(conditions, "block_timestamp >= ?")
+		args = append(args, *query.FromTime)
+	}
+	if query.ToTime != nil {
+		conditions = append(conditions, "block_timestamp <= ?")
+		args = append(args, *query.ToTime)
+	}
+	if query.MinValue != nil {
+		conditions = append(conditions, "value_wei >= ?")
+		args = append(args, paddedValue(query.MinValue))
+	}
+	if query.MaxValue != nil {
+		conditions = append(conditions, "value_wei <= ?")
+		args = append(args, paddedValue(query.MaxValue))
+	}
+	if query.Execution != "" {
+		conditions = append(conditions, "execution = ?")
+		args = append(args, string(query.Execution))
+	}
+	if !query.Counterparty.IsZero() {
+		counterparty := query.Counterparty.String()
+		conditions = append(conditions, "((direction IN (?, ?) AND to_address = ?) OR (direction IN (?, ?) AND from_address = ?))")
+		args = append(args,
+			string(OutRecordDirection), string(SelfRecordDirection), counterparty,
+			string(InRecordDirection), string(SelfRecordDirection), counterparty,
+		)
+	}
+	return conditions, args
+}
+
+// DirectionCondition returns where condition and its arguments of direction column matching the query direction,
+// condition is empty when every direction matches
+func DirectionCondition(direction Direction) (string, []any) {
+	switch direction {
+	case InboundDirection:
+		return "direction IN (?, ?)", []any{string(InRecordDirection), string(SelfRecordDirection)}
+	case OutboundDirection:
+		return "direction IN (?, ?)", []any{string(OutRecordDirection), string(SelfRecordDirection)}
+	case SelfDirection:
+		return "direction = ?", []any{string(SelfRecordDirection)}
+	}
+	return "", nil
+}
+
+func (s *sqlStorage) InsertBatch(ctx context.Context, data map[AddressTransactionID][]*AddressTransaction) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// transaction stored without receipt gets its receipt when it is stored again, e.g. by backfill
+	statement, err := tx.PrepareContext(ctx, s.db.Rebind(`
+		INSERT INTO address_transactions (
+			address, block_number, transaction_index, hash, block_hash, nonce, from_address, to_address,
+			value, gas_price, gas, input, block_timestamp, value_wei,
+			tx_type, chain_id, max_fee_per_gas, max_priority_fee_per_gas, access_list, max_fee_per_blob_gas, blob_versioned_hashes, v, r, s, y_parity,
+			receipt, execution, direction
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (address, block_number, transaction_index) DO UPDATE SET
+			receipt = excluded.receipt,
+			execution = excluded.execution
+		WHERE address_transactions.receipt = ''`),
+	)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for key, addressTransactions := range data {
+		for _, addressTransaction := range addressTransactions {
+			transaction := addressTransaction.Transaction
+			position := addressTransaction.Position()
+			accessList, err := encodeAccessList(transaction.AccessList)
+			if err != nil {
+				return err
+			}
+			receipt, err := encodeReceipt(addressTransaction.Receipt)
+			if err != nil {
+				return err
+			}
+			_, err = statement.ExecContext(ctx,
+				key.String(),
+				position.BlockNumber,
+				position.TransactionIndex,
+				transaction.Hash.String(),
+				transaction.BlockHash.String(),
+				transaction.Nonce.String(),
+				transaction.From.String(),
+				transaction.To.String(),
+				transaction.Value.String(),
+				transaction.GasPrice.String(),
+				transaction.Gas.String(),
+				transaction.Input.String(),
+				addressTransaction.BlockTimestamp,
+				paddedValue(addressTransaction.ValueWei()),
+				transaction.Type.String(),
+				optionalQuantity(transaction.ChainID),
+				optionalQuantity(transaction.MaxFeePerGas),
+				optionalQuantity(transaction.MaxPriorityFeePerGas),
+				accessList,
+				optionalQuantity(transaction.MaxFeePerBlobGas),
+				joinHashes(transaction.BlobVersionedHashes),
+				transaction.V.String(),
+				transaction.R.String(),
+				transaction.S.String(),
+				optionalQuantity(transaction.YParity),
+				receipt,
+				string(addressTransaction.Execution()),
+				string(addressTransaction.RecordDirection()),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM address_transactions WHERE block_number >= ?`), blockNumber)
+	return err
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, key AddressTransactionID) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM address_transactions WHERE address = ?`), key.String())
+	return err
+}
+
+// paddedValue returns value as zero padded decimal text
+func paddedValue(value *big.Int) string {
+	return fmt.Sprintf("%0*s", valueWeiDigits, value.String())
+}
+
+// transactionRow are hex encoded columns of stored transaction
+type transactionRow struct {
+	hash, blockHash, nonce, from, to, value, gasPrice, gas, input string
+	txType, v, r, s                                               string
+	// optional columns are empty when transaction does not have the field
+	chainID, maxFeePerGas, maxPriorityFeePerGas, maxFeePerBlobGas, yParity string
+	accessList, blobVersionedHashes                                        string
+	receipt                                                                string
+}
+
+// transaction parses columns of the row, it fails on invalid hex values
+func (r *transactionRow) transaction() (*blockchain.Transaction, error) {
+	var transaction blockchain.Transaction
+	var err error
+	for _, column := range []struct {
+		value  string
+		target encoding.TextUnmarshaler
+	}{
+		{r.hash, &transaction.Hash},
+		{r.blockHash, &transaction.BlockHash},
+		{r.nonce, &transaction.Nonce},
+		{r.from, &transaction.From},
+		{r.to, &transaction.To},
+		{r.value, &transaction.Value},
+		{r.gasPrice, &transaction.GasPrice},
+		{r.gas, &transaction.Gas},
+		{r.input, &transaction.Input},
+		{r.txType, &transaction.Type},
+		{r.v, &transaction.V},
+		{r.r, &transaction.R},
+		{r.s, &transaction.S},
+	} {
+		if err = column.target.UnmarshalText([]byte(column.value)); err != nil {
+			return nil, err
+		}
+	}
+	for _, column := range []struct {
+		value  string
+		target **blockchain.Quantity
+	}{
+		{r.chainID, &transaction.ChainID},
+		{r.maxFeePerGas, &transaction.MaxFeePerGas},
+		{r.maxPriorityFeePerGas, &transaction.MaxPriorityFeePerGas},
+		{r.maxFeePerBlobGas, &transaction.MaxFeePerBlobGas},
+		{r.yParity, &transaction.YParity},
+	} {
+		if column.value == "" {
+			continue
+		}
+		quantity, err := blockchain.ParseQuantity(column.value)
+		if err != nil {
+			return nil, err
+		}
+		*column.target = &quantity
+	}
+	if r.accessList != "" {
+		if err = json.Unmarshal([]byte(r.accessList), &transaction.AccessList); err != nil {
+			return nil, err
+		}
+	}
+	if r.blobVersionedHashes != "" {
+		for _, value := range strings.Split(r.blobVersionedHashes, ",") {
+			hash, err := blockchain.ParseHash(value)
+			if err != nil {
+				return nil, err
+			}
+			transaction.BlobVersionedHashes = append(transaction.BlobVersionedHashes, hash)
+		}
+	}
+	return &transaction, nil
+}
+
+// decodeReceipt parses json encoded receipt of the row, receipt is nil if the column is empty
+func (r *transactionRow) decodeReceipt() (*blockchain.Receipt, error) {
+	if r.receipt == "" {
+		return nil, nil
+	}
+	var receipt blockchain.Receipt
+	if err := json.Unmarshal([]byte(r.receipt), &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// encodeReceipt returns json encoded receipt, missing receipt is empty
+func encodeReceipt(receipt *blockchain.Receipt) (string, error) {
+	if receipt == nil {
+		return "", nil
+	}
+	encoded, err := json.Marshal(receipt)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// optionalQuantity returns hex encoded quantity, missing quantity is empty
+func optionalQuantity(quantity *blockchain.Quantity) string {
+	if quantity == nil {
+		return ""
+	}
+	return quantity.String()
+}
+
+// encodeAccessList returns json encoded access list, missing access list is empty
+func encodeAccessList(accessList blockchain.AccessList) (string, error) {
+	if accessList == nil {
+		return "", nil
+	}
+	encoded, err := json.Marshal(accessList)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// joinHashes joins hashes to comma separated list
+func joinHashes(hashes []blockchain.Hash) string {
+	values := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		values = append(values, hash.String())
+	}
+	return strings.Join(values, ",")
+}
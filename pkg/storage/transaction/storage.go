@@ -2,19 +2,26 @@ package transaction
 
 import (
 	"context"
-	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"sort"
 	"sync"
 )
 
 // ReadOnlyStorage is responsible for reading transactions
 type ReadOnlyStorage interface {
-	Get(ctx context.Context, key string) ([]*blockchain.Transaction, error)
+	// Get returns page of transactions stored under the key matching the query
+	Get(ctx context.Context, key AddressTransactionID, query Query) (*Page, error)
+	// Count returns number of transactions stored under the key matching the query, cursor and limit are ignored
+	Count(ctx context.Context, key AddressTransactionID, query Query) (int, error)
 }
 
 // WriteStorage is responsible for writing transactions
-// TODO in future do not use blockchain.Transaction, but some model representation of transaction
 type WriteStorage interface {
-	InsertBatch(ctx context.Context, data map[string][]*blockchain.Transaction) error
+	// InsertBatch inserts transactions by key, transaction already stored under the key is not duplicated
+	InsertBatch(ctx context.Context, data map[AddressTransactionID][]*AddressTransaction) error
+	// DeleteFromBlock deletes all transactions included in given block or any block after it
+	DeleteFromBlock(ctx context.Context, blockNumber int64) error
+	// Delete deletes all transactions stored under the key
+	Delete(ctx context.Context, key AddressTransactionID) error
 }
 
 // Storage is responsible for reading and writing transactions
@@ -26,31 +33,83 @@ type Storage interface {
 var _ Storage = (*inMemoryStorage)(nil)
 
 type inMemoryStorage struct {
-	transactions map[string][]*blockchain.Transaction
+	transactions map[AddressTransactionID]map[Position]*AddressTransaction
 	mutex        sync.RWMutex
 }
 
 func NewStorage() Storage {
 	return &inMemoryStorage{
-		transactions: make(map[string][]*blockchain.Transaction),
+		transactions: make(map[AddressTransactionID]map[Position]*AddressTransaction),
 	}
 }
 
-func (s *inMemoryStorage) Get(ctx context.Context, key string) ([]*blockchain.Transaction, error) {
+func (s *inMemoryStorage) Get(ctx context.Context, key AddressTransactionID, query Query) (*Page, error) {
+	s.mutex.RLock()
+	matched := make([]*AddressTransaction, 0)
+	for position, transaction := range s.transactions[key] {
+		if query.IsAfterCursor(position) && query.Match(key.Address(), transaction) {
+			matched = append(matched, transaction)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if query.Descending() {
+			return matched[j].Position().Less(matched[i].Position())
+		}
+		return matched[i].Position().Less(matched[j].Position())
+	})
+	if len(matched) > query.PageLimit()+1 {
+		matched = matched[:query.PageLimit()+1]
+	}
+	return NewPage(matched, query), nil
+}
+
+func (s *inMemoryStorage) Count(ctx context.Context, key AddressTransactionID, query Query) (int, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	transactions, ok := s.transactions[key]
-	if !ok {
-		return nil, nil
+	count := 0
+	for _, transaction := range s.transactions[key] {
+		if query.Match(key.Address(), transaction) {
+			count++
+		}
 	}
-	return transactions, nil
+	return count, nil
 }
 
-func (s *inMemoryStorage) InsertBatch(ctx context.Context, data map[string][]*blockchain.Transaction) error {
+func (s *inMemoryStorage) InsertBatch(ctx context.Context, data map[AddressTransactionID][]*AddressTransaction) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	for key, value := range data {
-		s.transactions[key] = append(s.transactions[key], value...)
+	for key, transactions := range data {
+		if _, ok := s.transactions[key]; !ok {
+			s.transactions[key] = make(map[Position]*AddressTransaction)
+		}
+		for _, transaction := range transactions {
+			s.transactions[key][transaction.Position()] = transaction
+		}
 	}
 	return nil
 }
+
+func (s *inMemoryStorage) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, transactions := range s.transactions {
+		for position := range transactions {
+			if position.BlockNumber >= blockNumber {
+				delete(transactions, position)
+			}
+		}
+		if len(transactions) == 0 {
+			delete(s.transactions, key)
+		}
+	}
+	return nil
+}
+
+func (s *inMemoryStorage) Delete(ctx context.Context, key AddressTransactionID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.transactions, key)
+	return nil
+}
@@ -0,0 +1,21 @@
+package transfer
+
+import (
+	"go.etcd.io/bbolt"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+)
+
+const transfersBucket = "token_transfers"
+
+// NewBoltStorage creates storage which persists token transfers in bolt database,
+// every key has its own nested bucket in which token transfers are keyed by their position
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	boltStorage, err := record.NewBoltStorage[AddressTransferID, *AddressTransfer](db, transfersBucket)
+	if err != nil {
+		return nil, err
+	}
+	return &storage{
+		recordStorage: boltStorage,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package transfer
+
+import (
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+)
+
+// Position is the position of a token transfer in the chain, index is the log index and sub index is the index in the batch,
+// transfers of a batch event share the log index and are ordered by their index in the batch
+type Position = record.Position
+
+// Query is a query of token transfers of an address, all filters are optional
+type Query struct {
+	record.Query
+	// Token filters token transfers of the token contract
+	Token blockchain.Address
+	// Standard filters token transfers of the token standard
+	Standard blockchain.TokenStandard
+}
+
+// Match returns true if token transfer of the address matches all filters of the query except cursor
+func (q Query) Match(address blockchain.Address, addressTransfer *AddressTransfer) bool {
+	if addressTransfer.Transfer == nil {
+		return false
+	}
+	transfer := addressTransfer.Transfer
+
+	if !q.Query.Match(addressTransfer) {
+		return false
+	}
+	if !q.Token.IsZero() && transfer.Token != q.Token {
+		return false
+	}
+	if q.Standard != "" && transfer.Standard != q.Standard {
+		return false
+	}
+	return true
+}
+
+// Page is a page of token transfers
+type Page struct {
+	Transfers []*AddressTransfer
+	// NextCursor is the cursor of the next page, it is empty on the last page
+	NextCursor string
+}
+
+// NewPage creates a page from token transfers matching the query,
+// token transfers have to be in query order and contain one token transfer more than the limit if there is a next page
+func NewPage(transfers []*AddressTransfer, query Query) *Page {
+	transfers, nextCursor := record.Paginate(transfers, query.Query)
+	return &Page{
+		Transfers:  transfers,
+		NextCursor: nextCursor,
+	}
+}
@@ -0,0 +1,118 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+// ReadOnlyRepository is responsible for reading token transfers
+type ReadOnlyRepository interface {
+	// GetTransfers returns page of inbound or outbound token transfers for an address matching the query
+	GetTransfers(ctx context.Context, address blockchain.Address, query Query) (*Page, error)
+}
+
+// WriteRepository is responsible for writing token transfers
+type WriteRepository interface {
+	InsertTransfers(ctx context.Context, transfers []*AddressTransfer) error
+	// DeleteTransfersFromBlock deletes token transfers of given block or any block after it,
+	// it is used to roll back token transfers from orphaned blocks after reorg
+	DeleteTransfersFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error
+	// DeleteTransfers deletes all token transfers for an address
+	DeleteTransfers(ctx context.Context, address blockchain.Address) error
+}
+
+// Repository is responsible for reading and writing token transfers
+type Repository interface {
+	ReadOnlyRepository
+	WriteRepository
+}
+
+var _ Repository = (*repository)(nil)
+
+type repository struct {
+	storage Storage
+}
+
+func NewRepository(storage Storage) Repository {
+	return &repository{
+		storage: storage,
+	}
+}
+
+func (r *repository) GetTransfers(ctx context.Context, address blockchain.Address, query Query) (*Page, error) {
+	return r.storage.Get(ctx, NewAddressTransferID(address), query)
+}
+
+func (r *repository) InsertTransfers(ctx context.Context, addressTransfers []*AddressTransfer) error {
+	data := make(map[AddressTransferID][]*AddressTransfer)
+	for _, addressTransfer := range addressTransfers {
+		data[addressTransfer.ID] = append(data[addressTransfer.ID], addressTransfer)
+	}
+	return r.storage.InsertBatch(ctx, data)
+}
+
+func (r *repository) DeleteTransfersFromBlock(ctx context.Context, blockNumber blockchain.BlockNumber) error {
+	return r.storage.DeleteFromBlock(ctx, blockNumber.ToInt64())
+}
+
+func (r *repository) DeleteTransfers(ctx context.Context, address blockchain.Address) error {
+	return r.storage.Delete(ctx, NewAddressTransferID(address))
+}
+
+// AddressTransferID is a unique identifier for token transfers of an address
+type AddressTransferID string
+
+func NewAddressTransferID(
+	address blockchain.Address,
+) AddressTransferID {
+	return AddressTransferID(address)
+}
+
+func (a AddressTransferID) String() string {
+	return string(a)
+}
+
+// Address returns address of the token transfer
+func (a AddressTransferID) Address() blockchain.Address {
+	return blockchain.Address(a)
+}
+
+// AddressTransfer is a token transfer sent from or to an address
+type AddressTransfer struct {
+	ID       AddressTransferID         `json:"id"`
+	Transfer *blockchain.TokenTransfer `json:"transfer"`
+	// Direction is the direction of the token transfer relative to the address
+	Direction transaction.RecordDirection `json:"direction"`
+	// BlockTimestamp is unix timestamp of the block that includes the transfer
+	BlockTimestamp int64 `json:"blockTimestamp"`
+}
+
+// NewAddressTransfer creates token transfer of the address with its direction relative to the address
+func NewAddressTransfer(address blockchain.Address, transfer *blockchain.TokenTransfer, blockTimestamp int64) *AddressTransfer {
+	return &AddressTransfer{
+		ID:             NewAddressTransferID(address),
+		Transfer:       transfer,
+		Direction:      transaction.NewRecordDirection(address, transfer.From, transfer.To),
+		BlockTimestamp: blockTimestamp,
+	}
+}
+
+// RecordDirection returns direction of the token transfer relative to the address,
+// it is derived from addresses of the token transfer when it is not set
+func (a *AddressTransfer) RecordDirection() transaction.RecordDirection {
+	if a.Direction != "" {
+		return a.Direction
+	}
+	return transaction.NewRecordDirection(a.ID.Address(), a.Transfer.From, a.Transfer.To)
+}
+
+// Position returns position of the token transfer in the chain
+func (a *AddressTransfer) Position() Position {
+	return Position{
+		BlockNumber: a.Transfer.BlockNumber.Int64(),
+		Index:       a.Transfer.LogIndex.Int64(),
+		SubIndex:    a.Transfer.BatchIndex,
+	}
+}
@@ -0,0 +1,215 @@
+package transfer
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+	"github.com/veljkomatic/be-homework/pkg/storage/transaction"
+)
+
+var _ Storage = (*sqlStorage)(nil)
+
+// positionColumns are columns of the position, log_index is its index and batch_index its sub index
+var positionColumns = record.PositionColumns{"block_number", "log_index", "batch_index"}
+
+// sqlStorage stores token transfers as normalized rows,
+// indexed by address (key), block number and token contract
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+func (s *sqlStorage) Get(ctx context.Context, key AddressTransferID, query Query) (*Page, error) {
+	conditions, args := queryConditions(key, query)
+	statement := fmt.Sprintf(`
+		SELECT block_number, log_index, batch_index, standard, token, operator, from_address, to_address, value, token_id,
+			transaction_hash, transaction_index, block_hash, block_timestamp, direction
+		FROM token_transfers
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?`,
+		strings.Join(conditions, " AND "), positionColumns.OrderBy(query.Query),
+	)
+	args = append(args, query.PageLimit()+1)
+
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(statement), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]*AddressTransfer, 0)
+	for rows.Next() {
+		var row transferRow
+		var blockNumber, logIndex, batchIndex, transactionIndex, blockTimestamp int64
+		var direction string
+		err := rows.Scan(
+			&blockNumber,
+			&logIndex,
+			&batchIndex,
+			&row.standard,
+			&row.token,
+			&row.operator,
+			&row.from,
+			&row.to,
+			&row.value,
+			&row.tokenID,
+			&row.transactionHash,
+			&transactionIndex,
+			&row.blockHash,
+			&blockTimestamp,
+			&direction,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transfer, err := row.transfer()
+		if err != nil {
+			return nil, err
+		}
+		transfer.BlockNumber = blockchain.QuantityFromInt64(blockNumber)
+		transfer.LogIndex = blockchain.QuantityFromInt64(logIndex)
+		transfer.BatchIndex = batchIndex
+		transfer.TransactionIndex = blockchain.QuantityFromInt64(transactionIndex)
+		transfers = append(transfers, &AddressTransfer{
+			ID:             key,
+			Transfer:       transfer,
+			Direction:      transaction.RecordDirection(direction),
+			BlockTimestamp: blockTimestamp,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return NewPage(transfers, query), nil
+}
+
+// queryConditions returns where conditions and their arguments of the query
+func queryConditions(key AddressTransferID, query Query) ([]string, []any) {
+	conditions := []string{"address = ?"}
+	args := []any{key.String()}
+
+	positionConditions, positionArgs := record.QueryConditions(query.Query, positionColumns)
+	conditions = append(conditions, positionConditions...)
+	args = append(args, positionArgs...)
+	if !query.Token.IsZero() {
+		conditions = append(conditions, "token = ?")
+		args = append(args, query.Token.String())
+	}
+	if query.Standard != "" {
+		conditions = append(conditions, "standard = ?")
+		args = append(args, string(query.Standard))
+	}
+	return conditions, args
+}
+
+func (s *sqlStorage) InsertBatch(ctx context.Context, data map[AddressTransferID][]*AddressTransfer) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.PrepareContext(ctx, s.db.Rebind(`
+		INSERT INTO token_transfers (
+			address, block_number, log_index, batch_index, standard, token, operator, from_address, to_address, value, token_id,
+			transaction_hash, transaction_index, block_hash, block_timestamp, direction
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (address, block_number, log_index, batch_index) DO NOTHING`),
+	)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for key, addressTransfers := range data {
+		for _, addressTransfer := range addressTransfers {
+			transfer := addressTransfer.Transfer
+			position := addressTransfer.Position()
+			var tokenID string
+			if transfer.TokenID != nil {
+				tokenID = transfer.TokenID.String()
+			}
+			_, err = statement.ExecContext(ctx,
+				key.String(),
+				position.BlockNumber,
+				position.Index,
+				position.SubIndex,
+				string(transfer.Standard),
+				transfer.Token.String(),
+				transfer.Operator.String(),
+				transfer.From.String(),
+				transfer.To.String(),
+				transfer.Value.String(),
+				tokenID,
+				transfer.TransactionHash.String(),
+				transfer.TransactionIndex.Int64(),
+				transfer.BlockHash.String(),
+				addressTransfer.BlockTimestamp,
+				string(addressTransfer.RecordDirection()),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) DeleteFromBlock(ctx context.Context, blockNumber int64) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM token_transfers WHERE block_number >= ?`), blockNumber)
+	return err
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, key AddressTransferID) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM token_transfers WHERE address = ?`), key.String())
+	return err
+}
+
+// transferRow are hex encoded columns of stored token transfer
+type transferRow struct {
+	standard, token, operator, from, to, value, transactionHash, blockHash string
+	// tokenID is empty when token transfer does not have token id
+	tokenID string
+}
+
+// transfer parses columns of the row, it fails on invalid hex values
+func (r *transferRow) transfer() (*blockchain.TokenTransfer, error) {
+	transfer := blockchain.TokenTransfer{
+		Standard: blockchain.TokenStandard(r.standard),
+	}
+	for _, column := range []struct {
+		value  string
+		target encoding.TextUnmarshaler
+	}{
+		{r.token, &transfer.Token},
+		{r.operator, &transfer.Operator},
+		{r.from, &transfer.From},
+		{r.to, &transfer.To},
+		{r.value, &transfer.Value},
+		{r.transactionHash, &transfer.TransactionHash},
+		{r.blockHash, &transfer.BlockHash},
+	} {
+		if err := column.target.UnmarshalText([]byte(column.value)); err != nil {
+			return nil, err
+		}
+	}
+	if r.tokenID != "" {
+		tokenID, err := blockchain.ParseQuantity(r.tokenID)
+		if err != nil {
+			return nil, err
+		}
+		transfer.TokenID = &tokenID
+	}
+	return &transfer, nil
+}
@@ -0,0 +1,60 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/record"
+)
+
+// ReadOnlyStorage is responsible for reading token transfers
+type ReadOnlyStorage interface {
+	// Get returns page of token transfers stored under the key matching the query
+	Get(ctx context.Context, key AddressTransferID, query Query) (*Page, error)
+}
+
+// WriteStorage is responsible for writing token transfers
+type WriteStorage interface {
+	// InsertBatch inserts token transfers by key, token transfer already stored under the key is not duplicated
+	InsertBatch(ctx context.Context, data map[AddressTransferID][]*AddressTransfer) error
+	// DeleteFromBlock deletes all token transfers of given block or any block after it
+	DeleteFromBlock(ctx context.Context, blockNumber int64) error
+	// Delete deletes all token transfers stored under the key
+	Delete(ctx context.Context, key AddressTransferID) error
+}
+
+// Storage is responsible for reading and writing token transfers
+type Storage interface {
+	ReadOnlyStorage
+	WriteStorage
+}
+
+// recordStorage is the storage of records which stores token transfers
+type recordStorage interface {
+	Find(ctx context.Context, key AddressTransferID, query record.Query, match func(*AddressTransfer) bool) ([]*AddressTransfer, error)
+	InsertBatch(ctx context.Context, data map[AddressTransferID][]*AddressTransfer) error
+	DeleteFromBlock(ctx context.Context, blockNumber int64) error
+	Delete(ctx context.Context, key AddressTransferID) error
+}
+
+var _ Storage = (*storage)(nil)
+
+// storage reads token transfers matching the query from in memory or bolt storage of records
+type storage struct {
+	recordStorage
+}
+
+func NewStorage() Storage {
+	return &storage{
+		recordStorage: record.NewMemoryStorage[AddressTransferID, *AddressTransfer](),
+	}
+}
+
+func (s *storage) Get(ctx context.Context, key AddressTransferID, query Query) (*Page, error) {
+	transfers, err := s.Find(ctx, key, query.Query, func(transfer *AddressTransfer) bool {
+		return query.Match(key.Address(), transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewPage(transfers, query), nil
+}
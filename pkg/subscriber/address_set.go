@@ -0,0 +1,129 @@
+package subscriber
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+const (
+	// DefaultFalsePositiveRate is the default false positive rate of bloom filter of subscribed addresses
+	DefaultFalsePositiveRate = 0.01
+
+	addressShards = 64
+	// minAddressCapacity is the minimal number of addresses bloom filter is sized for
+	minAddressCapacity = 1024
+)
+
+// addressSet is a set of subscribed addresses tested for every transaction.
+// Addresses are tested against bloom filter first, so transactions of addresses which are not subscribed
+// are rejected without locking. Accepted addresses are checked in the exact set, which is split into shards,
+// so tests do not contend with each other or with subscribing of other addresses.
+// Bloom filter is rebuilt when it holds more addresses than it is sized for, or when too many addresses
+// are removed from the set, as removed addresses are still accepted by the filter.
+type addressSet struct {
+	filter            atomic.Pointer[bloomFilter]
+	shards            [addressShards]addressShard
+	falsePositiveRate float64
+	// capacity is the number of addresses the filter is sized for, removed is the number of addresses
+	// removed since the filter was built, they are guarded by the mutex which serializes writers
+	capacity int
+	size     int
+	removed  int
+	mutex    sync.Mutex
+}
+
+type addressShard struct {
+	addresses map[blockchain.Address]struct{}
+	mutex     sync.RWMutex
+}
+
+func newAddressSet(falsePositiveRate float64) *addressSet {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = DefaultFalsePositiveRate
+	}
+	s := &addressSet{
+		falsePositiveRate: falsePositiveRate,
+		capacity:          minAddressCapacity,
+	}
+	for i := range s.shards {
+		s.shards[i].addresses = make(map[blockchain.Address]struct{})
+	}
+	s.filter.Store(newBloomFilter(s.capacity, falsePositiveRate))
+	return s
+}
+
+// add adds address to the set
+func (s *addressSet) add(address blockchain.Address) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	hash := hashAddress(address)
+	shard := &s.shards[hash%addressShards]
+	shard.mutex.Lock()
+	_, exists := shard.addresses[address]
+	shard.addresses[address] = struct{}{}
+	shard.mutex.Unlock()
+	if exists {
+		return
+	}
+	s.size++
+	if s.size > s.capacity {
+		s.rebuild(2 * s.size)
+		return
+	}
+	s.filter.Load().add(hash)
+}
+
+// remove removes address from the set
+func (s *addressSet) remove(address blockchain.Address) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	shard := &s.shards[hashAddress(address)%addressShards]
+	shard.mutex.Lock()
+	_, exists := shard.addresses[address]
+	delete(shard.addresses, address)
+	shard.mutex.Unlock()
+	if !exists {
+		return
+	}
+	s.size--
+	s.removed++
+	// removed addresses raise false positive rate of the filter, it is rebuilt when they are a quarter of its capacity
+	if s.removed > s.capacity/4 {
+		s.rebuild(2 * s.size)
+	}
+}
+
+// contains checks if address is in the set
+func (s *addressSet) contains(address blockchain.Address) bool {
+	hash := hashAddress(address)
+	if !s.filter.Load().test(hash) {
+		return false
+	}
+	shard := &s.shards[hash%addressShards]
+	shard.mutex.RLock()
+	_, exists := shard.addresses[address]
+	shard.mutex.RUnlock()
+	return exists
+}
+
+// rebuild builds new filter sized for the capacity from addresses of all shards and replaces the current one,
+// caller holds the mutex. Tests use the current filter until the new one is built.
+func (s *addressSet) rebuild(capacity int) {
+	if capacity < minAddressCapacity {
+		capacity = minAddressCapacity
+	}
+	filter := newBloomFilter(capacity, s.falsePositiveRate)
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mutex.RLock()
+		for address := range shard.addresses {
+			filter.add(hashAddress(address))
+		}
+		shard.mutex.RUnlock()
+	}
+	s.capacity = capacity
+	s.removed = 0
+	s.filter.Store(filter)
+}
@@ -0,0 +1,211 @@
+package subscriber
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// mapAddressSet is the set of subscribed addresses used before the bloom filter,
+// a single map guarded by RWMutex, it is kept as the baseline of benchmarks
+type mapAddressSet struct {
+	addresses map[blockchain.Address]struct{}
+	mutex     sync.RWMutex
+}
+
+func (s *mapAddressSet) add(address blockchain.Address) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.addresses[address] = struct{}{}
+}
+
+func (s *mapAddressSet) contains(address blockchain.Address) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	_, exists := s.addresses[address]
+	return exists
+}
+
+func testAddress(i int) blockchain.Address {
+	return blockchain.Address(fmt.Sprintf("0x%040x", i))
+}
+
+func TestBloomFilterHasNoFalseNegatives(t *testing.T) {
+	filter := newBloomFilter(10000, DefaultFalsePositiveRate)
+	for i := 0; i < 10000; i++ {
+		filter.add(hashAddress(testAddress(i)))
+	}
+	for i := 0; i < 10000; i++ {
+		if !filter.test(hashAddress(testAddress(i))) {
+			t.Fatalf("added address %s rejected by filter", testAddress(i))
+		}
+	}
+
+	falsePositives := 0
+	for i := 10000; i < 20000; i++ {
+		if filter.test(hashAddress(testAddress(i))) {
+			falsePositives++
+		}
+	}
+	// expected rate is 1%, filter is broken if it is much higher
+	if falsePositives > 300 {
+		t.Fatalf("too many false positives: %d of 10000", falsePositives)
+	}
+}
+
+func TestAddressSetAddRemove(t *testing.T) {
+	set := newAddressSet(0)
+	// more addresses than the initial capacity of the filter, so it is rebuilt while adding
+	for i := 0; i < 3*minAddressCapacity; i++ {
+		set.add(testAddress(i))
+	}
+	for i := 0; i < 3*minAddressCapacity; i++ {
+		if !set.contains(testAddress(i)) {
+			t.Fatalf("added address %s not found", testAddress(i))
+		}
+	}
+	if set.contains(testAddress(3 * minAddressCapacity)) {
+		t.Fatal("address which was not added found")
+	}
+
+	// removing more than a quarter of the capacity rebuilds the filter
+	for i := 0; i < 2*minAddressCapacity; i++ {
+		set.remove(testAddress(i))
+	}
+	for i := 0; i < 2*minAddressCapacity; i++ {
+		if set.contains(testAddress(i)) {
+			t.Fatalf("removed address %s found", testAddress(i))
+		}
+	}
+	for i := 2 * minAddressCapacity; i < 3*minAddressCapacity; i++ {
+		if !set.contains(testAddress(i)) {
+			t.Fatalf("address %s not found after removal of other addresses", testAddress(i))
+		}
+	}
+	if set.size != minAddressCapacity {
+		t.Fatalf("expected %d addresses, got %d", minAddressCapacity, set.size)
+	}
+
+	set.add(testAddress(0))
+	if !set.contains(testAddress(0)) {
+		t.Fatal("address added again not found")
+	}
+}
+
+func TestAddressSetShards(t *testing.T) {
+	set := newAddressSet(0)
+	for i := 0; i < 10*addressShards; i++ {
+		set.add(testAddress(i))
+	}
+	total := 0
+	for i := range set.shards {
+		shard := &set.shards[i]
+		if len(shard.addresses) == 0 {
+			t.Fatalf("shard %d is empty", i)
+		}
+		for address := range shard.addresses {
+			if hashAddress(address)%addressShards != uint64(i) {
+				t.Fatalf("address %s is in wrong shard %d", address, i)
+			}
+		}
+		total += len(shard.addresses)
+	}
+	if total != 10*addressShards {
+		t.Fatalf("expected %d addresses in shards, got %d", 10*addressShards, total)
+	}
+}
+
+const (
+	benchmarkSubscribedAddresses = 100000
+	// benchmarkHitRatio is the ratio of tested addresses which are subscribed,
+	// most addresses of processed transactions are not subscribed
+	benchmarkHitRatio = 100
+)
+
+func benchmarkAddresses() []blockchain.Address {
+	addresses := make([]blockchain.Address, 1024)
+	for i := range addresses {
+		if i%benchmarkHitRatio == 0 {
+			addresses[i] = testAddress(i)
+			continue
+		}
+		addresses[i] = testAddress(benchmarkSubscribedAddresses + i)
+	}
+	return addresses
+}
+
+func BenchmarkAddressSetContains(b *testing.B) {
+	set := newAddressSet(0)
+	for i := 0; i < benchmarkSubscribedAddresses; i++ {
+		set.add(testAddress(i))
+	}
+	addresses := benchmarkAddresses()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			set.contains(addresses[i%len(addresses)])
+			i++
+		}
+	})
+}
+
+func BenchmarkMapAddressSetContains(b *testing.B) {
+	set := &mapAddressSet{addresses: make(map[blockchain.Address]struct{})}
+	for i := 0; i < benchmarkSubscribedAddresses; i++ {
+		set.add(testAddress(i))
+	}
+	addresses := benchmarkAddresses()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			set.contains(addresses[i%len(addresses)])
+			i++
+		}
+	})
+}
+
+// benchmarks of tests while other addresses are subscribed, every 100th operation is a subscription
+
+func BenchmarkAddressSetContainsWhileAdding(b *testing.B) {
+	set := newAddressSet(0)
+	for i := 0; i < benchmarkSubscribedAddresses; i++ {
+		set.add(testAddress(i))
+	}
+	addresses := benchmarkAddresses()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%100 == 0 {
+				set.add(addresses[i%len(addresses)])
+			} else {
+				set.contains(addresses[i%len(addresses)])
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMapAddressSetContainsWhileAdding(b *testing.B) {
+	set := &mapAddressSet{addresses: make(map[blockchain.Address]struct{})}
+	for i := 0; i < benchmarkSubscribedAddresses; i++ {
+		set.add(testAddress(i))
+	}
+	addresses := benchmarkAddresses()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%100 == 0 {
+				set.add(addresses[i%len(addresses)])
+			} else {
+				set.contains(addresses[i%len(addresses)])
+			}
+			i++
+		}
+	})
+}
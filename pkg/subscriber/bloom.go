@@ -0,0 +1,86 @@
+package subscriber
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// bloomFilter is a bloom filter of address hashes. Bits are set atomically,
+// so filter is tested without locking while addresses are added.
+// Addresses can not be removed from it, it is rebuilt instead.
+type bloomFilter struct {
+	bits []uint64
+	// size is the number of bits, hashes is the number of bits set per address
+	size   uint64
+	hashes uint64
+}
+
+// newBloomFilter creates bloom filter sized for the capacity of addresses with the false positive rate
+func newBloomFilter(capacity int, falsePositiveRate float64) *bloomFilter {
+	n := math.Max(float64(capacity), 1)
+	size := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	words := uint64(math.Ceil(size / 64))
+	hashes := uint64(math.Max(1, math.Round(float64(words*64)/n*math.Ln2)))
+	return &bloomFilter{
+		bits:   make([]uint64, words),
+		size:   words * 64,
+		hashes: hashes,
+	}
+}
+
+// add sets bits of the hash
+func (f *bloomFilter) add(hash uint64) {
+	h1, h2 := splitHash(hash)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		word, mask := &f.bits[bit/64], uint64(1)<<(bit%64)
+		for {
+			old := atomic.LoadUint64(word)
+			if old&mask != 0 || atomic.CompareAndSwapUint64(word, old, old|mask) {
+				break
+			}
+		}
+	}
+}
+
+// test checks if all bits of the hash are set, address which is not added is rejected,
+// but address which is not added can be accepted with the false positive rate
+func (f *bloomFilter) test(hash uint64) bool {
+	h1, h2 := splitHash(hash)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		if atomic.LoadUint64(&f.bits[bit/64])&(uint64(1)<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// splitHash derives two hashes of double hashing from the hash, the second one is odd so it never cycles early
+func splitHash(hash uint64) (uint64, uint64) {
+	// finalizer of splitmix64 spreads bits of the second hash
+	h2 := hash
+	h2 ^= h2 >> 30
+	h2 *= 0xbf58476d1ce4e5b9
+	h2 ^= h2 >> 27
+	h2 *= 0x94d049bb133111eb
+	h2 ^= h2 >> 31
+	return hash, h2 | 1
+}
+
+// hashAddress returns FNV-1a hash of address
+func hashAddress(address blockchain.Address) uint64 {
+	hash := uint64(fnvOffset64)
+	for i := 0; i < len(address); i++ {
+		hash ^= uint64(address[i])
+		hash *= fnvPrime64
+	}
+	return hash
+}
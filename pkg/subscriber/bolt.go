@@ -0,0 +1,84 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+var subscriptionsBucket = []byte("subscriptions")
+
+var _ Storage = (*boltStorage)(nil)
+
+// boltStorage persists subscriptions in bolt database, keyed by tenant id and address,
+// subscriptions of the default tenant are keyed by address only, as they were before tenants were introduced
+type boltStorage struct {
+	db *bbolt.DB
+}
+
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(subscriptionsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStorage{
+		db: db,
+	}, nil
+}
+
+func (s *boltStorage) GetAll(ctx context.Context) ([]*Subscription, error) {
+	subscriptions := make([]*Subscription, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).ForEach(func(key, value []byte) error {
+			var subscription Subscription
+			if err := json.Unmarshal(value, &subscription); err != nil {
+				return err
+			}
+			// subscriptions stored before address was added are identified by the key only
+			subscription.TenantID, subscription.Address = parseStorageKey(string(key))
+			subscriptions = append(subscriptions, &subscription)
+			return nil
+		})
+	})
+	return subscriptions, err
+}
+
+func (s *boltStorage) Save(ctx context.Context, subscription *Subscription) error {
+	value, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).Put([]byte(storageKey(subscription.TenantID, subscription.Address)), value)
+	})
+}
+
+func (s *boltStorage) Delete(ctx context.Context, tenantID string, address blockchain.Address) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).Delete([]byte(storageKey(tenantID, address)))
+	})
+}
+
+// storageKey returns key of subscription of the tenant to the address
+func storageKey(tenantID string, address blockchain.Address) string {
+	if tenantID == "" {
+		return address.String()
+	}
+	return tenantID + "/" + address.String()
+}
+
+// parseStorageKey returns tenant id and address of subscription from its key
+func parseStorageKey(key string) (string, blockchain.Address) {
+	tenantID, address, ok := strings.Cut(key, "/")
+	if !ok {
+		return "", blockchain.Address(key)
+	}
+	return tenantID, blockchain.Address(address)
+}
@@ -0,0 +1,129 @@
+package subscriber
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// ErrInvalidCriteria is returned when subscription criteria are not valid
+var ErrInvalidCriteria = errors.New("invalid subscription criteria")
+
+var methodSelectorRegexp = regexp.MustCompile(`^0x[0-9a-f]{8}$`)
+
+// Direction is the direction of transactions matched by subscription relative to subscribed address
+type Direction string
+
+const (
+	InboundDirection  = Direction("inbound")
+	OutboundDirection = Direction("outbound")
+	BothDirection     = Direction("both")
+)
+
+// Criteria are conditions transaction of subscribed address has to meet to be matched,
+// empty criteria match every transaction of the address
+type Criteria struct {
+	// Direction is inbound, outbound or both, empty direction is both
+	Direction Direction `json:"direction,omitempty"`
+	// Kinds are matched transaction kinds, empty kinds match every kind
+	Kinds []blockchain.TransactionKind `json:"kinds,omitempty"`
+	// MinValue is the minimum value of transaction in wei, as decimal string
+	MinValue string `json:"min_value,omitempty"`
+	// MethodSelectors are hex encoded selectors of called methods, empty selectors match every transaction
+	MethodSelectors []string `json:"method_selectors,omitempty"`
+
+	// minValue is parsed min value
+	minValue *big.Int
+}
+
+// Validate checks if criteria are valid
+func (c Criteria) Validate() error {
+	return c.normalize()
+}
+
+// normalize validates criteria, lower cases method selectors and parses min value
+func (c *Criteria) normalize() error {
+	switch c.Direction {
+	case "", InboundDirection, OutboundDirection, BothDirection:
+	default:
+		return fmt.Errorf("%w: unknown direction %s", ErrInvalidCriteria, c.Direction)
+	}
+	for _, kind := range c.Kinds {
+		if !kind.IsValid() {
+			return fmt.Errorf("%w: unknown kind %s", ErrInvalidCriteria, kind)
+		}
+	}
+	c.minValue = nil
+	if c.MinValue != "" {
+		minValue, ok := new(big.Int).SetString(c.MinValue, 10)
+		if !ok || minValue.Sign() < 0 {
+			return fmt.Errorf("%w: invalid min value %s", ErrInvalidCriteria, c.MinValue)
+		}
+		c.minValue = minValue
+	}
+	selectors := make([]string, 0, len(c.MethodSelectors))
+	for _, selector := range c.MethodSelectors {
+		selector = strings.ToLower(selector)
+		if !methodSelectorRegexp.MatchString(selector) {
+			return fmt.Errorf("%w: invalid method selector %s", ErrInvalidCriteria, selector)
+		}
+		selectors = append(selectors, selector)
+	}
+	if len(selectors) > 0 {
+		c.MethodSelectors = selectors
+	}
+	return nil
+}
+
+// MatchesAll returns true if criteria match every transaction of the address
+func (c *Criteria) MatchesAll() bool {
+	return (c.Direction == "" || c.Direction == BothDirection) &&
+		len(c.Kinds) == 0 && c.MinValue == "" && len(c.MethodSelectors) == 0
+}
+
+// Match checks if transaction of the address meets the criteria,
+// contract creation is inbound for the created contract and outbound for its deployer
+func (c *Criteria) Match(address blockchain.Address, tx *blockchain.Transaction) bool {
+	switch c.Direction {
+	case InboundDirection:
+		if tx.To != address && !(tx.To.IsZero() && tx.From != address) {
+			return false
+		}
+	case OutboundDirection:
+		if tx.From != address {
+			return false
+		}
+	}
+	if len(c.Kinds) > 0 && !containsKind(c.Kinds, tx.Kind()) {
+		return false
+	}
+	if c.minValue != nil && tx.ValueWei().Cmp(c.minValue) < 0 {
+		return false
+	}
+	if len(c.MethodSelectors) > 0 && !containsString(c.MethodSelectors, tx.MethodSelector()) {
+		return false
+	}
+	return true
+}
+
+func containsKind(kinds []blockchain.TransactionKind, kind blockchain.TransactionKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
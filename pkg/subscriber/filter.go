@@ -2,13 +2,18 @@ package subscriber
 
 import (
 	"context"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
 )
 
-// Filter is a filter for addresses.
-// It is used to filter transactions.
-// It could be in future bloom filter.
+// Filter is a filter for transactions of addresses.
+// Addresses which are not subscribed are rejected by bloom filter of subscriber without locking,
+// so filtering a block does not contend with api requests.
 type Filter interface {
-	Test(ctx context.Context, address string) bool
+	// Test tests if transaction of the address (sender or recipient) is matched
+	Test(ctx context.Context, address blockchain.Address, tx *blockchain.Transaction) bool
+	// TestAddress tests if the address is subscribed, criteria of subscriptions apply only to transactions
+	TestAddress(ctx context.Context, address blockchain.Address) bool
 }
 
 var _ Filter = (*filter)(nil)
@@ -23,11 +28,20 @@ func NewFilter(subscriber Subscriber) Filter {
 	}
 }
 
-func (f *filter) Test(ctx context.Context, address string) bool {
-	exists, err := f.subscriber.Test(ctx, address)
+func (f *filter) Test(ctx context.Context, address blockchain.Address, tx *blockchain.Transaction) bool {
+	matched, err := f.subscriber.Match(ctx, address, tx)
+	if err != nil {
+		return false
+	}
+
+	return matched
+}
+
+func (f *filter) TestAddress(ctx context.Context, address blockchain.Address) bool {
+	subscribed, err := f.subscriber.Test(ctx, address)
 	if err != nil {
 		return false
 	}
 
-	return exists
+	return subscribed
 }
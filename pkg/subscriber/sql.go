@@ -0,0 +1,99 @@
+package subscriber
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+)
+
+var _ Storage = (*sqlStorage)(nil)
+
+// sqlStorage persists subscriptions in sql database, keyed by tenant id and address
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+func (s *sqlStorage) GetAll(ctx context.Context) ([]*Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT tenant_id, address, direction, kinds, min_value, method_selectors, created_at FROM subscriptions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*Subscription, 0)
+	for rows.Next() {
+		var kinds, methodSelectors string
+		var createdAt int64
+		var subscription Subscription
+		err := rows.Scan(
+			&subscription.TenantID,
+			&subscription.Address,
+			&subscription.Direction,
+			&kinds,
+			&subscription.MinValue,
+			&methodSelectors,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, kind := range splitList(kinds) {
+			subscription.Kinds = append(subscription.Kinds, blockchain.TransactionKind(kind))
+		}
+		subscription.MethodSelectors = splitList(methodSelectors)
+		if createdAt > 0 {
+			subscription.CreatedAt = time.Unix(0, createdAt).UTC()
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (s *sqlStorage) Save(ctx context.Context, subscription *Subscription) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`
+		INSERT INTO subscriptions (tenant_id, address, direction, kinds, min_value, method_selectors, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tenant_id, address) DO UPDATE SET
+			direction = excluded.direction, kinds = excluded.kinds, min_value = excluded.min_value,
+			method_selectors = excluded.method_selectors, created_at = excluded.created_at`),
+		subscription.TenantID,
+		subscription.Address.String(),
+		subscription.Direction,
+		joinKinds(subscription.Kinds),
+		subscription.MinValue,
+		strings.Join(subscription.MethodSelectors, ","),
+		subscription.CreatedAt.UnixNano(),
+	)
+	return err
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, tenantID string, address blockchain.Address) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM subscriptions WHERE tenant_id = ? AND address = ?`), tenantID, address.String())
+	return err
+}
+
+// joinKinds joins kinds to comma separated list
+func joinKinds(kinds []blockchain.TransactionKind) string {
+	values := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		values = append(values, string(kind))
+	}
+	return strings.Join(values, ",")
+}
+
+// splitList splits comma separated list, empty list is nil
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
@@ -0,0 +1,65 @@
+package subscriber
+
+import (
+	"context"
+	"sync"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+// ReadOnlyStorage is responsible for reading subscriptions
+type ReadOnlyStorage interface {
+	// GetAll returns subscriptions of all tenants
+	GetAll(ctx context.Context) ([]*Subscription, error)
+}
+
+// WriteStorage is responsible for writing subscriptions
+type WriteStorage interface {
+	// Save saves subscription, it replaces subscription of the same tenant to the same address
+	Save(ctx context.Context, subscription *Subscription) error
+	Delete(ctx context.Context, tenantID string, address blockchain.Address) error
+}
+
+// Storage is responsible for reading and writing subscriptions
+type Storage interface {
+	ReadOnlyStorage
+	WriteStorage
+}
+
+var _ Storage = (*inMemoryStorage)(nil)
+
+type inMemoryStorage struct {
+	// subscriptions by storage key of tenant id and address
+	subscriptions map[string]*Subscription
+	mutex         sync.RWMutex
+}
+
+func NewStorage() Storage {
+	return &inMemoryStorage{
+		subscriptions: make(map[string]*Subscription),
+	}
+}
+
+func (s *inMemoryStorage) GetAll(ctx context.Context) ([]*Subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	subscriptions := make([]*Subscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+func (s *inMemoryStorage) Save(ctx context.Context, subscription *Subscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.subscriptions[storageKey(subscription.TenantID, subscription.Address)] = subscription
+	return nil
+}
+
+func (s *inMemoryStorage) Delete(ctx context.Context, tenantID string, address blockchain.Address) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.subscriptions, storageKey(tenantID, address))
+	return nil
+}
@@ -2,65 +2,220 @@ package subscriber
 
 import (
 	"context"
-	"strings"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/blockchain"
+)
+
+var (
+	// ErrNotSubscribed is returned when address is not subscribed
+	ErrNotSubscribed = errors.New("address is not subscribed")
+	// ErrQuotaExceeded is returned when tenant subscribes more addresses than its quota allows
+	ErrQuotaExceeded = errors.New("quota of subscribed addresses exceeded")
 )
 
-// Subscriber is responsible for subscribing, unsubscribing and testing addresses
+// Subscriber is responsible for subscribing, unsubscribing and testing addresses.
+// Subscriptions are owned by tenants, the same address can be subscribed by more tenants,
+// its transactions are matched when they meet criteria of any of its subscriptions.
 type Subscriber interface {
-	// Subscribe subscribes to address
-	Subscribe(context context.Context, address string) error
-	// UnSubscribe unsubscribes from address
-	UnSubscribe(context context.Context, address string) error
-	// Test tests if address is subscribed
-	Test(context context.Context, address string) (bool, error)
+	// Subscribe subscribes tenant to address, criteria of already subscribed address are replaced,
+	// if criteria are nil, subscribed address keeps its criteria and new subscription matches every transaction.
+	// New address is not subscribed if tenant already subscribed maxSubscriptions addresses, 0 is unlimited.
+	Subscribe(context context.Context, tenantID string, address blockchain.Address, criteria *Criteria, maxSubscriptions int) error
+	// UnSubscribe unsubscribes tenant from address
+	UnSubscribe(context context.Context, tenantID string, address blockchain.Address) error
+	// Test tests if address is subscribed by any tenant
+	Test(context context.Context, address blockchain.Address) (bool, error)
+	// Match tests if transaction meets criteria of any subscription of the address
+	Match(context context.Context, address blockchain.Address, tx *blockchain.Transaction) (bool, error)
+	// MatchTenant tests if transaction meets criteria of subscription of the tenant to the address
+	MatchTenant(context context.Context, tenantID string, address blockchain.Address, tx *blockchain.Transaction) (bool, error)
+	// Get returns subscription of the tenant to the address
+	Get(context context.Context, tenantID string, address blockchain.Address) (*Subscription, error)
+	// List returns up to limit subscriptions of the tenant ordered by address, starting after the given address
+	List(context context.Context, tenantID string, after blockchain.Address, limit int) ([]*Subscription, error)
 }
 
 var _ Subscriber = (*subscriber)(nil)
 
-// subscription represents subscription to address
-// now it is just a stub, but in future it could be more complex
-// having more fields and complex logic around it
-type subscription struct {
-	Type  string `json:"type"`
-	Event string `json:"event"`
-	// TODO: add more fields
+// Subscription represents subscription of a tenant to address,
+// only transactions meeting its criteria are matched
+type Subscription struct {
+	TenantID string             `json:"tenant_id,omitempty"`
+	Address  blockchain.Address `json:"address"`
+	Criteria
+	CreatedAt time.Time `json:"created_at"`
 }
 
+// subscriber keeps all subscriptions in memory for fast testing,
+// every change is written through to the storage, so subscriptions survive restarts.
+// Subscribed addresses are also kept in address set, so transactions of addresses which are not subscribed
+// are rejected without taking the lock of subscriptions.
 type subscriber struct {
-	storage map[string]*subscription
+	// subscriptions by address and tenant id
+	subscriptions map[blockchain.Address]map[string]*Subscription
+	addresses     *addressSet
+	// counts are numbers of subscribed addresses by tenant id
+	counts  map[string]int
+	storage Storage
 	mutex   sync.RWMutex
 }
 
-func NewSubscriber() Subscriber {
-	return &subscriber{
-		storage: make(map[string]*subscription),
+// NewSubscriber creates a subscriber and loads existing subscriptions from the storage,
+// false positive rate is the rate of bloom filter of subscribed addresses, default rate is used if it is 0
+func NewSubscriber(ctx context.Context, storage Storage, falsePositiveRate float64) (Subscriber, error) {
+	subscriptions, err := storage.GetAll(ctx)
+	if err != nil {
+		return nil, err
 	}
+	s := &subscriber{
+		subscriptions: make(map[blockchain.Address]map[string]*Subscription),
+		addresses:     newAddressSet(falsePositiveRate),
+		counts:        make(map[string]int),
+		storage:       storage,
+	}
+	for _, subscription := range subscriptions {
+		if err := subscription.normalize(); err != nil {
+			return nil, fmt.Errorf("subscription of address %s: %w", subscription.Address, err)
+		}
+		s.add(subscription)
+	}
+	return s, nil
 }
 
-func (s *subscriber) Subscribe(context context.Context, address string) error {
+func (s *subscriber) Subscribe(context context.Context, tenantID string, address blockchain.Address, criteria *Criteria, maxSubscriptions int) error {
+	if criteria != nil {
+		if err := criteria.normalize(); err != nil {
+			return err
+		}
+	}
+	if address.IsZero() {
+		return blockchain.ErrInvalidAddress
+	}
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	// make sure that address is always lower case
-	lowerCaseAddress := strings.ToLower(address)
-	s.storage[lowerCaseAddress] = &subscription{}
+	existing, exists := s.subscriptions[address][tenantID]
+	if exists && criteria == nil {
+		return nil
+	}
+	if !exists && maxSubscriptions > 0 && s.counts[tenantID] >= maxSubscriptions {
+		return ErrQuotaExceeded
+	}
+	subscription := &Subscription{
+		TenantID:  tenantID,
+		Address:   address,
+		CreatedAt: time.Now().UTC(),
+	}
+	if criteria != nil {
+		subscription.Criteria = *criteria
+	}
+	if exists {
+		// subscription keeps its original creation time
+		subscription.CreatedAt = existing.CreatedAt
+	}
+	if err := s.storage.Save(context, subscription); err != nil {
+		return err
+	}
+	s.add(subscription)
 	return nil
 }
 
-func (s *subscriber) UnSubscribe(context context.Context, address string) error {
+func (s *subscriber) UnSubscribe(context context.Context, tenantID string, address blockchain.Address) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	// make sure that address is always lower case
-	lowerCaseAddress := strings.ToLower(address)
-	delete(s.storage, lowerCaseAddress)
+	if err := s.storage.Delete(context, tenantID, address); err != nil {
+		return err
+	}
+	if _, exists := s.subscriptions[address][tenantID]; exists {
+		s.counts[tenantID]--
+	}
+	delete(s.subscriptions[address], tenantID)
+	if _, exists := s.subscriptions[address]; exists && len(s.subscriptions[address]) == 0 {
+		delete(s.subscriptions, address)
+		s.addresses.remove(address)
+	}
 	return nil
 }
 
-func (s *subscriber) Test(context context.Context, address string) (bool, error) {
+func (s *subscriber) Test(context context.Context, address blockchain.Address) (bool, error) {
+	return s.addresses.contains(address), nil
+}
+
+func (s *subscriber) Match(context context.Context, address blockchain.Address, tx *blockchain.Transaction) (bool, error) {
+	if !s.addresses.contains(address) {
+		return false, nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, subscription := range s.subscriptions[address] {
+		if subscription.Match(address, tx) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *subscriber) MatchTenant(context context.Context, tenantID string, address blockchain.Address, tx *blockchain.Transaction) (bool, error) {
+	if !s.addresses.contains(address) {
+		return false, nil
+	}
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	// make sure that address is always lower case
-	lowerCaseAddress := strings.ToLower(address)
-	_, exists := s.storage[lowerCaseAddress]
-	return exists, nil
+	subscription, exists := s.subscriptions[address][tenantID]
+	if !exists {
+		return false, nil
+	}
+	return subscription.Match(address, tx), nil
+}
+
+func (s *subscriber) Get(context context.Context, tenantID string, address blockchain.Address) (*Subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	subscription, exists := s.subscriptions[address][tenantID]
+	if !exists {
+		return nil, ErrNotSubscribed
+	}
+	copied := *subscription
+	return &copied, nil
+}
+
+func (s *subscriber) List(context context.Context, tenantID string, after blockchain.Address, limit int) ([]*Subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	addresses := make([]blockchain.Address, 0)
+	for address, tenantSubscriptions := range s.subscriptions {
+		if _, exists := tenantSubscriptions[tenantID]; exists && address > after {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i] < addresses[j]
+	})
+	if limit > 0 && len(addresses) > limit {
+		addresses = addresses[:limit]
+	}
+	subscriptions := make([]*Subscription, 0, len(addresses))
+	for _, address := range addresses {
+		copied := *s.subscriptions[address][tenantID]
+		subscriptions = append(subscriptions, &copied)
+	}
+	return subscriptions, nil
+}
+
+// add adds subscription to the index, caller holds the lock
+func (s *subscriber) add(subscription *Subscription) {
+	tenantSubscriptions, exists := s.subscriptions[subscription.Address]
+	if !exists {
+		tenantSubscriptions = make(map[string]*Subscription)
+		s.subscriptions[subscription.Address] = tenantSubscriptions
+		s.addresses.add(subscription.Address)
+	}
+	if _, exists := tenantSubscriptions[subscription.TenantID]; !exists {
+		s.counts[subscription.TenantID]++
+	}
+	tenantSubscriptions[subscription.TenantID] = subscription
 }
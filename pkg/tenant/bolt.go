@@ -0,0 +1,61 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.etcd.io/bbolt"
+)
+
+var keysBucket = []byte("api_keys")
+
+var _ Storage = (*boltStorage)(nil)
+
+// boltStorage persists api keys in bolt database keyed by id
+type boltStorage struct {
+	db *bbolt.DB
+}
+
+func NewBoltStorage(db *bbolt.DB) (Storage, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(keysBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStorage{
+		db: db,
+	}, nil
+}
+
+func (s *boltStorage) GetKeys(ctx context.Context) ([]*Key, error) {
+	keys := make([]*Key, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(keysBucket).ForEach(func(_, value []byte) error {
+			var key Key
+			if err := json.Unmarshal(value, &key); err != nil {
+				return err
+			}
+			keys = append(keys, &key)
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (s *boltStorage) SaveKey(ctx context.Context, key *Key) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(keysBucket).Put([]byte(key.ID), value)
+	})
+}
+
+func (s *boltStorage) DeleteKey(ctx context.Context, id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(keysBucket).Delete([]byte(id))
+	})
+}
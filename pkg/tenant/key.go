@@ -0,0 +1,120 @@
+package tenant
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownKey is returned when api key does not belong to any tenant
+	ErrUnknownKey = errors.New("unknown api key")
+	// ErrInvalidKeys is returned when configured api keys can not be parsed
+	ErrInvalidKeys = errors.New("invalid api keys")
+	// ErrInvalidKey is returned when api key has invalid tenant id or limits
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrKeyNotFound is returned when api key with given id does not exist
+	ErrKeyNotFound = errors.New("api key not found")
+	// ErrStaticKey is returned when api key configured at startup is deleted
+	ErrStaticKey = errors.New("api key is configured at startup and can not be deleted")
+)
+
+var idRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Key is api key of a tenant, its limits are applied to every request made with it
+type Key struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id"`
+	// Secret is passed in X-API-Key header, it is also the secret of HMAC signed requests.
+	// It is returned only when key is created.
+	Secret string `json:"secret,omitempty"`
+	// RateLimit is the number of requests per second, default rate limit is used if it is 0
+	RateLimit float64 `json:"rate_limit,omitempty"`
+	// Burst is the number of requests allowed at once, default burst is used if it is 0
+	Burst int `json:"burst,omitempty"`
+	// MaxSubscriptions is the quota of addresses subscribed by tenant of the key, default quota is used if it is 0
+	MaxSubscriptions int `json:"max_subscriptions,omitempty"`
+	// SignatureRequired rejects requests which are not signed with secret of the key
+	SignatureRequired bool `json:"signature_required"`
+	// Static keys are configured at startup, they are not persisted and can not be deleted
+	Static    bool      `json:"static"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate validates tenant id and limits of the key
+func (k *Key) Validate() error {
+	if !idRegexp.MatchString(k.TenantID) {
+		return fmt.Errorf("%w: tenant id has to consist of letters, digits, dash and underscore", ErrInvalidKey)
+	}
+	if k.RateLimit < 0 || k.Burst < 0 || k.MaxSubscriptions < 0 {
+		return fmt.Errorf("%w: limits can not be negative", ErrInvalidKey)
+	}
+	return nil
+}
+
+// Redacted returns copy of the key without its secret
+func (k *Key) Redacted() *Key {
+	copied := *k
+	copied.Secret = ""
+	return &copied
+}
+
+// ParseKeys parses comma separated list of tenant:key pairs configured at startup,
+// tenant id consists of letters, digits, dash and underscore, a tenant can have more keys
+func ParseKeys(list string) ([]*Key, error) {
+	keys := make([]*Key, 0)
+	secrets := make(map[string]bool)
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || secret == "" || !idRegexp.MatchString(id) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKeys, pair)
+		}
+		if secrets[secret] {
+			return nil, fmt.Errorf("%w: duplicate key of tenant %s", ErrInvalidKeys, id)
+		}
+		secrets[secret] = true
+		keys = append(keys, &Key{
+			ID:        staticKeyID(secret),
+			TenantID:  id,
+			Secret:    secret,
+			Static:    true,
+			CreatedAt: time.Now().UTC(),
+		})
+	}
+	return keys, nil
+}
+
+// staticKeyID returns id of the key configured at startup, it is derived from the secret,
+// so the key keeps its id after restart without revealing the secret
+func staticKeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return "static_" + hex.EncodeToString(sum[:6])
+}
+
+// Sign returns hex encoded HMAC-SHA256 signature of request sent at the timestamp,
+// signed message is "<timestamp>.<method>.<request uri>.<body>"
+func Sign(secret string, timestamp string, method string, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + method + "." + requestURI + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSecret returns random hex encoded value of the given number of bytes
+func newSecret(size int) (string, error) {
+	value := make([]byte, size)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(value), nil
+}
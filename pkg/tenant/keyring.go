@@ -0,0 +1,162 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	keyIDSize     = 8
+	keySecretSize = 24
+)
+
+// Keyring authenticates callers by api keys of tenants and manages the keys.
+// Keys configured at startup are kept only in memory, created keys are persisted in the storage.
+type Keyring interface {
+	// Enabled checks if any api key exists, without keys every caller is the default tenant
+	Enabled() bool
+	// Authenticate returns api key with the secret
+	Authenticate(ctx context.Context, secret string) (*Key, error)
+	// GetKey returns api key with the id, it is used to verify signed requests
+	GetKey(ctx context.Context, id string) (*Key, error)
+	// ListKeys returns all api keys ordered by creation time, secrets are not returned
+	ListKeys(ctx context.Context) []*Key
+	// CreateKey creates api key with generated id and secret, created key is returned with its secret
+	CreateKey(ctx context.Context, key *Key) (*Key, error)
+	// DeleteKey deletes api key, requests with it are rejected from now on
+	DeleteKey(ctx context.Context, id string) error
+}
+
+var _ Keyring = (*keyring)(nil)
+
+// keyring keeps all keys in memory for fast authentication,
+// every change is written through to the storage, so keys survive restarts
+type keyring struct {
+	// keys by id and by secret
+	keys    map[string]*Key
+	secrets map[string]*Key
+	storage Storage
+	mutex   sync.RWMutex
+}
+
+// NewKeyring creates a keyring with static keys and loads persisted keys from the storage
+func NewKeyring(ctx context.Context, storage Storage, static []*Key) (Keyring, error) {
+	persisted, err := storage.GetKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	k := &keyring{
+		keys:    make(map[string]*Key),
+		secrets: make(map[string]*Key),
+		storage: storage,
+	}
+	for _, key := range append(static, persisted...) {
+		if _, exists := k.secrets[key.Secret]; exists {
+			return nil, fmt.Errorf("%w: duplicate key of tenant %s", ErrInvalidKeys, key.TenantID)
+		}
+		k.add(key)
+	}
+	return k, nil
+}
+
+func (k *keyring) Enabled() bool {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	return len(k.keys) > 0
+}
+
+func (k *keyring) Authenticate(ctx context.Context, secret string) (*Key, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	key, exists := k.secrets[secret]
+	if !exists || secret == "" {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (k *keyring) GetKey(ctx context.Context, id string) (*Key, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	key, exists := k.keys[id]
+	if !exists {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (k *keyring) ListKeys(ctx context.Context) []*Key {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key.Redacted())
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys
+}
+
+func (k *keyring) CreateKey(ctx context.Context, key *Key) (*Key, error) {
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+	id, err := newSecret(keyIDSize)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret(keySecretSize)
+	if err != nil {
+		return nil, err
+	}
+	created := &Key{
+		ID:                "key_" + id,
+		TenantID:          key.TenantID,
+		Secret:            secret,
+		RateLimit:         key.RateLimit,
+		Burst:             key.Burst,
+		MaxSubscriptions:  key.MaxSubscriptions,
+		SignatureRequired: key.SignatureRequired,
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if err := k.storage.SaveKey(ctx, created); err != nil {
+		return nil, err
+	}
+	k.add(created)
+	copied := *created
+	return &copied, nil
+}
+
+func (k *keyring) DeleteKey(ctx context.Context, id string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	key, exists := k.keys[id]
+	if !exists {
+		return ErrKeyNotFound
+	}
+	if key.Static {
+		return ErrStaticKey
+	}
+	if err := k.storage.DeleteKey(ctx, id); err != nil {
+		return err
+	}
+	delete(k.keys, id)
+	delete(k.secrets, key.Secret)
+	return nil
+}
+
+// add adds key to the index, caller holds the lock
+func (k *keyring) add(key *Key) {
+	k.keys[key.ID] = key
+	k.secrets[key.Secret] = key
+}
@@ -0,0 +1,82 @@
+package tenant
+
+import (
+	"context"
+	"time"
+
+	"github.com/veljkomatic/be-homework/pkg/storage/database"
+)
+
+var _ Storage = (*sqlStorage)(nil)
+
+// sqlStorage persists api keys in sql database keyed by id
+type sqlStorage struct {
+	db *database.DB
+}
+
+func NewSQLStorage(db *database.DB) Storage {
+	return &sqlStorage{
+		db: db,
+	}
+}
+
+func (s *sqlStorage) GetKeys(ctx context.Context) ([]*Key, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, secret, rate_limit, burst, max_subscriptions, signature_required, created_at FROM api_keys`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*Key, 0)
+	for rows.Next() {
+		var signatureRequired int
+		var createdAt int64
+		var key Key
+		err := rows.Scan(
+			&key.ID,
+			&key.TenantID,
+			&key.Secret,
+			&key.RateLimit,
+			&key.Burst,
+			&key.MaxSubscriptions,
+			&signatureRequired,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		key.SignatureRequired = signatureRequired != 0
+		key.CreatedAt = time.Unix(0, createdAt).UTC()
+		keys = append(keys, &key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *sqlStorage) SaveKey(ctx context.Context, key *Key) error {
+	var signatureRequired int
+	if key.SignatureRequired {
+		signatureRequired = 1
+	}
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`
+		INSERT INTO api_keys (id, tenant_id, secret, rate_limit, burst, max_subscriptions, signature_required, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			tenant_id = excluded.tenant_id, secret = excluded.secret, rate_limit = excluded.rate_limit, burst = excluded.burst,
+			max_subscriptions = excluded.max_subscriptions, signature_required = excluded.signature_required`),
+		key.ID,
+		key.TenantID,
+		key.Secret,
+		key.RateLimit,
+		key.Burst,
+		key.MaxSubscriptions,
+		signatureRequired,
+		key.CreatedAt.UnixNano(),
+	)
+	return err
+}
+
+func (s *sqlStorage) DeleteKey(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`DELETE FROM api_keys WHERE id = ?`), id)
+	return err
+}
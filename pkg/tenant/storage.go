@@ -0,0 +1,60 @@
+package tenant
+
+import (
+	"context"
+	"sync"
+)
+
+// ReadOnlyStorage is responsible for reading api keys
+type ReadOnlyStorage interface {
+	GetKeys(ctx context.Context) ([]*Key, error)
+}
+
+// WriteStorage is responsible for writing api keys
+type WriteStorage interface {
+	SaveKey(ctx context.Context, key *Key) error
+	DeleteKey(ctx context.Context, id string) error
+}
+
+// Storage is responsible for reading and writing api keys
+type Storage interface {
+	ReadOnlyStorage
+	WriteStorage
+}
+
+var _ Storage = (*inMemoryStorage)(nil)
+
+type inMemoryStorage struct {
+	keys  map[string]*Key
+	mutex sync.RWMutex
+}
+
+func NewStorage() Storage {
+	return &inMemoryStorage{
+		keys: make(map[string]*Key),
+	}
+}
+
+func (s *inMemoryStorage) GetKeys(ctx context.Context) ([]*Key, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *inMemoryStorage) SaveKey(ctx context.Context, key *Key) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *inMemoryStorage) DeleteKey(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.keys, id)
+	return nil
+}
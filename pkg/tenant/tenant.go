@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+)
+
+// DefaultID is the id of the tenant owning subscriptions when api keys are not configured,
+// subscriptions created before tenants were introduced belong to it
+const DefaultID = ""
+
+// Tenant owns subscriptions, it sees only transactions of addresses it subscribed to
+type Tenant struct {
+	ID string `json:"id"`
+	// MaxSubscriptions is the quota of addresses subscribed by the caller, set from its api key, 0 is unlimited
+	MaxSubscriptions int `json:"max_subscriptions,omitempty"`
+}
+
+type contextKey struct{}
+
+// NewContext returns context carrying the tenant of the caller
+func NewContext(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenant)
+}
+
+// FromContext returns the tenant of the caller, it is nil if context does not carry it
+func FromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(contextKey{}).(*Tenant)
+	return tenant
+}
+
+// IDFromContext returns id of the tenant of the caller, default id is returned if context does not carry it
+func IDFromContext(ctx context.Context) string {
+	if tenant := FromContext(ctx); tenant != nil {
+		return tenant.ID
+	}
+	return DefaultID
+}